@@ -0,0 +1,220 @@
+// Package check provides the connectivity diagnostics behind the check subcommand: each
+// step from resolving the controller to reading every data type, reported on its own so
+// a failure names the step that failed.
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// Step statuses.
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Step names, in the order Run takes them.
+const (
+	StepResolve       = "resolve"
+	StepConnect       = "connect"
+	StepTLS           = "tls"
+	StepAuthorization = "authorization"
+)
+
+// defaultPort is the port the SDK reaches a controller given without one on.
+const defaultPort = "443"
+
+// Step is the outcome of one connectivity step.
+type Step struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Detail  string
+}
+
+// Certificate is one certificate of the chain the controller presented.
+type Certificate struct {
+	Subject  string
+	Issuer   string
+	NotAfter time.Time
+}
+
+// Report is the outcome of a check.
+type Report struct {
+	Steps        []Step
+	Certificates []Certificate
+	// DataTypes is nil when a step before them failed, and empty when no enabled
+	// module reads a data type.
+	DataTypes []wnc.DataTypeCheck
+}
+
+// Failed reports whether a step or a data type failed. A warning does not fail it.
+func (r *Report) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Status == StatusFail {
+			return true
+		}
+	}
+	for _, result := range r.DataTypes {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Run checks the controller step by step: it resolves the name, connects, inspects the
+// TLS handshake and the certificate chain, checks the access token, and then reads each
+// data type the enabled modules need once. A step that fails skips the steps after it,
+// since each depends on the one before.
+func Run(ctx context.Context, cfg *config.Config) *Report {
+	report := &Report{}
+	host, port := splitController(cfg.WNC.Controller)
+
+	conn, ok := report.connect(ctx, cfg.WNC, host, port)
+	if !ok {
+		report.skip(StepTLS, StepAuthorization)
+		return report
+	}
+	ok = report.handshake(ctx, cfg.WNC, conn, host)
+	_ = conn.Close()
+	if !ok {
+		report.skip(StepAuthorization)
+		return report
+	}
+
+	// The check reads into a data source of its own, which restores nothing from the
+	// snapshot file and publishes nothing. It reads what the exporter would, so a data
+	// type only families the metric filter withholds read is not checked.
+	wncCfg := cfg.WNC
+	wncCfg.SnapshotFile = ""
+	checker, isChecker := wnc.NewDataSourceFor(wncCfg, collector.RequiredDataTypes(cfg)).(wnc.Checker)
+	if !isChecker {
+		report.add(StepAuthorization, StatusFail, 0, "WNC data source cannot be checked")
+		return report
+	}
+
+	start := time.Now()
+	code, err := checker.CheckAuthorization(ctx)
+	if err != nil {
+		report.add(StepAuthorization, StatusFail, time.Since(start), fmt.Sprintf("%v (%s)", err, code))
+		return report
+	}
+	report.add(StepAuthorization, StatusOK, time.Since(start), "token accepted ("+code+")")
+
+	report.DataTypes = checker.CheckDataTypes(ctx)
+	return report
+}
+
+// connect resolves the controller and opens a TCP connection to it.
+func (r *Report) connect(ctx context.Context, cfg config.WNC, host, port string) (net.Conn, bool) {
+	resolveCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupHost(resolveCtx, host)
+	if err != nil {
+		r.add(StepResolve, StatusFail, time.Since(start), err.Error())
+		r.skip(StepConnect)
+		return nil, false
+	}
+	r.add(StepResolve, StatusOK, time.Since(start), host+": "+strings.Join(addrs, ", "))
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	start = time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		r.add(StepConnect, StatusFail, time.Since(start), err.Error())
+		return nil, false
+	}
+	r.add(StepConnect, StatusOK, time.Since(start), conn.RemoteAddr().String())
+	return conn, true
+}
+
+// handshake runs the TLS handshake and verifies the chain the controller presented
+// against the system roots. The handshake itself never verifies, so the chain can be
+// reported whatever its state; a chain that fails verification fails the step unless
+// --wnc.tls-skip-verify tells the exporter to accept it, when it only warns.
+func (r *Report) handshake(ctx context.Context, cfg config.WNC, conn net.Conn, host string) bool {
+	handshakeCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true, //nolint:gosec // The chain is verified below, to report it either way.
+	})
+	start := time.Now()
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		r.add(StepTLS, StatusFail, time.Since(start), err.Error())
+		return false
+	}
+	latency := time.Since(start)
+
+	state := tlsConn.ConnectionState()
+	for _, cert := range state.PeerCertificates {
+		r.Certificates = append(r.Certificates, Certificate{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+		})
+	}
+	session := tls.VersionName(state.Version) + ", " + tls.CipherSuiteName(state.CipherSuite)
+
+	if err := verifyChain(state.PeerCertificates, host); err != nil {
+		if cfg.TLSSkipVerify {
+			r.add(StepTLS, StatusWarn, latency, session+", chain not verified (--wnc.tls-skip-verify): "+err.Error())
+			return true
+		}
+		r.add(StepTLS, StatusFail, latency, session+", chain not verified: "+err.Error())
+		return false
+	}
+	r.add(StepTLS, StatusOK, latency, session+", chain verified for "+host)
+	return true
+}
+
+// verifyChain verifies the presented chain for host against the system roots.
+func verifyChain(certs []*x509.Certificate, host string) error {
+	if len(certs) == 0 {
+		return errors.New("controller presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	return err
+}
+
+// splitController returns the host and port of a --wnc.controller value, which names a
+// port only when the controller listens on another than the HTTPS default.
+func splitController(controller string) (host, port string) {
+	if host, port, err := net.SplitHostPort(controller); err == nil {
+		return host, port
+	}
+	return strings.Trim(controller, "[]"), defaultPort
+}
+
+func (r *Report) add(name, status string, latency time.Duration, detail string) {
+	r.Steps = append(r.Steps, Step{Name: name, Status: status, Latency: latency, Detail: detail})
+}
+
+// skip records steps a failure before them left untried.
+func (r *Report) skip(names ...string) {
+	for _, name := range names {
+		r.add(name, StatusSkip, 0, "")
+	}
+}
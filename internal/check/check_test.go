@@ -0,0 +1,141 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// newController stands a TLS server in for the controller. It answers the boot time
+// read the token check makes with status, and every other read with dataStatus.
+func newController(t *testing.T, status, dataStatus int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/boot-time") {
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				_, _ = w.Write([]byte(`{"Cisco-IOS-XE-device-hardware-oper:boot-time":"2026-10-01T00:00:00+00:00"}`))
+			}
+			return
+		}
+		w.WriteHeader(dataStatus)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(t *testing.T, server *httptest.Server, skipVerify bool, modules config.Collectors) *config.Config {
+	t.Helper()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &config.Config{
+		WNC: config.WNC{
+			Controller:    target.Host,
+			AccessToken:   "test-token",
+			Timeout:       5 * time.Second,
+			CacheTTL:      5 * time.Second,
+			TLSSkipVerify: skipVerify,
+			RetryAttempts: 1,
+		},
+		Collectors: modules,
+	}
+}
+
+func statuses(report *Report) map[string]string {
+	out := make(map[string]string, len(report.Steps))
+	for _, step := range report.Steps {
+		out[step.Name] = step.Status
+	}
+	return out
+}
+
+// TestRun_SelfSignedController covers the common lab setup: the chain of a self-signed
+// controller only warns when --wnc.tls-skip-verify accepts it, and fails otherwise,
+// leaving the steps after it untried.
+func TestRun_SelfSignedController(t *testing.T) {
+	t.Parallel()
+
+	server := newController(t, http.StatusOK, http.StatusOK)
+
+	report := Run(context.Background(), testConfig(t, server, true, config.Collectors{}))
+	got := statuses(report)
+	want := map[string]string{
+		StepResolve: StatusOK, StepConnect: StatusOK, StepTLS: StatusWarn, StepAuthorization: StatusOK,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("step %s = %s, want %s", name, got[name], status)
+		}
+	}
+	if report.Failed() {
+		t.Error("Failed() = true with every step passing or warning, want false")
+	}
+	if len(report.Certificates) == 0 {
+		t.Error("Certificates is empty, want the chain the controller presented")
+	}
+
+	report = Run(context.Background(), testConfig(t, server, false, config.Collectors{}))
+	got = statuses(report)
+	if got[StepTLS] != StatusFail || got[StepAuthorization] != StatusSkip {
+		t.Errorf("steps = %v, want tls failed and authorization skipped", got)
+	}
+	if !report.Failed() {
+		t.Error("Failed() = false with an unverified chain, want true")
+	}
+}
+
+// TestRun_RejectedToken stops at the token: reading data types with a token the
+// controller refuses would only repeat the refusal once per data type.
+func TestRun_RejectedToken(t *testing.T) {
+	t.Parallel()
+
+	server := newController(t, http.StatusUnauthorized, http.StatusUnauthorized)
+	report := Run(context.Background(), testConfig(t, server, true,
+		config.Collectors{AP: config.APCollectorModules{General: true}}))
+
+	if got := statuses(report)[StepAuthorization]; got != StatusFail {
+		t.Errorf("authorization = %s, want fail", got)
+	}
+	if report.DataTypes != nil {
+		t.Errorf("DataTypes = %v, want none read after a refused token", report.DataTypes)
+	}
+	if !report.Failed() {
+		t.Error("Failed() = false with a refused token, want true")
+	}
+}
+
+// TestRun_FailedDataType fails the check for a data type that failed, and prints it
+// with the status the controller answered.
+func TestRun_FailedDataType(t *testing.T) {
+	t.Parallel()
+
+	server := newController(t, http.StatusOK, http.StatusInternalServerError)
+	report := Run(context.Background(), testConfig(t, server, true,
+		config.Collectors{AP: config.APCollectorModules{General: true}}))
+
+	if len(report.DataTypes) == 0 {
+		t.Fatal("DataTypes is empty, want the data types the AP general module reads")
+	}
+	if !report.Failed() {
+		t.Error("Failed() = false with every data type failing, want true")
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "ap_capwap_data") || !strings.Contains(buf.String(), " 500 ") {
+		t.Errorf("Write() output lacks the failed data type and its status:\n%s", buf.String())
+	}
+}
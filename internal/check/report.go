@@ -0,0 +1,71 @@
+// Package check provides the connectivity diagnostics behind the check subcommand: each
+// step from resolving the controller to reading every data type, reported on its own so
+// a failure names the step that failed.
+// This file holds the table the report is printed as.
+package check
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Write prints the report as tables: the steps, the certificate chain, and one row
+// per data type.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "STEP\tSTATUS\tLATENCY\tDETAIL")
+	for _, step := range r.Steps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", step.Name, step.Status, formatLatency(step.Latency), step.Detail)
+	}
+
+	if len(r.Certificates) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "CERTIFICATE\tSUBJECT\tISSUER\tNOT AFTER")
+		for i, cert := range r.Certificates {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i, cert.Subject, cert.Issuer, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+	}
+
+	switch {
+	case r.DataTypes == nil:
+	case len(r.DataTypes) == 0:
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "No enabled collector module reads a data type.")
+	default:
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "DATA TYPE\tSTATUS\tCODE\tITEMS\tLATENCY\tWITH-DEFAULTS\tERROR")
+		for _, result := range r.DataTypes {
+			status, items, errText := StatusOK, strconv.Itoa(result.Items), ""
+			if result.Err != nil {
+				// An error can quote a response body, which would break the row.
+				status, items, errText = StatusFail, "-", strings.Join(strings.Fields(result.Err.Error()), " ")
+			}
+			fallback := "-"
+			if result.DefaultsFallback {
+				fallback = "fallback"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.Name, status, result.Code, items,
+				formatLatency(result.Latency), fallback, errText)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// formatLatency rounds a latency to the millisecond, or the microsecond below one, and
+// marks a step never tried.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
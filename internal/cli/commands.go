@@ -0,0 +1,274 @@
+// Package cli provides the CLI implementation.
+// This file holds the subcommands, which run once against the controller and exit
+// instead of serving.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/check"
+	"github.com/umatare5/cisco-wnc-exporter/internal/collect"
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/inventory"
+	"github.com/umatare5/cisco-wnc-exporter/internal/log"
+)
+
+// registerCommands defines and returns the subcommands. Each reads the global flags, so
+// it runs with the same controller, credentials and modules as the exporter would.
+func registerCommands() []*cli.Command {
+	commands := []*cli.Command{
+		newCollectCommand(),
+		newCheckCommand(),
+		newMetricsCommand(),
+	}
+	for _, query := range inventory.Queries {
+		commands = append(commands, newInventoryCommand(query))
+	}
+	return commands
+}
+
+// newCollectCommand creates the collect subcommand.
+func newCollectCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "collect",
+		Usage: "Refresh the WNC data once and write the metrics instead of serving them",
+		Flags: registerCollectFlags(),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			format := cmd.String("format")
+			if !slices.Contains(collect.Formats, format) {
+				slog.Error("Configuration parsing failed",
+					"error", fmt.Sprintf("invalid output format %q (valid: %s)", format,
+						strings.Join(collect.Formats, ", ")))
+				return errors.New("configuration error")
+			}
+
+			cfg, err := parseCommandConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			result, err := collect.Gather(ctx, cfg, getVersion())
+			if err != nil {
+				slog.Error("Collection failed", "error", err)
+				return errors.New("collection error")
+			}
+
+			if output := cmd.String("output"); output != "" {
+				err = collect.WriteFile(output, result, format)
+			} else {
+				err = collect.Write(os.Stdout, result, format)
+			}
+			if err != nil {
+				slog.Error("Writing metrics failed", "error", err)
+				return errors.New("output error")
+			}
+			return nil
+		},
+	}
+}
+
+// newCheckCommand creates the check subcommand.
+func newCheckCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Check the connection, TLS, token and every data type the enabled modules read",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfg, err := parseCommandConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			report := check.Run(ctx, cfg)
+			if err := report.Write(os.Stdout); err != nil {
+				slog.Error("Writing check report failed", "error", err)
+				return errors.New("output error")
+			}
+			if report.Failed() {
+				return errors.New("check failed")
+			}
+			return nil
+		},
+	}
+}
+
+// newMetricsCommand creates the metrics subcommand. It lists what the collectors would
+// register without reaching the controller, so it needs no controller or token.
+func newMetricsCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "metrics",
+		Usage:  "List the metrics the enabled modules publish, with type, labels, module and data type",
+		Flags:  registerMetricsFlags(),
+		Before: relaxWNCFlags,
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			format := cmd.String("format")
+			if !slices.Contains(collector.CatalogFormats, format) {
+				slog.Error("Configuration parsing failed",
+					"error", fmt.Sprintf("invalid output format %q (valid: %s)", format,
+						strings.Join(collector.CatalogFormats, ", ")))
+				return errors.New("configuration error")
+			}
+
+			cfg, err := config.ParseOffline(cmd)
+			if err != nil {
+				slog.Error("Configuration parsing failed", "error", err)
+				return errors.New("configuration error")
+			}
+			slog.SetDefault(log.SetupTo(os.Stderr, cfg.Log))
+			if cmd.Bool("all") {
+				collector.EnableAllModules(&cfg.Collectors)
+			}
+
+			entries, err := collector.Catalog(cfg)
+			if err != nil {
+				slog.Error("Building metric catalog failed", "error", err)
+				return errors.New("catalog error")
+			}
+			if err := collector.WriteCatalog(os.Stdout, entries, format); err != nil {
+				slog.Error("Writing metric catalog failed", "error", err)
+				return errors.New("output error")
+			}
+			return nil
+		},
+	}
+}
+
+// newInventoryCommand creates the subcommand printing an inventory query. The query
+// reads the data types it needs whichever modules are enabled.
+func newInventoryCommand(query inventory.Query) *cli.Command {
+	return &cli.Command{
+		Name:  query.Name,
+		Usage: query.Usage,
+		Flags: registerInventoryFlags(query),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			format := cmd.String("format")
+			if !slices.Contains(inventory.Formats, format) {
+				slog.Error("Configuration parsing failed",
+					"error", fmt.Sprintf("invalid output format %q (valid: %s)", format,
+						strings.Join(inventory.Formats, ", ")))
+				return errors.New("configuration error")
+			}
+			filters, err := inventory.ParseFilters(cmd.String("filter"))
+			if err != nil {
+				slog.Error("Configuration parsing failed", "error", err)
+				return errors.New("configuration error")
+			}
+
+			cfg, err := parseCommandConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			table, err := query.Run(ctx, cfg.WNC)
+			if err != nil {
+				slog.Error("Query failed", "error", err)
+				return errors.New("query error")
+			}
+			if err := table.Filter(filters); err != nil {
+				slog.Error("Filtering failed", "error", err)
+				return errors.New("configuration error")
+			}
+			if err := table.Sort(cmd.String("sort")); err != nil {
+				slog.Error("Sorting failed", "error", err)
+				return errors.New("configuration error")
+			}
+
+			if err := table.Write(os.Stdout, format); err != nil {
+				slog.Error("Writing query result failed", "error", err)
+				return errors.New("output error")
+			}
+			return nil
+		},
+	}
+}
+
+// registerCollectFlags defines the flags of the collect subcommand.
+func registerCollectFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format (" + strings.Join(collect.Formats, ", ") + ")",
+			Value: collect.FormatText,
+			Local: true,
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "File the metrics are written to, replaced atomically (empty writes to stdout)",
+			Local: true,
+		},
+	}
+}
+
+// registerMetricsFlags defines the flags of the metrics subcommand.
+func registerMetricsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format (" + strings.Join(collector.CatalogFormats, ", ") + ")",
+			Value: collector.CatalogFormatMarkdown,
+			Local: true,
+		},
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "List the metrics of every module, not only the enabled ones",
+			Local: true,
+		},
+	}
+}
+
+// registerInventoryFlags defines the flags of an inventory subcommand.
+func registerInventoryFlags(query inventory.Query) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format (" + strings.Join(inventory.Formats, ", ") + ")",
+			Value: inventory.FormatTable,
+			Local: true,
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "Comma-separated column=pattern conditions a row has to meet, with shell-style wildcards",
+			Local: true,
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "Column the rows are sorted by, descending when prefixed with -",
+			Value: query.DefaultSort,
+			Local: true,
+		},
+	}
+}
+
+// relaxWNCFlags lifts the required mark from the controller and the token on the root
+// command. Before runs ahead of the required flag check, so only a subcommand that never
+// reaches the controller, and so validates with config.ParseOffline, should use it.
+func relaxWNCFlags(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	for _, flag := range cmd.Root().Flags {
+		if stringFlag, ok := flag.(*cli.StringFlag); ok &&
+			(stringFlag.Name == "wnc.controller" || stringFlag.Name == "wnc.access-token") {
+			stringFlag.Required = false
+		}
+	}
+	return ctx, nil
+}
+
+// parseCommandConfig parses and validates the global flags for a subcommand and logs to
+// stderr, since stdout carries the subcommand's output.
+func parseCommandConfig(cmd *cli.Command) (*config.Config, error) {
+	cfg, err := config.Parse(cmd)
+	if err != nil {
+		slog.Error("Configuration parsing failed", "error", err)
+		return nil, errors.New("configuration error")
+	}
+
+	slog.SetDefault(log.SetupTo(os.Stderr, cfg.Log))
+	return cfg, nil
+}
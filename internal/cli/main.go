@@ -6,21 +6,29 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
 	"github.com/umatare5/cisco-wnc-exporter/internal/log"
 	"github.com/umatare5/cisco-wnc-exporter/internal/server"
+	"github.com/umatare5/cisco-wnc-exporter/internal/tracing"
 )
 
+// tracingFlushTimeout bounds the export of the spans still buffered at exit.
+const tracingFlushTimeout = 5 * time.Second
+
 // NewApp creates a new CLI application.
 func NewApp() *cli.Command {
 	cmd := &cli.Command{
-		Name:    "cisco-wnc-exporter",
-		Usage:   "Prometheus exporter for Cisco WNC",
-		Version: getVersion(),
-		Flags:   registerFlags(),
+		Name:     "cisco-wnc-exporter",
+		Usage:    "Prometheus exporter for Cisco WNC",
+		Version:  getVersion(),
+		Flags:    registerFlags(),
+		Commands: registerCommands(),
+		// A metric pattern may hold a comma, as in {1,3}, so a repeated flag is never split.
+		DisableSliceFlagSeparator: true,
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			cfg, err := config.Parse(cmd)
 			if err != nil {
@@ -35,6 +43,21 @@ func NewApp() *cli.Command {
 				return nil
 			}
 
+			shutdown, err := tracing.Setup(ctx, cfg.Tracing, cfg.WNC.Controller, getVersion())
+			if err != nil {
+				slog.Error("Tracing setup failed", "error", err)
+				return errors.New("tracing error")
+			}
+			defer func() {
+				// The server has stopped by now, so flushing the last spans gets its
+				// own deadline rather than the cancelled context it ran under.
+				flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+				defer cancel()
+				if err := shutdown(flushCtx); err != nil {
+					slog.Warn("Failed to flush traces", "error", err)
+				}
+			}()
+
 			return server.StartAndServe(ctx, cfg, getVersion())
 		},
 	}
@@ -53,12 +76,16 @@ func registerFlags() []cli.Flag {
 	flags = append(flags, registerWNCFlags()...)
 	flags = append(flags, registerCollectorFlags()...)
 	flags = append(flags, registerLogFlags()...)
+	flags = append(flags, registerTracingFlags()...)
+	flags = append(flags, registerOTLPFlags()...)
+	flags = append(flags, registerRemoteWriteFlags()...)
 	flags = append(flags, registerUtilityFlags()...)
 	flags = append(flags, registerInternalCollectorFlags()...)
 	flags = append(flags, registerAPCollectorFlags()...)
 	flags = append(flags, registerClientCollectorFlags()...)
 	flags = append(flags, registerWLANCollectorFlags()...)
 	flags = append(flags, registerControllerCollectorFlags()...)
+	flags = append(flags, registerRRMCollectorFlags()...)
 	return flags
 }
 
@@ -120,6 +147,28 @@ func registerWNCFlags() []cli.Flag {
 			Name:  "wnc.tls-skip-verify",
 			Usage: "Skip TLS certificate verification",
 		},
+		&cli.IntFlag{
+			Name:  "wnc.retry-attempts",
+			Usage: "Fetches of one WNC data type per refresh before it counts as failed (1 disables retries)",
+			Value: config.DefaultWNCRetryAttempts,
+		},
+		&cli.DurationFlag{
+			Name:  "wnc.retry-backoff",
+			Usage: "Wait before the first retry of a WNC data type, doubled before each next one",
+			Value: config.DefaultWNCRetryBackoff,
+		},
+		&cli.StringFlag{
+			Name:  "wnc.snapshot-file",
+			Usage: "File the last WNC data snapshot is kept in across restarts (empty keeps it in memory)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.DurationFlag{
+			Name:  "wnc.snapshot-max-age",
+			Usage: "Maximum age of a snapshot restored from the snapshot file",
+			Value: config.DefaultWNCSnapshotMaxAge,
+		},
 	}
 }
 
@@ -132,6 +181,21 @@ func registerCollectorFlags() []cli.Flag {
 			Value:    config.DefaultCollectorInfoCacheTTL,
 			Category: "* Collector Wide Options",
 		},
+		&cli.StringSliceFlag{
+			Name:     "collector.const-label",
+			Usage:    "name=value label added to every series of the modules and the refresh health, repeatable",
+			Category: "* Collector Wide Options",
+		},
+		&cli.StringSliceFlag{
+			Name:     "collector.metrics.include",
+			Usage:    "Regex a metric family name has to match to be published, repeatable (none publishes every family)",
+			Category: "* Collector Wide Options",
+		},
+		&cli.StringSliceFlag{
+			Name:     "collector.metrics.exclude",
+			Usage:    "Regex of metric family names withheld, repeatable, applied after --collector.metrics.include",
+			Category: "* Collector Wide Options",
+		},
 	}
 }
 
@@ -168,12 +232,74 @@ func registerAPCollectorFlags() []cli.Flag {
 			Category:    "# AP Collector Options",
 			HideDefault: true,
 		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.uplink",
+			Usage:       "Enable AP uplink neighbor metrics",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.mesh",
+			Usage:       "Enable AP mesh backhaul metrics",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.qos",
+			Usage:       "Enable AP WMM queue metrics",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.restarts",
+			Usage:       "Enable AP reboot and rejoin counters",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.departed",
+			Usage:       "Enable AP departure metrics for APs that left the inventory",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.DurationFlag{
+			Name:     "collector.ap.departed-retention",
+			Usage:    "How long an AP that left the inventory keeps being reported",
+			Value:    config.DefaultAPDepartedRetention,
+			Category: "# AP Collector Options",
+		},
+		&cli.StringFlag{
+			Name:     "collector.ap.departed-state-file",
+			Usage:    "File the departed AP record is kept in across restarts (empty keeps it in memory)",
+			Category: "# AP Collector Options",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.neighbors",
+			Usage:       "Enable AP RRM neighbor metrics",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
+		&cli.IntFlag{
+			Name:     "collector.ap.neighbors-top-n",
+			Usage:    "Number of strongest RRM neighbors kept per AP radio",
+			Value:    config.DefaultAPNeighborsTopN,
+			Category: "# AP Collector Options",
+		},
 		&cli.BoolFlag{
 			Name:        "collector.ap.spectrum",
 			Usage:       "Enable AP CleanAir spectrum metrics",
 			Category:    "# AP Collector Options",
 			HideDefault: true,
 		},
+		&cli.BoolFlag{
+			Name:        "collector.ap.interferers",
+			Usage:       "Enable AP CleanAir interferer device metrics",
+			Category:    "# AP Collector Options",
+			HideDefault: true,
+		},
 		&cli.BoolFlag{
 			Name:        "collector.ap.info",
 			Usage:       "Enable AP info metrics",
@@ -213,6 +339,24 @@ func registerWLANCollectorFlags() []cli.Flag {
 			Category:    "# WLAN Collector Options",
 			HideDefault: true,
 		},
+		&cli.BoolFlag{
+			Name:        "collector.wlan.applications",
+			Usage:       "Enable WLAN AVC application metrics",
+			Category:    "# WLAN Collector Options",
+			HideDefault: true,
+		},
+		&cli.IntFlag{
+			Name:     "collector.wlan.applications-top-n",
+			Usage:    "Number of heaviest AVC applications kept per WLAN",
+			Value:    config.DefaultWLANApplicationsTopN,
+			Category: "# WLAN Collector Options",
+		},
+		&cli.BoolFlag{
+			Name:        "collector.wlan.atf",
+			Usage:       "Enable WLAN Air Time Fairness metrics",
+			Category:    "# WLAN Collector Options",
+			HideDefault: true,
+		},
 		&cli.BoolFlag{
 			Name:        "collector.wlan.info",
 			Usage:       "Enable WLAN info metrics",
@@ -240,6 +384,24 @@ func registerControllerCollectorFlags() []cli.Flag {
 			Category:    "# Controller Collector Options",
 			HideDefault: true,
 		},
+		&cli.BoolFlag{
+			Name:        "collector.controller.aaa",
+			Usage:       "Enable Controller AAA server metrics",
+			Category:    "# Controller Collector Options",
+			HideDefault: true,
+		},
+	}
+}
+
+// registerRRMCollectorFlags defines flags for RRM collector modules.
+func registerRRMCollectorFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "collector.rrm.channels",
+			Usage:       "Enable RRM per-channel contention metrics",
+			Category:    "# RRM Collector Options",
+			HideDefault: true,
+		},
 	}
 }
 
@@ -270,6 +432,24 @@ func registerClientCollectorFlags() []cli.Flag {
 			Category:    "# Client Collector Options",
 			HideDefault: true,
 		},
+		&cli.BoolFlag{
+			Name:        "collector.client.devices",
+			Usage:       "Enable Client device classification metrics",
+			Category:    "# Client Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.client.onboarding",
+			Usage:       "Enable Client onboarding latency histograms",
+			Category:    "# Client Collector Options",
+			HideDefault: true,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.client.sessions",
+			Usage:       "Enable Client session start, end and roam counters",
+			Category:    "# Client Collector Options",
+			HideDefault: true,
+		},
 		&cli.BoolFlag{
 			Name:        "collector.client.info",
 			Usage:       "Enable Client info metrics",
@@ -304,6 +484,69 @@ func registerLogFlags() []cli.Flag {
 	}
 }
 
+// registerTracingFlags defines flags for OpenTelemetry tracing configuration.
+func registerTracingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "tracing.endpoint",
+			Usage: "OTLP/HTTP endpoint URL refresh traces are exported to (empty disables tracing)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	}
+}
+
+// registerOTLPFlags defines flags for OTLP metrics push configuration.
+func registerOTLPFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "otlp.endpoint",
+			Usage: "OTLP/HTTP endpoint URL metrics are pushed to after each refresh (empty disables the push)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	}
+}
+
+// registerRemoteWriteFlags defines flags for Prometheus remote-write push configuration.
+func registerRemoteWriteFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "remote-write.url",
+			Usage: "Prometheus remote-write URL metrics are pushed to after each refresh (empty disables the push)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "remote-write.username",
+			Usage: "Basic auth username for the remote-write URL",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.StringFlag{
+			Name:    "remote-write.password",
+			Usage:   "Basic auth password for the remote-write URL",
+			Sources: cli.EnvVars("REMOTE_WRITE_PASSWORD"),
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "remote-write.external-labels",
+			Usage: "Comma-separated name=value labels added to every pushed series",
+		},
+		&cli.IntFlag{
+			Name:  "remote-write.queue-size",
+			Usage: "Pushes held while the remote-write URL is unreachable, the oldest dropped first",
+			Value: config.DefaultRemoteWriteQueueSize,
+		},
+	}
+}
+
 // registerInternalCollectorFlags defines flags for internal metrics collection configuration.
 func registerInternalCollectorFlags() []cli.Flag {
 	return []cli.Flag{
@@ -1,9 +1,17 @@
 package cli
 
 import (
+	"context"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/urfave/cli/v3"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/inventory"
 )
 
 // TestRegisterFlags verifies that registerFlags returns all flags from sub-registrars.
@@ -16,7 +24,7 @@ func TestRegisterFlags(t *testing.T) {
 	}{
 		{
 			name:          "All flags registered",
-			expectedCount: 34,
+			expectedCount: 66,
 		},
 	}
 
@@ -88,8 +96,11 @@ func TestRegisterWNCFlags(t *testing.T) {
 	}{
 		{
 			name:          "WNC flags count",
-			expectedCount: 5,
-			expectedTypes: []string{"string", "string", "duration", "duration", "bool"},
+			expectedCount: 9,
+			expectedTypes: []string{
+				"string", "string", "duration", "duration", "bool",
+				"int", "duration", "string", "duration",
+			},
 		},
 	}
 
@@ -110,6 +121,8 @@ func TestRegisterWNCFlags(t *testing.T) {
 					gotType = "string"
 				case *cli.DurationFlag:
 					gotType = "duration"
+				case *cli.IntFlag:
+					gotType = "int"
 				case *cli.BoolFlag:
 					gotType = "bool"
 				default:
@@ -161,7 +174,7 @@ func TestRegisterCollectorFlags(t *testing.T) {
 	}{
 		{
 			name:          "Collector flags count",
-			expectedCount: 1,
+			expectedCount: 4,
 		},
 	}
 
@@ -193,8 +206,11 @@ func TestRegisterAPCollectorFlags(t *testing.T) {
 	}{
 		{
 			name:          "AP collector flags count",
-			expectedCount: 8,
-			expectedTypes: []string{"bool", "bool", "bool", "bool", "bool", "bool", "bool", "string"},
+			expectedCount: 18,
+			expectedTypes: []string{
+				"bool", "bool", "bool", "bool", "bool", "bool", "bool", "bool", "bool", "bool",
+				"duration", "string", "bool", "int", "bool", "bool", "bool", "string",
+			},
 		},
 	}
 
@@ -213,6 +229,10 @@ func TestRegisterAPCollectorFlags(t *testing.T) {
 				switch flag.(type) {
 				case *cli.BoolFlag:
 					gotType = "bool"
+				case *cli.IntFlag:
+					gotType = "int"
+				case *cli.DurationFlag:
+					gotType = "duration"
 				case *cli.StringFlag:
 					gotType = "string"
 				default:
@@ -238,8 +258,8 @@ func TestRegisterWLANCollectorFlags(t *testing.T) {
 	}{
 		{
 			name:          "WLAN collector flags count",
-			expectedCount: 5,
-			expectedTypes: []string{"bool", "bool", "bool", "bool", "string"},
+			expectedCount: 8,
+			expectedTypes: []string{"bool", "bool", "bool", "bool", "int", "bool", "bool", "string"},
 		},
 	}
 
@@ -258,6 +278,8 @@ func TestRegisterWLANCollectorFlags(t *testing.T) {
 				switch flag.(type) {
 				case *cli.BoolFlag:
 					gotType = "bool"
+				case *cli.IntFlag:
+					gotType = "int"
 				case *cli.StringFlag:
 					gotType = "string"
 				default:
@@ -277,8 +299,23 @@ func TestRegisterControllerCollectorFlags(t *testing.T) {
 	t.Parallel()
 
 	flags := registerControllerCollectorFlags()
+	if got := len(flags); got != 2 {
+		t.Errorf("registerControllerCollectorFlags() returned %d flags, want 2", got)
+	}
+	for i, flag := range flags {
+		if _, ok := flag.(*cli.BoolFlag); !ok {
+			t.Errorf("flag[%d] type = %T, want *cli.BoolFlag", i, flag)
+		}
+	}
+}
+
+// TestRegisterRRMCollectorFlags verifies RRM collector module flags.
+func TestRegisterRRMCollectorFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := registerRRMCollectorFlags()
 	if got := len(flags); got != 1 {
-		t.Errorf("registerControllerCollectorFlags() returned %d flags, want 1", got)
+		t.Errorf("registerRRMCollectorFlags() returned %d flags, want 1", got)
 	}
 	if _, ok := flags[0].(*cli.BoolFlag); !ok {
 		t.Errorf("flag[0] type = %T, want *cli.BoolFlag", flags[0])
@@ -296,8 +333,8 @@ func TestRegisterClientCollectorFlags(t *testing.T) {
 	}{
 		{
 			name:          "Client collector flags count",
-			expectedCount: 6,
-			expectedTypes: []string{"bool", "bool", "bool", "bool", "bool", "string"},
+			expectedCount: 9,
+			expectedTypes: []string{"bool", "bool", "bool", "bool", "bool", "bool", "bool", "bool", "string"},
 		},
 	}
 
@@ -363,6 +400,62 @@ func TestRegisterLogFlags(t *testing.T) {
 	}
 }
 
+// TestRegisterTracingFlags verifies OpenTelemetry tracing flags.
+func TestRegisterTracingFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := registerTracingFlags()
+	if got := len(flags); got != 1 {
+		t.Errorf("registerTracingFlags() returned %d flags, want 1", got)
+	}
+	flag, ok := flags[0].(*cli.StringFlag)
+	if !ok {
+		t.Fatalf("flag[0] type = %T, want *cli.StringFlag", flags[0])
+	}
+	if flag.Value != "" {
+		t.Errorf("flag[0] default = %q, want empty so tracing is off unless asked for", flag.Value)
+	}
+}
+
+// TestRegisterOTLPFlags verifies OTLP metrics push flags.
+func TestRegisterOTLPFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := registerOTLPFlags()
+	if got := len(flags); got != 1 {
+		t.Errorf("registerOTLPFlags() returned %d flags, want 1", got)
+	}
+	flag, ok := flags[0].(*cli.StringFlag)
+	if !ok {
+		t.Fatalf("flag[0] type = %T, want *cli.StringFlag", flags[0])
+	}
+	if flag.Value != "" {
+		t.Errorf("flag[0] default = %q, want empty so the push is off unless asked for", flag.Value)
+	}
+}
+
+// TestRegisterRemoteWriteFlags verifies Prometheus remote-write push flags.
+func TestRegisterRemoteWriteFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := registerRemoteWriteFlags()
+	if got := len(flags); got != 5 {
+		t.Fatalf("registerRemoteWriteFlags() returned %d flags, want 5", got)
+	}
+	for i, flag := range flags[:4] {
+		if _, ok := flag.(*cli.StringFlag); !ok {
+			t.Errorf("flag[%d] type = %T, want *cli.StringFlag", i, flag)
+		}
+	}
+	queue, ok := flags[4].(*cli.IntFlag)
+	if !ok {
+		t.Fatalf("flag[4] type = %T, want *cli.IntFlag", flags[4])
+	}
+	if queue.Value != config.DefaultRemoteWriteQueueSize {
+		t.Errorf("flag[4] default = %d, want %d", queue.Value, config.DefaultRemoteWriteQueueSize)
+	}
+}
+
 // TestRegisterInternalCollectorFlags verifies internal collector flags.
 func TestRegisterInternalCollectorFlags(t *testing.T) {
 	t.Parallel()
@@ -468,3 +561,81 @@ func TestNewApp(t *testing.T) {
 		})
 	}
 }
+
+// TestRegisterCommands verifies the subcommands and that their own flags stay local.
+func TestRegisterCommands(t *testing.T) {
+	t.Parallel()
+
+	commands := registerCommands()
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	if !slices.Equal(names, []string{"collect", "check", "metrics", "aps", "clients", "wlans"}) {
+		t.Errorf("registerCommands() = %v, want [collect check metrics aps clients wlans]", names)
+	}
+
+	for _, flag := range registerCollectFlags() {
+		if local, ok := flag.(cli.LocalFlag); !ok || !local.IsLocal() {
+			t.Errorf("collect flag %v is not local", flag.Names())
+		}
+	}
+	for _, flag := range registerMetricsFlags() {
+		if local, ok := flag.(cli.LocalFlag); !ok || !local.IsLocal() {
+			t.Errorf("metrics flag %v is not local", flag.Names())
+		}
+	}
+	for _, flag := range registerInventoryFlags(inventory.Queries[0]) {
+		if local, ok := flag.(cli.LocalFlag); !ok || !local.IsLocal() {
+			t.Errorf("inventory flag %v is not local", flag.Names())
+		}
+	}
+}
+
+// TestRequiredWNCFlags_ExceptForMetrics pins that the controller and the token
+// stay required flags for the commands that reach the controller, while the metrics
+// subcommand runs without them.
+func TestRequiredWNCFlags_ExceptForMetrics(t *testing.T) {
+	// Setenv restores both variables afterwards; an empty variable would count as set.
+	for _, name := range []string{"WNC_CONTROLLER", "WNC_ACCESS_TOKEN"} {
+		t.Setenv(name, "")
+		if err := os.Unsetenv(name); err != nil {
+			t.Fatalf("os.Unsetenv(%s) error = %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "serve", args: []string{"cisco-wnc-exporter"}, wantErr: true},
+		{name: "check", args: []string{"cisco-wnc-exporter", "check"}, wantErr: true},
+		{name: "metrics", args: []string{"cisco-wnc-exporter", "metrics", "--format", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// NewApp runs itself on os.Args, so the root command is assembled the same way
+			// with an action that does nothing.
+			app := &cli.Command{
+				Name:      "cisco-wnc-exporter",
+				Flags:     registerFlags(),
+				Commands:  registerCommands(),
+				Writer:    io.Discard,
+				ErrWriter: io.Discard,
+				Action:    func(context.Context, *cli.Command) error { return nil },
+			}
+
+			err := app.Run(context.Background(), tt.args)
+
+			var required bool
+			if err != nil {
+				required = strings.Contains(err.Error(), "wnc.controller")
+			}
+			if required != tt.wantErr {
+				t.Errorf("Run(%v) error = %v, want required flag error %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
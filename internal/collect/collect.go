@@ -0,0 +1,182 @@
+// Package collect provides the one-shot collection behind the collect subcommand: one
+// synchronous refresh, one gather, and the result written out instead of served.
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// Output formats.
+const (
+	FormatText        = "text"
+	FormatOpenMetrics = "openmetrics"
+	FormatJSON        = "json"
+)
+
+// Formats lists the output formats Write accepts.
+var Formats = []string{FormatText, FormatOpenMetrics, FormatJSON}
+
+// outputFileMode is the mode of a written output file. A textfile collector usually
+// runs as another user than the cron job writing its input, so the file is readable by
+// all.
+const outputFileMode = 0o644
+
+// errUnknownFormat is returned by Write for a format not in Formats.
+var errUnknownFormat = errors.New("unknown output format")
+
+// errComparingModules is returned by Gather for a configuration enabling a module that
+// counts what changed between two refreshes.
+var errComparingModules = errors.New("modules that compare two refreshes need more than one, disable them for collect")
+
+// Result is one gathered set of metric families and the time they describe.
+type Result struct {
+	Families []*dto.MetricFamily
+	// RefreshedAt is when the refresh behind the families started.
+	RefreshedAt time.Time
+}
+
+// Gather refreshes the WNC data once, waiting for it, and gathers every collector the
+// configuration enables, exactly as a scrape of the exporter would.
+//
+// A refresh that reached no data type fails it, and so does a collector that failed,
+// as it would fail a scrape. A data type that failed while others succeeded only
+// withholds the series derived from it. A configuration enabling a module that
+// compares refreshes is refused before anything is fetched, and the files a running
+// exporter keeps are left out by oneShotConfig.
+func Gather(ctx context.Context, cfg *config.Config, version string) (*Result, error) {
+	if modules := comparingModules(cfg); len(modules) > 0 {
+		return nil, fmt.Errorf("%w: %s", errComparingModules, strings.Join(modules, ", "))
+	}
+
+	collectorMgr := collector.NewCollector(oneShotConfig(cfg))
+	collectorMgr.Setup(version)
+
+	source := collectorMgr.DataSource()
+	refresher, ok := source.(wnc.SyncRefresher)
+	if !ok {
+		return nil, errors.New("WNC data source cannot be refreshed synchronously")
+	}
+	if err := refresher.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("WNC data refresh failed: %w", err)
+	}
+
+	data, err := source.GetCachedData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("WNC data unavailable: %w", err)
+	}
+
+	families, err := collectorMgr.Registry().Gather()
+	if err != nil {
+		return nil, fmt.Errorf("gathering metrics failed: %w", err)
+	}
+
+	return &Result{Families: families, RefreshedAt: data.RefreshedAt}, nil
+}
+
+// oneShotConfig returns a copy of the configuration fit for a single refresh. The data
+// source restores nothing from the snapshot file, and the departed module neither loads
+// nor rewrites its state file, so a one-shot run leaves the files of a running exporter
+// alone.
+func oneShotConfig(cfg *config.Config) *config.Config {
+	oneShot := *cfg
+	oneShot.WNC.SnapshotFile = ""
+	oneShot.Collectors.AP.DepartedStateFile = ""
+
+	return &oneShot
+}
+
+// comparingModules returns the flags of the enabled modules that count what changed
+// between two refreshes, sorted. A process that refreshes once only records the first
+// snapshot, so they would write zeros or nothing at all, which reads as a quiet network
+// rather than as no data.
+func comparingModules(cfg *config.Config) []string {
+	var modules []string
+	for name, enabled := range map[string]bool{
+		"--collector.ap.restarts":       cfg.Collectors.AP.Restarts,
+		"--collector.client.onboarding": cfg.Collectors.Client.Onboarding,
+		"--collector.client.sessions":   cfg.Collectors.Client.Sessions,
+	} {
+		if enabled {
+			modules = append(modules, name)
+		}
+	}
+	slices.Sort(modules)
+
+	return modules
+}
+
+// Write writes the result to w in the given format. Samples carry no timestamp in the
+// exposition formats, as node_exporter's textfile collector requires, so the refresh
+// time is only in the JSON output.
+func Write(w io.Writer, result *Result, format string) error {
+	switch format {
+	case FormatText, FormatOpenMetrics:
+		return writeExposition(w, result.Families, format)
+	case FormatJSON:
+		return writeJSON(w, result)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+}
+
+// WriteFile writes the result to path in the given format. It writes a temporary file
+// next to it and renames it into place, so a textfile collector reading the path never
+// sees a half-written file.
+func WriteFile(path string, result *Result, format string) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	// Once the rename has succeeded there is nothing left to remove.
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if err := Write(temp, result, format); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Chmod(outputFileMode); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+// writeExposition writes the families in the Prometheus text or OpenMetrics format.
+func writeExposition(w io.Writer, families []*dto.MetricFamily, format string) error {
+	expositionFormat := expfmt.NewFormat(expfmt.TypeTextPlain)
+	if format == FormatOpenMetrics {
+		expositionFormat = expfmt.NewFormat(expfmt.TypeOpenMetrics)
+	}
+
+	encoder := expfmt.NewEncoder(w, expositionFormat)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+
+	// OpenMetrics ends with an EOF marker the encoder writes on Close.
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+func testResult(t *testing.T) *Result {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "wnc_up", Help: "Up."})
+	up.Set(1)
+	ratio := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "wnc_ap_ratio", Help: "Ratio."}, []string{"mac"})
+	ratio.WithLabelValues("aa:bb:cc:dd:ee:ff").Set(math.NaN())
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "wnc_request_duration_seconds", Help: "Duration.", Buckets: []float64{0.5},
+	})
+	duration.Observe(0.2)
+	duration.Observe(2)
+	registry.MustRegister(up, ratio, duration)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	return &Result{Families: families, RefreshedAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+}
+
+// TestWrite_Formats pins what each format carries: the text format as a scrape serves
+// it with no timestamps, OpenMetrics with its EOF marker, and JSON with the refresh
+// time and a NaN it cannot hold as a number.
+func TestWrite_Formats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatText, []string{"# TYPE wnc_up gauge\nwnc_up 1\n", `wnc_request_duration_seconds_bucket{le="+Inf"} 2`}},
+		{FormatOpenMetrics, []string{"wnc_up 1.0\n", "# EOF\n"}},
+		{FormatJSON, []string{`"refreshed_at": "2026-10-18T09:00:00Z"`, `"value": "NaN"`, `"+Inf": 2`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := Write(&buf, testResult(t), tt.format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Write(%s) output lacks %q:\n%s", tt.format, want, buf.String())
+				}
+			}
+			if tt.format == FormatJSON && !json.Valid(buf.Bytes()) {
+				t.Errorf("Write(json) output is not valid JSON:\n%s", buf.String())
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, testResult(t), "yaml"); err == nil {
+		t.Error("Write(yaml) error = nil, want an unknown format error")
+	}
+}
+
+// TestWriteFile_ReplacesAtomically covers the textfile collector's use: the file is
+// replaced whole, readable by another user, and no temporary file is left behind.
+func TestWriteFile_ReplacesAtomically(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wnc.prom")
+	if err := os.WriteFile(path, []byte("stale\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFile(path, testResult(t), FormatText); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "stale") || !strings.Contains(string(content), "wnc_up 1") {
+		t.Errorf("file = %q, want the new metrics only", content)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != outputFileMode {
+		t.Errorf("file mode = %v, want %v", info.Mode().Perm(), os.FileMode(outputFileMode))
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want the output file alone", len(entries))
+	}
+}
+
+// TestGather_FailsWhenControllerUnreachable keeps a cron job from replacing a good file
+// with one that carries no data: a refresh that reached nothing fails the collection.
+func TestGather_FailsWhenControllerUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	cfg := &config.Config{
+		WNC: config.WNC{
+			Controller:    target.Host,
+			AccessToken:   "test-token",
+			Timeout:       5 * time.Second,
+			CacheTTL:      5 * time.Second,
+			TLSSkipVerify: true,
+			RetryAttempts: 1,
+		},
+		Collectors: config.Collectors{AP: config.APCollectorModules{General: true}},
+	}
+
+	if _, err := Gather(context.Background(), cfg, "test"); err == nil {
+		t.Error("Gather() error = nil with the controller failing every request, want the refresh error")
+	}
+}
+
+// TestOneShotConfig pins what a one-shot run leaves out: the files a running exporter
+// keeps. Every module is kept, and the caller's configuration is left as it was.
+func TestOneShotConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		WNC: config.WNC{SnapshotFile: "/var/lib/wnc/snapshot.json"},
+		Collectors: config.Collectors{
+			AP: config.APCollectorModules{
+				General: true, Departed: true,
+				DepartedStateFile: "/var/lib/wnc/departed.json",
+			},
+			Client: config.ClientCollectorModules{General: true},
+		},
+	}
+
+	got := oneShotConfig(cfg)
+	if got.WNC.SnapshotFile != "" || got.Collectors.AP.DepartedStateFile != "" {
+		t.Errorf("oneShotConfig() kept snapshot file %q and departed state file %q, want neither",
+			got.WNC.SnapshotFile, got.Collectors.AP.DepartedStateFile)
+	}
+	if !got.Collectors.AP.General || !got.Collectors.AP.Departed || !got.Collectors.Client.General {
+		t.Errorf("oneShotConfig() disabled a module: %+v", got.Collectors)
+	}
+	if cfg.WNC.SnapshotFile == "" || cfg.Collectors.AP.DepartedStateFile == "" {
+		t.Error("oneShotConfig() changed the caller's configuration, want a copy")
+	}
+}
+
+// TestGather_RefusesModulesThatCompareRefreshes names every enabled module one refresh
+// cannot feed, and fails before the controller is reached rather than disabling them.
+func TestGather_RefusesModulesThatCompareRefreshes(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Collectors: config.Collectors{
+		AP:     config.APCollectorModules{General: true, Restarts: true},
+		Client: config.ClientCollectorModules{Onboarding: true, Sessions: true},
+	}}
+
+	_, err := Gather(context.Background(), cfg, "test")
+	if !errors.Is(err, errComparingModules) {
+		t.Fatalf("Gather() error = %v, want %v", err, errComparingModules)
+	}
+	for _, flag := range []string{
+		"--collector.ap.restarts", "--collector.client.onboarding", "--collector.client.sessions",
+	} {
+		if !strings.Contains(err.Error(), flag) {
+			t.Errorf("Gather() error = %v, want %s named", err, flag)
+		}
+	}
+	if !cfg.Collectors.AP.Restarts || !cfg.Collectors.Client.Sessions {
+		t.Error("Gather() changed the caller's configuration")
+	}
+}
@@ -0,0 +1,141 @@
+// Package collect provides the one-shot collection behind the collect subcommand: one
+// synchronous refresh, one gather, and the result written out instead of served.
+// This file holds the JSON output.
+package collect
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// jsonResult is the JSON output: the families and the time they describe.
+type jsonResult struct {
+	RefreshedAt time.Time    `json:"refreshed_at"`
+	Families    []jsonFamily `json:"families"`
+}
+
+// jsonFamily is one metric family.
+type jsonFamily struct {
+	Name    string       `json:"name"`
+	Help    string       `json:"help"`
+	Type    string       `json:"type"`
+	Metrics []jsonMetric `json:"metrics"`
+}
+
+// jsonMetric is one series of a family. A counter, gauge or untyped metric carries
+// value, a histogram buckets, sum and count, and a summary quantiles, sum and count.
+type jsonMetric struct {
+	Labels    map[string]string    `json:"labels,omitempty"`
+	Value     *jsonFloat           `json:"value,omitempty"`
+	Buckets   map[string]uint64    `json:"buckets,omitempty"`
+	Quantiles map[string]jsonFloat `json:"quantiles,omitempty"`
+	Sum       *jsonFloat           `json:"sum,omitempty"`
+	Count     *uint64              `json:"count,omitempty"`
+}
+
+// jsonFloat is a sample value. JSON has no NaN or infinity, so those are written as
+// the strings the exposition formats spell them with, and every other value as a
+// number.
+type jsonFloat float64
+
+// MarshalJSON implements json.Marshaler.
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v), math.IsInf(v, 0):
+		return json.Marshal(formatFloat(v))
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// writeJSON writes the result as one JSON document.
+func writeJSON(w io.Writer, result *Result) error {
+	out := jsonResult{
+		RefreshedAt: result.RefreshedAt,
+		Families:    make([]jsonFamily, 0, len(result.Families)),
+	}
+	for _, family := range result.Families {
+		out.Families = append(out.Families, newJSONFamily(family))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// newJSONFamily converts one gathered family.
+func newJSONFamily(family *dto.MetricFamily) jsonFamily {
+	out := jsonFamily{
+		Name:    family.GetName(),
+		Help:    family.GetHelp(),
+		Type:    strings.ToLower(family.GetType().String()),
+		Metrics: make([]jsonMetric, 0, len(family.GetMetric())),
+	}
+
+	for _, m := range family.GetMetric() {
+		metric := jsonMetric{}
+		if len(m.GetLabel()) > 0 {
+			metric.Labels = make(map[string]string, len(m.GetLabel()))
+			for _, pair := range m.GetLabel() {
+				metric.Labels[pair.GetName()] = pair.GetValue()
+			}
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			metric.Value = floatPtr(m.GetCounter().GetValue())
+		case dto.MetricType_GAUGE:
+			metric.Value = floatPtr(m.GetGauge().GetValue())
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			h := m.GetHistogram()
+			metric.Buckets = make(map[string]uint64, len(h.GetBucket())+1)
+			for _, bucket := range h.GetBucket() {
+				metric.Buckets[formatFloat(bucket.GetUpperBound())] = bucket.GetCumulativeCount()
+			}
+			// The client library leaves the +Inf bucket implicit, as the exposition
+			// formats write it out.
+			metric.Buckets[formatFloat(math.Inf(1))] = h.GetSampleCount()
+			count := h.GetSampleCount()
+			metric.Sum, metric.Count = floatPtr(h.GetSampleSum()), &count
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			metric.Quantiles = make(map[string]jsonFloat, len(s.GetQuantile()))
+			for _, q := range s.GetQuantile() {
+				metric.Quantiles[formatFloat(q.GetQuantile())] = jsonFloat(q.GetValue())
+			}
+			count := s.GetSampleCount()
+			metric.Sum, metric.Count = floatPtr(s.GetSampleSum()), &count
+		default:
+			metric.Value = floatPtr(m.GetUntyped().GetValue())
+		}
+
+		out.Metrics = append(out.Metrics, metric)
+	}
+	return out
+}
+
+func floatPtr(v float64) *jsonFloat {
+	f := jsonFloat(v)
+	return &f
+}
+
+// formatFloat spells a value the way the exposition formats do.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
@@ -28,9 +28,16 @@ const (
 	typeAPRadioOperStats      = "ap_radio_oper_stats"
 	typeAPRadioResetStats     = "ap_radio_reset_stats"
 	typeAPJoinStats           = "ap_join_stats"
+	typeAPCDPCacheData        = "ap_cdp_cache_data"
+	typeAPLLDPNeigh           = "ap_lldp_neigh"
+	typeAPPwrInfo             = "ap_pwr_info"
+	typeAPMeshOperData        = "ap_mesh_oper_data"
+	typeAPRadioWMMStats       = "ap_radio_wmm_stats"
+	typeAPRadioATFStats       = "ap_radio_atf_stats"
 	typeControllerBootTime    = "controller_boot_time"
 	typeCoClientDelReason     = "co_client_del_reason"
 	typeClientRoamingStats    = "client_roaming_stats"
+	typeAAARadiusStats        = "aaa_radius_stats"
 	typeClientCommonOperData  = "client_common_oper_data"
 	typeClientDCInfo          = "client_dc_info"
 	typeClientDot11OperData   = "client_dot11_oper_data"
@@ -38,27 +45,34 @@ const (
 	typeClientTrafficStats    = "client_traffic_stats"
 	typeClientMMIFHistory     = "client_mm_if_client_history"
 	typeRRMMeasurement        = "rrm_measurement"
+	typeRRMAPAutoRFDot11Data  = "rrm_ap_auto_rf_dot11_data"
 	typeRRMCoverage           = "rrm_coverage"
 	typeRRMAPDot11RadarData   = "rrm_ap_dot11_radar_data"
 	typeRRMRadioSlot          = "rrm_radio_slot"
 	typeRRMMainData           = "rrm_main_data"
+	typeRRMSpectrumDevice     = "rrm_spectrum_device_table"
 	typeRRMSpectrumAqTable    = "rrm_spectrum_aq_table"
 	typeRRMSpectrumAqWorst    = "rrm_spectrum_aq_worst_table"
 	typeWLANCfgEntries        = "wlan_cfg_entries"
 	typeWLANPolicies          = "wlan_policies"
 	typeWLANPolicyListEntries = "wlan_policy_list_entries"
 	typeWLANClientStats       = "wlan_client_stats"
+	typeWLANAVCStats          = "wlan_avc_stats"
 )
 
 var allDataTypes = []string{
 	typeAPCAPWAPData, typeAPOperData, typeAPRadioOperData, typeAPNameMACMap,
 	typeAPRadioOperStats, typeAPRadioResetStats, typeAPJoinStats,
-	typeControllerBootTime, typeCoClientDelReason, typeClientRoamingStats,
+	typeAPCDPCacheData, typeAPLLDPNeigh, typeAPPwrInfo, typeAPMeshOperData,
+	typeAPRadioWMMStats, typeAPRadioATFStats,
+	typeControllerBootTime, typeCoClientDelReason, typeClientRoamingStats, typeAAARadiusStats,
 	typeClientCommonOperData, typeClientDCInfo, typeClientDot11OperData,
 	typeClientSISFDBMac, typeClientTrafficStats, typeClientMMIFHistory,
-	typeRRMMeasurement, typeRRMCoverage, typeRRMAPDot11RadarData, typeRRMRadioSlot,
-	typeRRMMainData, typeRRMSpectrumAqTable, typeRRMSpectrumAqWorst,
+	typeRRMMeasurement, typeRRMAPAutoRFDot11Data, typeRRMCoverage, typeRRMAPDot11RadarData,
+	typeRRMRadioSlot, typeRRMMainData, typeRRMSpectrumDevice, typeRRMSpectrumAqTable,
+	typeRRMSpectrumAqWorst,
 	typeWLANCfgEntries, typeWLANPolicies, typeWLANPolicyListEntries, typeWLANClientStats,
+	typeWLANAVCStats,
 }
 
 const (
@@ -81,7 +95,7 @@ const (
 	fixtureFTMode     = "dot11r-disabled"
 
 	// fixtureUnnumberedSpelling is well formed and belongs to no release of any of the
-	// twelve enumerations, so it is the reading the encoding must withhold rather than
+	// thirteen enumerations, so it is the reading the encoding must withhold rather than
 	// number. The data channel of the DTLS reason leaf carries it.
 	fixtureUnnumberedSpelling = "dtls-hs-fragment-error"
 
@@ -99,6 +113,22 @@ const (
 	fixtureZeroLatencyClientMAC = "02:03:04:05:06:07"
 	fixtureUnmappedAPName       = "ap-absent-from-the-name-map"
 	fixtureUnconfiguredWLANID   = 9
+
+	// fixtureLLDPOnlyAPMAC is an AP only LLDP names, so its neighbor is published from
+	// that list. It sorts after fixtureAPMAC's CDP entry so that the first sample of the
+	// uplink info family stays the CDP one.
+	fixtureLLDPOnlyAPMAC = "aa:bb:cc:dd:ef:00"
+
+	// fixtureNeighborTopN is the neighbors module's cap. The fixture radio hears one more
+	// neighbor than it, the weakest, which must not be published.
+	fixtureNeighborTopN = 2
+
+	// fixtureApplicationTopN is the applications module's cap. The fixture WLAN carries
+	// one application more than it, which is published only inside the other bucket.
+	fixtureApplicationTopN = 1
+	// fixtureNeighborAPMAC is a radio the name map does not carry, so it is published
+	// as a neighbor without a name. It hears fixtureAPMAC, which the name map names.
+	fixtureNeighborAPMAC = "aa:bb:cc:dd:ef:10"
 )
 
 // The eleven timestamps of the join record, one day apart so that every pair is
@@ -205,13 +235,16 @@ func TestAllCollectors_OmitSeriesWhenDataTypeFails(t *testing.T) {
 	}{
 		{typeAPCAPWAPData, []string{
 			"wnc_ap_config_state", "wnc_ap_uptime_seconds", "wnc_ap_oper_state",
-			"wnc_ap_association_uptime_seconds",
+			"wnc_ap_association_uptime_seconds", "wnc_ap_last_seen_timestamp_seconds",
 		}},
 		{typeAPOperData, []string{"wnc_ap_cpu_utilization_ratio", "wnc_ap_memory_utilization_ratio"}},
 		{typeAPRadioOperData, []string{
 			"wnc_ap_radio_state", "wnc_ap_channel_number", "wnc_ap_clients", "wnc_ap_info",
+			"wnc_rrm_channel_aps", "wnc_rrm_channel_clients",
+		}},
+		{typeAPNameMACMap, []string{
+			"wnc_ap_clients", "wnc_ap_neighbor_info", "wnc_rrm_channel_clients",
 		}},
-		{typeAPNameMACMap, []string{"wnc_ap_clients"}},
 		{typeAPRadioOperStats, []string{
 			"wnc_ap_data_rx_frames_total", "wnc_ap_data_tx_frames_total",
 			"wnc_ap_rx_errors_total", "wnc_ap_fcs_errors_total",
@@ -222,6 +255,22 @@ func TestAllCollectors_OmitSeriesWhenDataTypeFails(t *testing.T) {
 			"wnc_ap_dtls_session_requests_total", "wnc_ap_last_join_success_timestamp_seconds",
 			"wnc_ap_last_dtls_success_timestamp_seconds", "wnc_ap_last_reboot_reason",
 		}},
+		// The LLDP list has no case: the fixture AP it alone names loses its series, but
+		// the family keeps the CDP ones. TestAPUplinkModule_PrefersCDP covers it.
+		{typeAPCDPCacheData, []string{
+			"wnc_ap_uplink_info", "wnc_ap_uplink_speed_mbps", "wnc_ap_uplink_full_duplex",
+		}},
+		{typeAPPwrInfo, []string{"wnc_ap_uplink_power_full"}},
+		{typeAPMeshOperData, []string{
+			"wnc_ap_mesh_info", "wnc_ap_mesh_root", "wnc_ap_mesh_hops",
+			"wnc_ap_mesh_backhaul_channel", "wnc_ap_mesh_link_snr_db", "wnc_ap_mesh_link_rate_mbps",
+		}},
+		{typeAPRadioWMMStats, []string{
+			"wnc_ap_qos_transmitted_frames_total", "wnc_ap_qos_queue_drops_total",
+		}},
+		{typeAPRadioATFStats, []string{
+			"wnc_wlan_atf_airtime_allocation_ratio", "wnc_wlan_atf_airtime_seconds_total",
+		}},
 		{typeControllerBootTime, []string{"wnc_controller_boot_time_seconds"}},
 		{typeCoClientDelReason, []string{"wnc_controller_client_deletes_total"}},
 		{typeClientRoamingStats, []string{
@@ -229,21 +278,37 @@ func TestAllCollectors_OmitSeriesWhenDataTypeFails(t *testing.T) {
 			"wnc_controller_client_ap_auth_dot11i_fast_roams_total",
 			"wnc_controller_client_ap_auth_dot11i_slow_roams_total",
 		}},
+		{typeAAARadiusStats, []string{
+			"wnc_controller_aaa_auth_requests_total", "wnc_controller_aaa_auth_accepts_total",
+			"wnc_controller_aaa_auth_rejects_total", "wnc_controller_aaa_auth_timeouts_total",
+			"wnc_controller_aaa_auth_retransmits_total", "wnc_controller_aaa_acct_requests_total",
+			"wnc_controller_aaa_acct_responses_total", "wnc_controller_aaa_acct_timeouts_total",
+			"wnc_controller_aaa_acct_retransmits_total", "wnc_controller_aaa_response_time_seconds",
+			"wnc_controller_aaa_server_up",
+		}},
 		{typeClientCommonOperData, []string{
 			"wnc_client_state", "wnc_client_info", "wnc_ap_clients",
-			"wnc_wlan_clients",
+			"wnc_wlan_clients", "wnc_rrm_channel_clients", "wnc_client_devices",
+			"wnc_client_sessions_started_total", "wnc_client_session_duration_seconds",
+		}},
+		{typeClientDCInfo, []string{"wnc_client_devices"}},
+		{typeClientDot11OperData, []string{
+			"wnc_client_protocol", "wnc_client_uptime_seconds", "wnc_client_onboarding_pending_seconds",
 		}},
-		{typeClientDot11OperData, []string{"wnc_client_protocol", "wnc_client_uptime_seconds"}},
 		{typeClientTrafficStats, clientTrafficDerived},
 		{typeClientMMIFHistory, []string{
 			"wnc_client_state_transition_seconds",
 			"wnc_client_roam_type",
+			// wnc_client_onboarding_latency_seconds is in neither list: its run phase
+			// reads this one and its other phases the dot11 list, so either keeps it.
 		}},
 		{typeRRMMeasurement, []string{
 			"wnc_ap_channel_utilization_ratio", "wnc_ap_rx_utilization_ratio",
 			"wnc_ap_tx_utilization_ratio", "wnc_ap_noise_utilization_ratio",
-			"wnc_ap_noise_floor_dbm",
+			"wnc_ap_noise_floor_dbm", "wnc_rrm_channel_utilization_ratio_avg",
+			"wnc_rrm_channel_utilization_ratio_max", "wnc_rrm_channel_noise_floor_dbm_avg",
 		}},
+		{typeRRMAPAutoRFDot11Data, []string{"wnc_ap_neighbor_rssi_dbm", "wnc_ap_neighbor_info"}},
 		{typeRRMCoverage, []string{"wnc_ap_coverage_failed_clients"}},
 		{typeRRMAPDot11RadarData, []string{"wnc_ap_last_radar_timestamp_seconds"}},
 		{typeRRMRadioSlot, []string{
@@ -255,6 +320,11 @@ func TestAllCollectors_OmitSeriesWhenDataTypeFails(t *testing.T) {
 			"wnc_rrm_last_rf_grouping_run_timestamp_seconds",
 			"wnc_rrm_last_dca_run_timestamp_seconds",
 		}},
+		{typeRRMSpectrumDevice, []string{
+			"wnc_ap_interferer_devices",
+			"wnc_ap_interferer_severity_max",
+			"wnc_ap_interferer_duty_cycle_ratio_max",
+		}},
 		{typeRRMSpectrumAqTable, []string{
 			"wnc_ap_air_quality_index_avg",
 			"wnc_ap_air_quality_index_min",
@@ -274,6 +344,10 @@ func TestAllCollectors_OmitSeriesWhenDataTypeFails(t *testing.T) {
 		{typeWLANClientStats, []string{
 			"wnc_wlan_data_usage_bytes_total", "wnc_wlan_onboarding_clients",
 		}},
+		{typeWLANAVCStats, []string{
+			"wnc_wlan_application_bytes_total", "wnc_wlan_application_packets_total",
+			"wnc_wlan_application_other_bytes", "wnc_wlan_application_other_packets",
+		}},
 		{typeWLANPolicies, policyDerived},
 		{typeWLANPolicyListEntries, policyDerived},
 	}
@@ -402,18 +476,30 @@ func fixtureCollectors(t *testing.T, data *wnc.WNCDataCache) []prometheus.Collec
 
 	apMetrics := APMetrics{
 		General: true, Radio: true, Traffic: true, Errors: true, Join: true,
-		Spectrum: true, Info: true,
+		Uplink: true, Mesh: true, QoS: true, Restarts: true, Departed: true, DepartedRetention: time.Hour,
+		Neighbors: true, Spectrum: true, Interferers: true, Info: true,
+		NeighborsTopN: fixtureNeighborTopN,
+	}
+	clientMetrics := ClientMetrics{
+		General: true, Radio: true, Traffic: true, Errors: true, Devices: true, Onboarding: true,
+		Sessions: true, Info: true,
+	}
+	wlanMetrics := WLANMetrics{
+		General: true, Traffic: true, Config: true, Applications: true, ATF: true, Info: true,
+		ApplicationsTopN: fixtureApplicationTopN,
 	}
-	clientMetrics := ClientMetrics{General: true, Radio: true, Traffic: true, Errors: true, Info: true}
-	wlanMetrics := WLANMetrics{General: true, Traffic: true, Config: true, Info: true}
 
 	return []prometheus.Collector{
-		NewControllerCollector(wnc.NewControllerSource(src), ControllerMetrics{General: true}),
+		NewControllerCollector(wnc.NewControllerSource(src), ControllerMetrics{General: true, AAA: true}),
 		NewAPCollector(
 			wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src), apMetrics,
 		),
 		NewClientCollector(wnc.NewClientSource(src), clientMetrics),
 		NewWLANCollector(wnc.NewWLANSource(src), wnc.NewClientSource(src), wlanMetrics),
+		NewRRMCollector(
+			wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+			RRMMetrics{Channels: true},
+		),
 	}
 }
 
@@ -525,6 +611,43 @@ func fullFixtureSnapshot() *wnc.WNCDataCache {
 		NameMACMaps: []ap.ApNameMACMap{{WtpName: fixtureAPName, WtpMAC: fixtureAPMAC, EthMAC: fixtureAPMAC}},
 		JoinStats:   []ap.ApJoinStats{newFixtureJoinStats()},
 
+		// The AP CDP names also carries an LLDP entry, which must not be published beside
+		// the CDP one, and a second CDP entry for it whose link settings must not be read.
+		CDPNeighbors: []wnc.CDPNeighbor{
+			{
+				WtpMAC: fixtureAPMAC, DeviceID: "access-sw01", PortID: "GigabitEthernet1/0/1",
+				Platform: "cisco C9300-48P", Duplex: "full", InterfaceSpeed: "2500",
+			},
+			{
+				WtpMAC: fixtureAPMAC, DeviceID: "access-sw01", PortID: "GigabitEthernet1/0/1",
+				Platform: "cisco C9300-48P", Duplex: "half", InterfaceSpeed: "10",
+			},
+		},
+		LLDPNeighbors: []wnc.LLDPNeighbor{
+			{WtpMAC: fixtureAPMAC, SystemName: "access-sw01", PortID: "Gi1/0/1"},
+			{WtpMAC: fixtureLLDPOnlyAPMAC, NeighborMAC: "00:11:22:33:44:55", PortID: "ge-0/0/7"},
+		},
+		APPowerInfo: []wnc.APPowerInfo{{WtpMAC: fixtureAPMAC, Status: "low-power"}},
+		// The fixture AP is the root, and the neighbor radio a mesh AP one hop below it,
+		// so every mesh series has a sample and the link readings come from the child.
+		MeshAPs: []wnc.MeshAPOperData{
+			{WtpMAC: fixtureAPMAC, Role: "root-ap", BackhaulSlotID: 1, BackhaulChannel: 36},
+			{
+				WtpMAC: fixtureNeighborAPMAC, Role: "mesh-ap", ParentMAC: fixtureAPMAC, HopCount: 1,
+				BackhaulSlotID: 1, BackhaulChannel: 36, LinkSNR: 41, BackhaulDataRate: "866",
+			},
+		},
+		RadioWMMStats: []wnc.RadioWMMStats{{
+			WtpMAC: fixtureAPMAC, RadioSlotID: 1, AccessCategory: "voice",
+			TxFrames: "8401", QueueDrops: "8402",
+		}},
+		// The used airtime is in microseconds and the allocation in percent, so each
+		// lands on a value the unit conversion alone produces.
+		RadioATFStats: []wnc.RadioATFStats{{
+			WtpMAC: fixtureAPMAC, RadioSlotID: 1, WlanID: 1, PolicyName: "voice-first",
+			AirtimeAllocation: "40", AirtimeUsed: "7301000",
+		}},
+
 		ControllerBootTime: fixtureBootTime,
 		ClientDeleteReasons: map[string]float64{
 			fixtureDeleteReason:      6101,
@@ -541,6 +664,16 @@ func fullFixtureSnapshot() *wnc.WNCDataCache {
 			"roam-fail":                6205,
 			"dot11r-roam":              6206,
 		},
+		// Every counter carries a distinct number, so a descriptor pointed at the wrong
+		// leaf reports a value the assertions do not expect.
+		AAAServers: []wnc.AAARadiusServer{{
+			GroupName: "radius-group", ServerIP: "192.168.255.50", ServerName: "radius01",
+			AuthPort: 1812, AcctPort: 1813, State: "up",
+			AuthRequests: "6301", AuthAccepts: "6302", AuthRejects: "6303",
+			AuthTimeouts: "6304", AuthRetransmits: "6305",
+			AcctRequests: "6311", AcctResponses: "6312", AcctTimeouts: "6313",
+			AcctRetransmits: "6314", ResponseTime: "25",
+		}},
 
 		CommonOperData: []client.CommonOperData{{
 			ClientMAC:   fixtureClientMAC,
@@ -710,6 +843,41 @@ func fullFixtureSnapshot() *wnc.WNCDataCache {
 			{BandID: 0, ChannelNum: 33, MinAqi: 8401, Aqi: 8402, TotalIntfDeviceCount: 8403},
 			{BandID: 4, ChannelNum: 55, MinAqi: 8501, Aqi: 8502, TotalIntfDeviceCount: 8503},
 		},
+		// The fixture radio hears one neighbor more than the cap, each on its own channel,
+		// plus an entry the controller left unreported. The channels sort against the
+		// strength, so the first sample value_test.go reads is the strongest neighbor only
+		// if the ranking is right. The second record hears the AP the name map names.
+		RRMNeighbors: []wnc.RRMNeighborData{
+			{
+				WtpMAC: fixtureAPMAC, RadioSlotID: 0,
+				NeighborRadioInfo: wnc.RRMNeighborRadioInfo{NeighborRadioList: []wnc.RRMNeighborRadioEntry{
+					{NeighborRadioInfo: wnc.RRMNeighborRadio{
+						NeighborRadioMAC: "aa:bb:cc:dd:ef:21", RSSI: -80, Channel: 11,
+					}},
+					{NeighborRadioInfo: wnc.RRMNeighborRadio{
+						NeighborRadioMAC: "aa:bb:cc:dd:ef:22", RSSI: -61, Channel: fixtureChannel,
+					}},
+					{NeighborRadioInfo: wnc.RRMNeighborRadio{
+						NeighborRadioMAC: fixtureNeighborAPMAC, RSSI: -52, Channel: 1,
+					}},
+					{NeighborRadioInfo: wnc.RRMNeighborRadio{
+						NeighborRadioMAC: "aa:bb:cc:dd:ef:23", RSSI: 0, Channel: 1,
+					}},
+				}},
+			},
+			{
+				WtpMAC: fixtureNeighborAPMAC, RadioSlotID: 1,
+				NeighborRadioInfo: wnc.RRMNeighborRadioInfo{NeighborRadioList: []wnc.RRMNeighborRadioEntry{
+					{NeighborRadioInfo: wnc.RRMNeighborRadio{
+						NeighborRadioMAC: fixtureAPMAC, RSSI: -55, Channel: fixtureChannel,
+					}},
+				}},
+			},
+		},
+		SpectrumDevices: []wnc.SpectrumDevice{{
+			DeviceID: "7", WtpMAC: fixtureAPMAC, RadioSlotID: 0, Channel: fixtureChannel,
+			DeviceType: "si-dev-type-video-camera", Severity: 46, DutyCycle: 35,
+		}},
 		ApDot11RadarData: []rrm.ApDot11RadarData{{
 			WtpMAC:           fixtureAPMAC,
 			RadioSlotID:      0,
@@ -731,6 +899,12 @@ func fullFixtureSnapshot() *wnc.WNCDataCache {
 			CurrStateWebauthPending: 7106,
 			ClientCurrStateRun:      7107,
 		}},
+		// The heavier application sorts first, so its two counters are the first samples;
+		// the lighter one is folded into the other bucket, which sorts after it.
+		WLANAppStats: []wnc.WLANAppStats{
+			{WlanID: 1, AppName: "ms-teams", Direction: "ingress", Bytes: "7203", Packets: "7204"},
+			{WlanID: 1, AppName: "youtube", Direction: "ingress", Bytes: "7201", Packets: "7202"},
+		},
 		WLANConfigEntries: []wlan.WlanCfgEntry{{
 			WlanID:         1,
 			ProfileName:    fixtureProfile,
@@ -17,22 +17,46 @@ import (
 
 // APMetrics represents which AP metrics are enabled.
 type APMetrics struct {
-	General    bool
-	Radio      bool
-	Traffic    bool
-	Errors     bool
-	Join       bool
-	Spectrum   bool
-	Info       bool
-	InfoLabels []string
+	General     bool
+	Radio       bool
+	Traffic     bool
+	Errors      bool
+	Join        bool
+	Uplink      bool
+	Mesh        bool
+	QoS         bool
+	Restarts    bool
+	Departed    bool
+	Neighbors   bool
+	Spectrum    bool
+	Interferers bool
+	Info        bool
+	InfoLabels  []string
+
+	// NeighborsTopN bounds the neighbors the neighbors module publishes per radio.
+	NeighborsTopN int
+
+	// DepartedRetention is how long the departed module keeps reporting an AP after it
+	// leaves the inventory, and DepartedStateFile where the departed module keeps the record across restarts. An
+	// empty file keeps the record in memory only.
+	DepartedRetention time.Duration
+	DepartedStateFile string
 }
 
 // APCollector implements prometheus.Collector for AP metrics from WNC.
 type APCollector struct {
 	metrics        APMetrics
+	families       *familySet
 	infoDesc       *prometheus.Desc
 	infoLabelNames []string
 	join           *apJoinDescs
+	uplink         *apUplinkDescs
+	mesh           *apMeshDescs
+	qos            *apQoSDescs
+	restarts       *apRestartDescs
+	departed       *apDepartedDescs
+	neighbors      *apNeighborDescs
+	interferers    *apInterfererDescs
 	band           *apBandDescs
 	rrmRuns        *apRRMDescs
 	src            wnc.APSource
@@ -95,9 +119,11 @@ func NewAPCollector(
 ) *APCollector {
 	baseRadioLabels := []string{labelMAC, labelRadio}
 	baseAPLabels := []string{labelMAC}
+	families := &familySet{}
 
 	collector := &APCollector{
 		metrics:   metrics,
+		families:  families,
 		src:       src,
 		rrmSrc:    rrmSrc,
 		clientSrc: clientSrc,
@@ -107,181 +133,209 @@ func NewAPCollector(
 		requiredLabels := []string{labelMAC, labelRadio}
 		availableLabels := []string{labelName, labelIP, labelBand, labelModel, labelSerial, labelSWVersion, labelEthMAC}
 		infoLabels := buildInfoLabels(requiredLabels, metrics.InfoLabels, availableLabels)
-		collector.infoDesc = prometheus.NewDesc(
+		collector.infoDesc = families.gauge(
 			"wnc_ap_info",
 			"AP information labels for joining with other metrics, including device details",
-			infoLabels, nil,
+			infoLabels, wnc.DataAPCAPWAPData, wnc.DataAPRadioOperData,
 		)
 		collector.infoLabelNames = infoLabels
 	}
 
 	if metrics.Join {
-		collector.join = newAPJoinDescs()
+		collector.join = newAPJoinDescs(families)
+	}
+
+	if metrics.Uplink {
+		collector.uplink = newAPUplinkDescs(families)
+	}
+
+	if metrics.Mesh {
+		collector.mesh = newAPMeshDescs(families)
+	}
+
+	if metrics.QoS {
+		collector.qos = newAPQoSDescs(families)
+	}
+
+	if metrics.Restarts {
+		collector.restarts = newAPRestartDescs(families)
+	}
+
+	if metrics.Departed {
+		collector.departed = newAPDepartedDescs(families, metrics.DepartedRetention, metrics.DepartedStateFile)
+	}
+
+	if metrics.Neighbors {
+		collector.neighbors = newAPNeighborDescs(families, metrics.NeighborsTopN)
+	}
+
+	if metrics.Interferers {
+		collector.interferers = newAPInterfererDescs(families)
 	}
 
 	if metrics.General {
-		collector.radioStateDesc = prometheus.NewDesc(
+		collector.radioStateDesc = families.gauge(
 			"wnc_ap_radio_state",
 			"Radio state (0=down, 1=up). Absent for a slot whose state the controller "+
 				"does not report, so a slot that is not a radio reads as no series",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
-		collector.adminStateDesc = prometheus.NewDesc(
+		collector.adminStateDesc = families.gauge(
 			"wnc_ap_admin_state",
 			"Admin state (1=enabled, 0=any other value). Absent for a slot whose state "+
 				"the controller does not report",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
-		collector.operStateDesc = prometheus.NewDesc(
+		collector.operStateDesc = families.gauge(
 			"wnc_ap_oper_state",
 			"AP operational state, as the value the controller's own enumeration assigns its "+
 				"spelling (1=ap-down, 2=ap-up, 3=unregistered, 4=registered, 5=downloading, "+
 				"6=pre-downloading). The enumeration declares no 0, and a larger value is not a "+
 				"healthier state, so match by equality",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData,
 		)
-		collector.configStateDesc = prometheus.NewDesc(
+		collector.configStateDesc = families.gauge(
 			"wnc_ap_config_state",
 			"Configuration state (0=valid, 1=invalid) from IsApMisconfigured",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData,
 		)
-		collector.uptimeSecondsDesc = prometheus.NewDesc(
+		collector.uptimeSecondsDesc = families.gauge(
 			"wnc_ap_uptime_seconds",
 			"AP uptime in seconds. Withheld rather than reported as 0 when the controller "+
 				"reports no boot time this exporter can use, so a reboot check has no reading "+
 				"instead of a false one",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData,
 		)
-		collector.associationUptimeSecondsDesc = prometheus.NewDesc(
+		collector.associationUptimeSecondsDesc = families.gauge(
 			"wnc_ap_association_uptime_seconds",
 			"Seconds since the CAPWAP association this AP currently holds began. It is "+
 				"withheld rather than reported as 0 where the controller reports no join "+
 				"time this exporter can use",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData,
 		)
 	}
 
 	if metrics.Radio {
-		collector.channelDesc = prometheus.NewDesc(
+		collector.channelDesc = families.gauge(
 			"wnc_ap_channel_number",
 			"Operating channel number",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
-		collector.channelWidthDesc = prometheus.NewDesc(
+		collector.channelWidthDesc = families.gauge(
 			"wnc_ap_channel_width_mhz",
 			"Channel bandwidth (MHz)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
-		collector.txPowerDesc = prometheus.NewDesc(
+		collector.txPowerDesc = families.gauge(
 			"wnc_ap_tx_power_dbm",
 			"Current transmit power (dBm)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
-		collector.txPowerMaxDesc = prometheus.NewDesc(
+		collector.txPowerMaxDesc = families.gauge(
 			"wnc_ap_tx_power_max_dbm",
 			"Maximum TX power capability (dBm)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData,
 		)
 	}
 
 	if metrics.Radio {
-		collector.channelUtilizationDesc = prometheus.NewDesc(
+		collector.channelUtilizationDesc = families.gauge(
 			"wnc_ap_channel_utilization_ratio",
 			"Channel utilization ratio (CCA-based, 0-1)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
 		)
-		collector.rxUtilizationDesc = prometheus.NewDesc(
+		collector.rxUtilizationDesc = families.gauge(
 			"wnc_ap_rx_utilization_ratio",
 			"RX utilization ratio (0-1)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
 		)
-		collector.txUtilizationDesc = prometheus.NewDesc(
+		collector.txUtilizationDesc = families.gauge(
 			"wnc_ap_tx_utilization_ratio",
 			"TX utilization ratio (0-1)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
 		)
-		collector.noiseUtilizationDesc = prometheus.NewDesc(
+		collector.noiseUtilizationDesc = families.gauge(
 			"wnc_ap_noise_utilization_ratio",
 			"Noise channel utilization ratio (0-1)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
 		)
-		collector.noiseFloorDesc = prometheus.NewDesc(
+		collector.noiseFloorDesc = families.gauge(
 			"wnc_ap_noise_floor_dbm",
 			"Channel noise floor (dBm)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
 		)
-		collector.associatedClientsDesc = prometheus.NewDesc(
+		collector.associatedClientsDesc = families.gauge(
 			"wnc_ap_clients",
 			"Number of clients in the run state on this radio",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataClientCommonOperData, wnc.DataAPNameMACMap,
 		)
-		collector.rrmProfilePassedDesc = prometheus.NewDesc(
+		collector.rrmProfilePassedDesc = families.gauge(
 			"wnc_ap_rrm_profile_passed",
 			"Whether the radio passes this RRM profile (1=passed, 0=failed or the "+
 				"verdict was not reported)",
 			[]string{labelMAC, labelRadio, labelProfile},
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMRadioSlot,
 		)
-		collector.channelEnergyDesc = prometheus.NewDesc(
+		collector.channelEnergyDesc = families.gauge(
 			"wnc_ap_channel_energy_dbm",
 			"Energy the controller measured on the channel it assigned this radio, from its "+
 				"DCA statistics. It is a step: the reading holds until DCA next runs for "+
 				"that band",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMRadioSlot,
 		)
-		collector.channelChangesTotalDesc = prometheus.NewDesc(
+		collector.channelChangesTotalDesc = families.counter(
 			"wnc_ap_channel_changes_total",
 			"Channel changes on this radio, from the controller's DCA assignment statistics. "+
 				"It resets, so read it with rate() rather than as a lifetime total",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMRadioSlot,
 		)
-		collector.rrmRuns = newAPRRMDescs()
+		collector.rrmRuns = newAPRRMDescs(families)
 	}
 
 	if metrics.Spectrum {
-		collector.airQualityDesc = prometheus.NewDesc(
+		collector.airQualityDesc = families.gauge(
 			"wnc_ap_air_quality_index_avg",
 			"Average CleanAir air quality index of the channel the radio operates on, over "+
 				"the air quality reporting period the controller declares. A higher index is "+
 				"cleaner, and the controller's own alarm threshold is a lower bound on it",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMSpectrumAqTable,
 		)
-		collector.airQualityMinDesc = prometheus.NewDesc(
+		collector.airQualityMinDesc = families.gauge(
 			"wnc_ap_air_quality_index_min",
 			"Lowest CleanAir air quality index the controller saw on the channel the radio "+
 				"operates on, over the same reporting period as the average. It never "+
 				"exceeds the average, and a higher index is cleaner",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMSpectrumAqTable,
 		)
-		collector.interferersDesc = prometheus.NewDesc(
+		collector.interferersDesc = families.gauge(
 			"wnc_ap_interferers",
 			"Interference devices CleanAir attributes to the channel the radio operates on. "+
 				"Zero is a reading rather than a missing one, and the series is absent "+
 				"instead where no reading can be reached",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMSpectrumAqTable,
 		)
-		collector.lastAirQualityAtDesc = prometheus.NewDesc(
+		collector.lastAirQualityAtDesc = families.gauge(
 			"wnc_ap_last_air_quality_timestamp_seconds",
 			"Instant the controller reports for the CleanAir row this radio's air quality and "+
 				"interferer series read, in Unix seconds. An instant that does not advance means "+
@@ -289,174 +343,179 @@ func NewAPCollector(
 				"that reported instant. It is withheld rather than reported as 0 where the "+
 				"controller carries no instant this exporter can use",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMSpectrumAqTable,
 		)
-		collector.band = newAPBandDescs()
+		collector.band = newAPBandDescs(families)
 	}
 
 	if metrics.General {
-		collector.cpuUtilizationDesc = prometheus.NewDesc(
+		collector.cpuUtilizationDesc = families.gauge(
 			"wnc_ap_cpu_utilization_ratio",
 			"CPU utilization ratio (0-1)",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData, wnc.DataAPOperData,
 		)
-		collector.memoryUtilizationDesc = prometheus.NewDesc(
+		collector.memoryUtilizationDesc = families.gauge(
 			"wnc_ap_memory_utilization_ratio",
 			"Memory utilization ratio (0-1)",
 			baseAPLabels,
-			nil,
+			wnc.DataAPCAPWAPData, wnc.DataAPOperData,
 		)
 	}
 
 	if metrics.Traffic {
-		collector.dataRxFramesTotalDesc = prometheus.NewDesc(
+		collector.dataRxFramesTotalDesc = families.counter(
 			"wnc_ap_data_rx_frames_total",
 			"Data RX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.dataTxFramesTotalDesc = prometheus.NewDesc(
+		collector.dataTxFramesTotalDesc = families.counter(
 			"wnc_ap_data_tx_frames_total",
 			"Data TX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.managementRxFramesTotalDesc = prometheus.NewDesc(
+		collector.managementRxFramesTotalDesc = families.counter(
 			"wnc_ap_management_rx_frames_total",
 			"Management RX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.managementTxFramesTotalDesc = prometheus.NewDesc(
+		collector.managementTxFramesTotalDesc = families.counter(
 			"wnc_ap_management_tx_frames_total",
 			"Management TX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.controlRxFramesTotalDesc = prometheus.NewDesc(
+		collector.controlRxFramesTotalDesc = families.counter(
 			"wnc_ap_control_rx_frames_total",
 			"Control RX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.controlTxFramesTotalDesc = prometheus.NewDesc(
+		collector.controlTxFramesTotalDesc = families.counter(
 			"wnc_ap_control_tx_frames_total",
 			"Control TX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.multicastRxFramesTotalDesc = prometheus.NewDesc(
+		collector.multicastRxFramesTotalDesc = families.counter(
 			"wnc_ap_multicast_rx_frames_total",
 			"Multicast RX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.multicastTxFramesTotalDesc = prometheus.NewDesc(
+		collector.multicastTxFramesTotalDesc = families.counter(
 			"wnc_ap_multicast_tx_frames_total",
 			"Multicast TX frames",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.totalTxFramesTotalDesc = prometheus.NewDesc(
+		collector.totalTxFramesTotalDesc = families.counter(
 			"wnc_ap_total_tx_frames_total",
 			"TX frames as the controller counts them, not the sum of the per-type series",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.rtsSuccessesTotalDesc = prometheus.NewDesc(
+		collector.rtsSuccessesTotalDesc = families.counter(
 			"wnc_ap_rts_successes_total",
 			"Successful RTS transmissions",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
 	}
 
 	if metrics.Errors {
-		collector.rxErrorsTotalDesc = prometheus.NewDesc(
+		collector.rxErrorsTotalDesc = families.counter(
 			"wnc_ap_rx_errors_total",
 			"Total RX errors (rx-error-frame-count)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.txRetriesTotalDesc = prometheus.NewDesc(
+		collector.txRetriesTotalDesc = families.counter(
 			"wnc_ap_tx_retries_total",
 			"Total TX retries",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.transmissionFailuresTotalDesc = prometheus.NewDesc(
+		collector.transmissionFailuresTotalDesc = families.counter(
 			"wnc_ap_transmission_failures_total",
 			"Failed transmission attempts",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.duplicateFramesTotalDesc = prometheus.NewDesc(
+		collector.duplicateFramesTotalDesc = families.counter(
 			"wnc_ap_duplicate_frames_total",
 			"Duplicate frames received",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.fcsErrorsTotalDesc = prometheus.NewDesc(
+		collector.fcsErrorsTotalDesc = families.counter(
 			"wnc_ap_fcs_errors_total",
 			"Frame Check Sequence errors",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.rxFragmentsTotalDesc = prometheus.NewDesc(
+		collector.rxFragmentsTotalDesc = families.counter(
 			"wnc_ap_rx_fragments_total",
 			"RX fragments",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.txFragmentsTotalDesc = prometheus.NewDesc(
+		collector.txFragmentsTotalDesc = families.counter(
 			"wnc_ap_tx_fragments_total",
 			"TX fragments",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.rtsFailuresTotalDesc = prometheus.NewDesc(
+		collector.rtsFailuresTotalDesc = families.counter(
 			"wnc_ap_rts_failures_total",
 			"RTS failures",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.decryptionErrorsTotalDesc = prometheus.NewDesc(
+		collector.decryptionErrorsTotalDesc = families.counter(
 			"wnc_ap_decryption_errors_total",
 			"Decryption errors",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.micErrorsTotalDesc = prometheus.NewDesc(
+		collector.micErrorsTotalDesc = families.counter(
 			"wnc_ap_mic_errors_total",
 			"MIC errors",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioOperStats,
 		)
-		collector.coverageFailedClientsDesc = prometheus.NewDesc(
+		collector.coverageFailedClientsDesc = families.gauge(
 			"wnc_ap_coverage_failed_clients",
 			"RRM coverage failed client count (current value, not cumulative)",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMCoverage,
 		)
-		collector.lastRadarOnRadioAtDesc = prometheus.NewDesc(
+		collector.lastRadarOnRadioAtDesc = families.gauge(
 			"wnc_ap_last_radar_timestamp_seconds",
 			"Unix timestamp of the last radar detection on this radio",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataRRMAPDot11RadarData,
 		)
-		collector.radioResetsTotalDesc = prometheus.NewDesc(
+		collector.radioResetsTotalDesc = families.counter(
 			"wnc_ap_radio_resets_total",
 			"Radio resets counted per cause and totalled for this radio. The controller "+
 				"deletes cause entries and this total then falls, so read it with rate() "+
 				"rather than as a lifetime total",
 			baseRadioLabels,
-			nil,
+			wnc.DataAPRadioOperData, wnc.DataAPRadioResetStats,
 		)
 	}
 	return collector
 }
 
+// declaredFamilies returns the families of the enabled modules.
+func (c *APCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
 // Describe implements prometheus.Collector.
 func (c *APCollector) Describe(ch chan<- *prometheus.Desc) {
 	if c.metrics.General {
@@ -515,6 +574,24 @@ func (c *APCollector) Describe(ch chan<- *prometheus.Desc) {
 	if c.metrics.Join {
 		c.join.describe(ch)
 	}
+	if c.metrics.Uplink {
+		c.uplink.describe(ch)
+	}
+	if c.metrics.Mesh {
+		c.mesh.describe(ch)
+	}
+	if c.metrics.QoS {
+		c.qos.describe(ch)
+	}
+	if c.metrics.Restarts {
+		c.restarts.describe(ch)
+	}
+	if c.metrics.Departed {
+		c.departed.describe(ch)
+	}
+	if c.metrics.Neighbors {
+		c.neighbors.describe(ch)
+	}
 	if c.metrics.Spectrum {
 		ch <- c.airQualityDesc
 		ch <- c.airQualityMinDesc
@@ -522,6 +599,9 @@ func (c *APCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- c.lastAirQualityAtDesc
 		c.band.describe(ch)
 	}
+	if c.metrics.Interferers {
+		c.interferers.describe(ch)
+	}
 	if c.metrics.Info {
 		ch <- c.infoDesc
 	}
@@ -535,18 +615,66 @@ func (c *APCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	// joinPublished names the APs the join module published wnc_ap_joined for, which
+	// the departed module must not publish it for again.
+	var joinPublished map[string]bool
 	if c.metrics.Join {
 		joinStats, err := c.src.GetAPJoinStats(ctx)
 		if err != nil {
 			slog.Debug("Failed to get AP join statistics", "error", err)
 		} else {
 			c.join.collect(ch, joinStats)
+			joinPublished = buildJoinPublishedSet(joinStats)
 		}
 	}
 
-	// Every module below reads the AP inventory or the radio list. The join module
-	// reads neither, so a deployment enabling only that one must not go on to ask for
-	// data types no enabled module declared.
+	if c.metrics.Uplink {
+		c.uplink.collect(ch, c.readUplink(ctx))
+	}
+
+	if c.metrics.Mesh {
+		meshAPs, err := c.src.GetMeshAPs(ctx)
+		if err != nil {
+			slog.Debug("Failed to get mesh APs for mesh metrics", "error", err)
+		} else {
+			c.mesh.collect(ch, meshAPs)
+		}
+	}
+
+	if c.metrics.QoS {
+		wmmStats, err := c.src.GetRadioWMMStats(ctx)
+		if err != nil {
+			slog.Debug("Failed to get WMM statistics for QoS metrics", "error", err)
+		} else {
+			c.qos.collect(ch, wmmStats)
+		}
+	}
+
+	if c.metrics.Restarts {
+		c.collectRestarts(ctx, ch)
+	}
+
+	if c.metrics.Departed {
+		c.collectDeparted(ctx, ch, joinPublished)
+	}
+
+	if c.metrics.Neighbors {
+		c.neighbors.collect(ch, c.readNeighbors(ctx))
+	}
+
+	if c.metrics.Interferers {
+		devices, err := c.rrmSrc.GetSpectrumDevices(ctx)
+		if err != nil {
+			slog.Debug("Failed to get CleanAir devices for interferer metrics", "error", err)
+		} else {
+			c.interferers.collect(ch, devices)
+		}
+	}
+
+	// Every module below reads the AP inventory and the radio list. The join, uplink,
+	// mesh, qos, restarts, departed, neighbors and interferers modules read no radio list,
+	// so a deployment enabling only those must not go on to ask for data types no enabled
+	// module declared.
 	if !c.isAnyRadioKeyedFlagEnabled() {
 		return
 	}
@@ -661,6 +789,66 @@ func (c *APCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+// buildJoinPublishedSet returns the MACs the join module keys wnc_ap_joined by.
+func buildJoinPublishedSet(joinStats []ap.ApJoinStats) map[string]bool {
+	published := make(map[string]bool, len(joinStats))
+	for i := range joinStats {
+		published[joinStats[i].WtpMAC] = true
+	}
+	return published
+}
+
+// collectRestarts reads the inventory and the join statistics for the restarts module.
+//
+// The inventory is the one the general module reads, and the comparison needs it, so
+// nothing is counted while it cannot be read. The join statistics only name the reason,
+// so a snapshot without them still counts, under the unknown reason. The refresh time is
+// read before the lists, so a refresh landing between the reads replays the newer lists
+// under the older time, and the next scrape compares those lists with themselves.
+func (c *APCollector) collectRestarts(ctx context.Context, ch chan<- prometheus.Metric) {
+	refreshedAt, err := c.src.GetRefreshedAt(ctx)
+	if err != nil {
+		slog.Debug("Failed to get snapshot refresh time for restart metrics", "error", err)
+		return
+	}
+
+	capwapData, err := c.src.GetCAPWAPData(ctx)
+	if err != nil {
+		slog.Debug("Failed to get CAPWAP data for restart metrics", "error", err)
+		return
+	}
+
+	joinStats, err := c.src.GetAPJoinStats(ctx)
+	if err != nil {
+		slog.Debug("Failed to get AP join statistics for restart metrics", "error", err)
+	}
+
+	c.restarts.collect(ch, refreshedAt, capwapData, joinStats)
+}
+
+// collectDeparted reads the refresh time and the inventory for the departed module. As
+// for the restarts module, the refresh time is read before the list, and nothing is
+// recorded or published while either cannot be read.
+func (c *APCollector) collectDeparted(
+	ctx context.Context,
+	ch chan<- prometheus.Metric,
+	joinPublished map[string]bool,
+) {
+	refreshedAt, err := c.src.GetRefreshedAt(ctx)
+	if err != nil {
+		slog.Debug("Failed to get snapshot refresh time for departed AP metrics", "error", err)
+		return
+	}
+
+	capwapData, err := c.src.GetCAPWAPData(ctx)
+	if err != nil {
+		slog.Debug("Failed to get CAPWAP data for departed AP metrics", "error", err)
+		return
+	}
+
+	c.departed.collect(ch, refreshedAt, capwapData, joinPublished)
+}
+
 // collectSystemMetrics collects AP system metrics.
 func (c *APCollector) collectSystemMetrics(
 	ch chan<- prometheus.Metric,
@@ -906,6 +1094,54 @@ func (c *APCollector) readSpectrum(ctx context.Context) spectrumReads {
 	return reads
 }
 
+// readUplink reads the three data types the uplink module publishes from. Each keeps its
+// own absence rule, so one failing does not withhold the others.
+func (c *APCollector) readUplink(ctx context.Context) uplinkReads {
+	var reads uplinkReads
+	var err error
+
+	reads.cdp, err = c.src.GetCDPNeighbors(ctx)
+	if err != nil {
+		slog.Debug("Failed to get CDP neighbors for uplink metrics", "error", err)
+	}
+	reads.cdpOK = err == nil
+
+	reads.lldp, err = c.src.GetLLDPNeighbors(ctx)
+	if err != nil {
+		slog.Debug("Failed to get LLDP neighbors for uplink metrics", "error", err)
+	}
+	reads.lldpOK = err == nil
+
+	reads.power, err = c.src.GetPowerInfo(ctx)
+	if err != nil {
+		slog.Debug("Failed to get AP power status for uplink metrics", "error", err)
+	}
+	reads.powerOK = err == nil
+
+	return reads
+}
+
+// readNeighbors reads the two data types the neighbors module publishes from. Each keeps
+// its own absence rule, so a failed name map withholds the names and nothing else.
+func (c *APCollector) readNeighbors(ctx context.Context) neighborReads {
+	var reads neighborReads
+	var err error
+
+	reads.neighbors, err = c.rrmSrc.GetNeighbors(ctx)
+	if err != nil {
+		slog.Debug("Failed to get RRM neighbors for neighbor metrics", "error", err)
+	}
+	reads.neighborsOK = err == nil
+
+	reads.names, err = c.src.ListNameMACMaps(ctx)
+	if err != nil {
+		slog.Debug("Failed to get AP name map for neighbor metrics", "error", err)
+	}
+	reads.namesOK = err == nil
+
+	return reads
+}
+
 // readRadioJoins reads the four data types the radio module publishes from. Each keeps
 // its own absence rule, so one failing does not withhold the others.
 func (c *APCollector) readRadioJoins(ctx context.Context) radioJoins {
@@ -1252,19 +1488,29 @@ func buildRadioClientCountsMap(
 // controller meant by it. This is the same guard the join module applies to its own
 // timestamps.
 func determineUptimeFromTimestamp(timestamp string) (int64, bool) {
-	instant, err := time.Parse(time.RFC3339, timestamp)
-	if err != nil {
-		return 0, false
-	}
-	if instant.Year() <= epochYear {
+	instant, ok := parseAPTimestamp(timestamp)
+	if !ok {
 		return 0, false
 	}
 
 	return int64(time.Since(instant).Seconds()), true
 }
 
+// parseAPTimestamp parses a timestamp leaf of the CAPWAP record, and reports false when
+// the leaf is absent, unparsable, or at the Unix epoch.
+func parseAPTimestamp(timestamp string) (time.Time, bool) {
+	instant, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil || instant.Year() <= epochYear {
+		return time.Time{}, false
+	}
+
+	return instant, true
+}
+
 func (c *APCollector) isAnyMetricFlagEnabled() bool {
-	return c.isAnyRadioKeyedFlagEnabled() || c.metrics.Join
+	return c.isAnyRadioKeyedFlagEnabled() || c.metrics.Join || c.metrics.Uplink ||
+		c.metrics.Mesh || c.metrics.QoS || c.metrics.Restarts || c.metrics.Departed ||
+		c.metrics.Neighbors || c.metrics.Interferers
 }
 
 // isAnyRadioKeyedFlagEnabled reports whether a module keyed by the AP inventory or
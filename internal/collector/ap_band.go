@@ -6,6 +6,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/rrm"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
 )
 
 // apBandDescs holds the descriptors of the band-keyed air quality series. A nil value
@@ -23,34 +24,34 @@ type apBandDescs struct {
 // The band is the whole identifier. The controller ranks the channels of a band across
 // every AP that scans it and keeps one row per band, so neither the AP nor the radio the
 // reading was taken on keys the row.
-func newAPBandDescs() *apBandDescs {
+func newAPBandDescs(families *familySet) *apBandDescs {
 	bandLabels := []string{labelBand}
 
 	return &apBandDescs{
-		worstAirQuality: prometheus.NewDesc(
+		worstAirQuality: families.gauge(
 			"wnc_rrm_worst_channel_air_quality_index_avg",
 			"Average CleanAir air quality index of the channel the controller ranks worst in "+
 				"this band, over the air quality reporting period. Higher is cleaner, and "+
 				"wnc_rrm_worst_channel_number reports which channel it is",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMSpectrumAqWorst,
 		),
-		worstAirQualityMin: prometheus.NewDesc(
+		worstAirQualityMin: families.gauge(
 			"wnc_rrm_worst_channel_air_quality_index_min",
 			"Lowest CleanAir air quality index the controller saw on that channel during the "+
 				"same reporting period, which the average does not carry",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMSpectrumAqWorst,
 		),
-		worstInterferers: prometheus.NewDesc(
+		worstInterferers: families.gauge(
 			"wnc_rrm_worst_channel_interferers",
 			"Interference devices CleanAir counts on the channel the controller ranks worst in "+
 				"this band",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMSpectrumAqWorst,
 		),
-		worstChannel: prometheus.NewDesc(
+		worstChannel: families.gauge(
 			"wnc_rrm_worst_channel_number",
 			"Channel the controller ranks worst in this band. It is a reading rather than a "+
 				"label, so a change moves the value instead of starting a new series",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMSpectrumAqWorst,
 		),
 	}
 }
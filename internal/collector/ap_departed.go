@@ -0,0 +1,176 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the departed AP module of the AP collector.
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// apDepartedDescs holds the state of the departed module. A nil value means the module
+// is disabled, which is what keeps every series of it out of a default scrape.
+//
+// Like the restarts module it keeps state across snapshots: an AP that leaves the
+// inventory takes every series derived from it along, so the module remembers when each
+// AP was last seen and keeps reporting it, for the retention, after it has gone.
+type apDepartedDescs struct {
+	joined   *prometheus.Desc
+	lastSeen *prometheus.Desc
+
+	retention time.Duration
+	// stateFile is where the record is kept across restarts. Empty keeps it in memory.
+	stateFile string
+
+	mu    sync.Mutex
+	clock snapshotClock
+	// seenAt maps each AP radio MAC to the refresh time of the last snapshot that found
+	// it in the inventory.
+	seenAt map[string]time.Time
+}
+
+// newAPDepartedDescs builds the descriptors of the departed module and loads the record
+// the state file carries, if any.
+//
+// A state file that cannot be read starts an empty record rather than failing the
+// exporter, since what it loses is the departures that happened before the restart.
+func newAPDepartedDescs(families *familySet, retention time.Duration, stateFile string) *apDepartedDescs {
+	d := &apDepartedDescs{
+		joined: newAPJoinedDesc(families, wnc.DataAPCAPWAPData),
+		lastSeen: families.gauge(
+			"wnc_ap_last_seen_timestamp_seconds",
+			"Unix timestamp of the last refresh that found this AP in the inventory. It "+
+				"follows the refresh while the AP is listed, and stops for the retention "+
+				"after the AP leaves, after which the series is dropped",
+			[]string{labelMAC}, wnc.DataAPCAPWAPData,
+		),
+		retention: retention,
+		stateFile: stateFile,
+		seenAt:    make(map[string]time.Time),
+	}
+
+	if stateFile != "" {
+		if err := d.load(); err != nil {
+			slog.Warn("Failed to load departed AP state, starting empty",
+				"file", stateFile, "error", err)
+		}
+	}
+
+	return d
+}
+
+// describe sends every descriptor of the departed module.
+func (d *apDepartedDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.joined
+	ch <- d.lastSeen
+}
+
+// collect records the APs the inventory of a snapshot not recorded yet lists, and
+// publishes every AP seen within the retention.
+//
+// An AP listed now reports the refresh behind the snapshot as its last-seen time, and
+// one that has left reports the refresh that last found it, along with wnc_ap_joined at
+// 0 unless the join module already publishes that series from the join record the AP
+// left behind, since Gather rejects a duplicate by failing the entire endpoint. An AP
+// past the retention is forgotten. The record is saved once per snapshot rather than
+// once per scrape, since only a new snapshot changes it.
+func (d *apDepartedDescs) collect(
+	ch chan<- prometheus.Metric,
+	refreshedAt time.Time,
+	capwapData []ap.CAPWAPData,
+	joinPublished map[string]bool,
+) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	present := make(map[string]bool, len(capwapData))
+	for i := range capwapData {
+		if mac := capwapData[i].WtpMAC; mac != "" {
+			present[mac] = true
+		}
+	}
+
+	if d.clock.advance(refreshedAt) {
+		d.record(refreshedAt, present)
+	}
+
+	for mac, seenAt := range d.seenAt {
+		ch <- prometheus.MustNewConstMetric(
+			d.lastSeen, prometheus.GaugeValue, float64(seenAt.Unix()), mac,
+		)
+		if !present[mac] && !joinPublished[mac] {
+			ch <- prometheus.MustNewConstMetric(d.joined, prometheus.GaugeValue, 0, mac)
+		}
+	}
+}
+
+// record stamps the APs listed now with the refresh time, forgets the ones past the
+// retention, and saves the record to the state file, if any.
+func (d *apDepartedDescs) record(refreshedAt time.Time, present map[string]bool) {
+	for mac := range present {
+		d.seenAt[mac] = refreshedAt
+	}
+
+	for mac, seenAt := range d.seenAt {
+		if !present[mac] && refreshedAt.Sub(seenAt) > d.retention {
+			delete(d.seenAt, mac)
+		}
+	}
+
+	if d.stateFile != "" {
+		if err := d.save(); err != nil {
+			slog.Warn("Failed to save departed AP state", "file", d.stateFile, "error", err)
+		}
+	}
+}
+
+// load reads the record from the state file. A file that does not exist yet is an
+// empty record rather than an error.
+func (d *apDepartedDescs) load() error {
+	content, err := os.ReadFile(d.stateFile) //nolint:gosec // The path is the operator's own flag.
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, &d.seenAt)
+}
+
+// save writes the record to the state file. It writes a temporary file beside it and
+// renames it over the old one, so a crash mid-write leaves the previous record rather
+// than a truncated one.
+func (d *apDepartedDescs) save() error {
+	content, err := json.Marshal(d.seenAt)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(d.stateFile), filepath.Base(d.stateFile)+".*")
+	if err != nil {
+		return err
+	}
+	// Once the rename has succeeded there is nothing left to remove.
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if _, err := temp.Write(content); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), d.stateFile)
+}
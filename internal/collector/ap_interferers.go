@@ -0,0 +1,130 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the CleanAir interferer module of the AP collector.
+package collector
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// interfererTypePrefix is the prefix every member of si-dev-type carries. The type label
+// drops it, since it repeats on every series and says nothing a query can select on.
+const interfererTypePrefix = "si-dev-type-"
+
+// apInterfererDescs holds the descriptors of the interferers module. A nil value means
+// the module is disabled, which is what keeps every series of it out of a default scrape.
+type apInterfererDescs struct {
+	devices   *prometheus.Desc
+	severity  *prometheus.Desc
+	dutyCycle *prometheus.Desc
+}
+
+// newAPInterfererDescs builds the descriptors of the interferers module.
+//
+// The devices a radio detects are counted by type rather than published one by one. The
+// identifier CleanAir assigns a device is a cluster identifier that changes as CleanAir
+// re-clusters its detections, so a series keyed by it would churn on every report.
+func newAPInterfererDescs(families *familySet) *apInterfererDescs {
+	labels := []string{labelMAC, labelRadio, labelChannel, labelType}
+
+	return &apInterfererDescs{
+		devices: families.gauge(
+			"wnc_ap_interferer_devices",
+			"Interferer devices of one type CleanAir detects on this radio and channel. The "+
+				"type is the controller's si-dev-type spelling without its prefix, such as "+
+				"microwave-oven, bt-link, video-camera, jammer or unclassified",
+			labels, wnc.DataRRMSpectrumDevice,
+		),
+		severity: families.gauge(
+			"wnc_ap_interferer_severity_max",
+			"Highest severity CleanAir assigns an interferer device of this type, 1-100, "+
+				"higher meaning more harm to Wi-Fi on the channel. Absent while the controller "+
+				"reports none",
+			labels, wnc.DataRRMSpectrumDevice,
+		),
+		dutyCycle: families.gauge(
+			"wnc_ap_interferer_duty_cycle_ratio_max",
+			"Highest share of time (0-1) an interferer device of this type is transmitting. "+
+				"Absent while the controller reports none",
+			labels, wnc.DataRRMSpectrumDevice,
+		),
+	}
+}
+
+// describe sends every descriptor of the interferers module.
+func (d *apInterfererDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.devices
+	ch <- d.severity
+	ch <- d.dutyCycle
+}
+
+// interfererGroup accumulates the devices of one type a radio detects on one channel.
+type interfererGroup struct {
+	devices   int
+	severity  int
+	dutyCycle int
+}
+
+// collect publishes the devices the CleanAir device table carries, counted by type.
+//
+// A record with no AP, no device identifier or no channel cannot be told apart from
+// another, so it is dropped, and a record repeated across pages is counted once. A
+// record with no type is counted as unknown rather than dropped, so the counts still
+// add up to what the radio hears. Severity and duty cycle are withheld at zero: the
+// controller leaves an unreported leaf at zero, and a device CleanAir still lists is
+// transmitting.
+func (d *apInterfererDescs) collect(ch chan<- prometheus.Metric, devices []wnc.SpectrumDevice) {
+	seen := make(map[[4]string]bool, len(devices))
+	groups := make(map[[4]string]*interfererGroup)
+
+	for i := range devices {
+		device := &devices[i]
+		if device.WtpMAC == "" || device.DeviceID == "" || device.Channel <= 0 {
+			continue
+		}
+
+		radio, channel := strconv.Itoa(device.RadioSlotID), strconv.Itoa(device.Channel)
+		key := [4]string{device.WtpMAC, radio, channel, device.DeviceID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		deviceType := strings.TrimPrefix(device.DeviceType, interfererTypePrefix)
+		if deviceType == "" {
+			deviceType = "unknown"
+		}
+
+		labels := [4]string{device.WtpMAC, radio, channel, deviceType}
+		group, ok := groups[labels]
+		if !ok {
+			group = &interfererGroup{}
+			groups[labels] = group
+		}
+		group.devices++
+		group.severity = max(group.severity, device.Severity)
+		group.dutyCycle = max(group.dutyCycle, device.DutyCycle)
+	}
+
+	for labels, group := range groups {
+		ch <- prometheus.MustNewConstMetric(
+			d.devices, prometheus.GaugeValue, float64(group.devices), labels[:]...,
+		)
+
+		if group.severity > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.severity, prometheus.GaugeValue, float64(group.severity), labels[:]...,
+			)
+		}
+
+		if group.dutyCycle > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.dutyCycle, prometheus.GaugeValue, float64(group.dutyCycle)/100, labels[:]...,
+			)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
 )
 
 // epochYear is the year of the sentinel the controller writes into a timestamp leaf
@@ -69,168 +70,161 @@ type apJoinDescs struct {
 // own series instead of as a label, because a bare and between a counter of this
 // module and wnc_ap_joined requires the two to carry identical label sets, and
 // because renaming an AP would otherwise start a fresh counter series.
-func newAPJoinDescs() *apJoinDescs {
+func newAPJoinDescs(families *familySet) *apJoinDescs {
 	apLabels := []string{labelMAC}
 	nameLabels := []string{labelMAC, labelName}
 	channelLabels := []string{labelMAC, labelChannel}
 
 	return &apJoinDescs{
-		joined: prometheus.NewDesc(
-			"wnc_ap_joined",
-			"Whether the AP holds a CAPWAP session with this controller now "+
-				"(0=not joined, 1=joined). The record outlives the session, so the join, "+
-				"configuration and DTLS series freeze while this reports 0, while the "+
-				"discovery series keep advancing for as long as the AP still reaches the controller",
-			apLabels, nil,
-		),
-		name: prometheus.NewDesc(
+		joined: newAPJoinedDesc(families, wnc.DataAPJoinStats),
+		name: families.gauge(
 			"wnc_ap_join_info",
 			"AP name as its CAPWAP join record reports it, always 1. The record outlives "+
 				"the session, so this names an AP the AP inventory no longer carries",
-			nameLabels, nil,
+			nameLabels, wnc.DataAPJoinStats,
 		),
 
-		discoveryRequests: prometheus.NewDesc(
+		discoveryRequests: families.counter(
 			"wnc_ap_discovery_requests_total",
 			"CAPWAP discovery requests received from this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		discoveryResponses: prometheus.NewDesc(
+		discoveryResponses: families.counter(
 			"wnc_ap_discovery_responses_total",
 			"Successful CAPWAP discovery responses sent to this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		discoveryErrors: prometheus.NewDesc(
+		discoveryErrors: families.counter(
 			"wnc_ap_discovery_errors_total",
 			"CAPWAP discovery requests from this AP the controller found in error",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		joinRequests: prometheus.NewDesc(
+		joinRequests: families.counter(
 			"wnc_ap_join_requests_total",
 			"CAPWAP join requests received from this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		joinResponses: prometheus.NewDesc(
+		joinResponses: families.counter(
 			"wnc_ap_join_responses_total",
 			"Successful CAPWAP join responses sent to this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		joinFailures: prometheus.NewDesc(
+		joinFailures: families.counter(
 			"wnc_ap_join_failures_total",
 			"CAPWAP join requests from this AP the controller failed to process",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		configRequests: prometheus.NewDesc(
+		configRequests: families.counter(
 			"wnc_ap_config_requests_total",
 			"CAPWAP configuration requests received from this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		configResponses: prometheus.NewDesc(
+		configResponses: families.counter(
 			"wnc_ap_config_responses_total",
 			"Successful CAPWAP configuration responses sent to this AP",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		configFailures: prometheus.NewDesc(
+		configFailures: families.counter(
 			"wnc_ap_config_failures_total",
 			"CAPWAP configuration requests from this AP the controller failed to process",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
 
-		dtlsRequests: prometheus.NewDesc(
+		dtlsRequests: families.counter(
 			"wnc_ap_dtls_session_requests_total",
 			"DTLS session setup requests received from this AP on the channel label",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		dtlsSuccesses: prometheus.NewDesc(
+		dtlsSuccesses: families.counter(
 			"wnc_ap_dtls_session_successes_total",
 			"DTLS sessions established with this AP on the channel label",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		dtlsFailures: prometheus.NewDesc(
+		dtlsFailures: families.counter(
 			"wnc_ap_dtls_session_failures_total",
 			"DTLS sessions with this AP that failed to establish on the channel label",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		dtlsDecryptErrors: prometheus.NewDesc(
+		dtlsDecryptErrors: families.counter(
 			"wnc_ap_dtls_decrypt_errors_total",
 			"DTLS decrypt errors on the channel label of this AP's tunnel",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		dtlsAntiReplayError: prometheus.NewDesc(
+		dtlsAntiReplayError: families.counter(
 			"wnc_ap_dtls_anti_replay_errors_total",
 			"DTLS anti-replay errors on the channel label of this AP's tunnel",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
 
-		lastErrorAt: prometheus.NewDesc(
+		lastErrorAt: families.gauge(
 			"wnc_ap_last_error_timestamp_seconds",
 			"Unix timestamp of the last CAPWAP connection error recorded for this AP, "+
 				"in the phase wnc_ap_last_error_phase reports",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastJoinSuccessAt: prometheus.NewDesc(
+		lastJoinSuccessAt: families.gauge(
 			"wnc_ap_last_join_success_timestamp_seconds",
 			"Unix timestamp of this AP's last successful CAPWAP join attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastJoinFailureAt: prometheus.NewDesc(
+		lastJoinFailureAt: families.gauge(
 			"wnc_ap_last_join_failure_timestamp_seconds",
 			"Unix timestamp of this AP's last failed CAPWAP join attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastConfigSuccessAt: prometheus.NewDesc(
+		lastConfigSuccessAt: families.gauge(
 			"wnc_ap_last_config_success_timestamp_seconds",
 			"Unix timestamp of this AP's last successful CAPWAP configuration attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastConfigFailureAt: prometheus.NewDesc(
+		lastConfigFailureAt: families.gauge(
 			"wnc_ap_last_config_failure_timestamp_seconds",
 			"Unix timestamp of this AP's last failed CAPWAP configuration attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastDiscoverySuccessAt: prometheus.NewDesc(
+		lastDiscoverySuccessAt: families.gauge(
 			"wnc_ap_last_discovery_success_timestamp_seconds",
 			"Unix timestamp of this AP's last successful CAPWAP discovery attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastDiscoveryFailureAt: prometheus.NewDesc(
+		lastDiscoveryFailureAt: families.gauge(
 			"wnc_ap_last_discovery_failure_timestamp_seconds",
 			"Unix timestamp of this AP's last failed CAPWAP discovery attempt",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastDTLSSuccessAt: prometheus.NewDesc(
+		lastDTLSSuccessAt: families.gauge(
 			"wnc_ap_last_dtls_success_timestamp_seconds",
 			"Unix timestamp of this AP's last established DTLS session on the channel label",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		lastDTLSFailureAt: prometheus.NewDesc(
+		lastDTLSFailureAt: families.gauge(
 			"wnc_ap_last_dtls_failure_timestamp_seconds",
 			"Unix timestamp of this AP's last failed DTLS session on the channel label",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
 
-		lastDiscoveryFailureReason: prometheus.NewDesc(
+		lastDiscoveryFailureReason: families.gauge(
 			"wnc_ap_last_discovery_failure_reason",
 			"Reason for this AP's last CAPWAP discovery failure, as the value the controller's "+
 				"own enumeration assigns its spelling. 0 is disc-fail-none, which reports that "+
 				"no discovery has failed",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastJoinFailureReason: prometheus.NewDesc(
+		lastJoinFailureReason: families.gauge(
 			"wnc_ap_last_join_failure_reason",
 			"Reason for this AP's last CAPWAP join failure, as the value the controller's own "+
 				"enumeration assigns its spelling. 0 is jf-none, which reports that no join "+
 				"has failed",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastConfigFailureReason: prometheus.NewDesc(
+		lastConfigFailureReason: families.gauge(
 			"wnc_ap_last_config_failure_reason",
 			"Reason for this AP's last CAPWAP configuration failure, as the value the "+
 				"controller's own enumeration assigns its spelling. 0 is cf-none, which reports "+
 				"that no configuration has failed",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastErrorPhase: prometheus.NewDesc(
+		lastErrorPhase: families.gauge(
 			"wnc_ap_last_error_phase",
 			"CAPWAP phase of this AP's last connection error, as the value the controller's "+
 				"own enumeration assigns its spelling (0=ap-con-failure-unknown, "+
@@ -239,31 +233,47 @@ func newAPJoinDescs() *apJoinDescs {
 				"0 reports that the phase is unknown rather than that nothing failed. It "+
 				"freezes with the record, and an AP that is not joined reports the same 6 as "+
 				"one that is",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastDTLSFailureReason: prometheus.NewDesc(
+		lastDTLSFailureReason: families.gauge(
 			"wnc_ap_last_dtls_failure_reason",
 			"Reason for the last DTLS handshake outcome on the channel label, as the value "+
 				"the controller's own enumeration assigns its spelling. 0 is dtls-hs-success, "+
 				"which is also what a channel carrying no session reports",
-			channelLabels, nil,
+			channelLabels, wnc.DataAPJoinStats,
 		),
-		lastRebootReason: prometheus.NewDesc(
+		lastRebootReason: families.gauge(
 			"wnc_ap_last_reboot_reason",
 			"Reason this AP last rebooted as the AP reported it, as the value the controller's "+
 				"own enumeration assigns its spelling. 0 is ap-reboot-reason-none",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
-		lastDisconnectReason: prometheus.NewDesc(
+		lastDisconnectReason: families.gauge(
 			"wnc_ap_last_disconnect_reason",
 			"Reason this AP last left CAPWAP, as the value the controller's own enumeration "+
 				"assigns its spelling. 0 is the enumeration's own unknown member rather than "+
 				"the absence of a disconnect",
-			apLabels, nil,
+			apLabels, wnc.DataAPJoinStats,
 		),
 	}
 }
 
+// newAPJoinedDesc builds the descriptor of wnc_ap_joined, which the join and departed
+// modules both publish, each from the data type it reads. They share one declaration
+// because the registry accepts a name twice from one collector only when its help and
+// labels are identical.
+func newAPJoinedDesc(families *familySet, dataType string) *prometheus.Desc {
+	return families.gauge(
+		"wnc_ap_joined",
+		"Whether the AP holds a CAPWAP session with this controller now "+
+			"(0=not joined, 1=joined). The record outlives the session, so the join, "+
+			"configuration and DTLS series freeze while this reports 0, while the "+
+			"discovery series keep advancing for as long as the AP still reaches the controller. "+
+			"The departed module also reports 0 for an AP that left the inventory and has no record",
+		[]string{labelMAC}, dataType,
+	)
+}
+
 // describe sends every descriptor of the join module.
 func (d *apJoinDescs) describe(ch chan<- *prometheus.Desc) {
 	for _, desc := range []*prometheus.Desc{
@@ -0,0 +1,139 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the mesh backhaul module of the AP collector.
+package collector
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// apMeshDescs holds the descriptors of the mesh module. A nil value means the module is
+// disabled, which is what keeps every series of it out of a default scrape.
+type apMeshDescs struct {
+	info            *prometheus.Desc
+	root            *prometheus.Desc
+	hopCount        *prometheus.Desc
+	backhaulChannel *prometheus.Desc
+	linkSNR         *prometheus.Desc
+	linkRate        *prometheus.Desc
+}
+
+// newAPMeshDescs builds the descriptors of the mesh module.
+//
+// Every series is keyed by the AP radio MAC, the key of wnc_ap_joined, and the link
+// readings add the backhaul radio. The parent is published as a label of an info series
+// rather than on the readings, for the reason the uplink module gives for the neighbor:
+// a mesh AP that changes parent would otherwise start fresh series for its link.
+func newAPMeshDescs(families *familySet) *apMeshDescs {
+	linkLabels := []string{labelMAC, labelRadio}
+
+	return &apMeshDescs{
+		info: families.gauge(
+			"wnc_ap_mesh_info",
+			"Parent a mesh AP backhauls through, always 1. One series per edge of the mesh "+
+				"tree, so a root AP, whose uplink is wired, has none",
+			[]string{labelMAC, labelParentMAC}, wnc.DataAPMeshOperData,
+		),
+		root: families.gauge(
+			"wnc_ap_mesh_root",
+			"Whether the AP is a root AP (1) or a mesh AP backhauling over the air (0). "+
+				"Absent when the controller reports no role",
+			[]string{labelMAC}, wnc.DataAPMeshOperData,
+		),
+		hopCount: families.gauge(
+			"wnc_ap_mesh_hops",
+			"Number of wireless hops between the AP and its root AP. 0 on a root AP, and "+
+				"absent on a mesh AP reporting none",
+			[]string{labelMAC}, wnc.DataAPMeshOperData,
+		),
+		backhaulChannel: families.gauge(
+			"wnc_ap_mesh_backhaul_channel",
+			"Channel the AP's backhaul radio operates on. Absent while the controller "+
+				"reports none",
+			linkLabels, wnc.DataAPMeshOperData,
+		),
+		linkSNR: families.gauge(
+			"wnc_ap_mesh_link_snr_db",
+			"SNR in dB at which a mesh AP hears its parent over the backhaul. Absent while "+
+				"the controller reports none",
+			linkLabels, wnc.DataAPMeshOperData,
+		),
+		linkRate: families.gauge(
+			"wnc_ap_mesh_link_rate_mbps",
+			"Data rate of a mesh AP's backhaul link to its parent in Mbps. Absent while the "+
+				"controller reports none",
+			linkLabels, wnc.DataAPMeshOperData,
+		),
+	}
+}
+
+// describe sends every descriptor of the mesh module.
+func (d *apMeshDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.info
+	ch <- d.root
+	ch <- d.hopCount
+	ch <- d.backhaulChannel
+	ch <- d.linkSNR
+	ch <- d.linkRate
+}
+
+// collect publishes every AP the mesh list carries.
+//
+// A record with no MAC cannot be joined to anything, so it is dropped, and an AP is
+// published once, since Gather rejects a duplicate by failing the entire endpoint. The
+// link readings are withheld at zero: the controller leaves an unreported leaf at zero,
+// and a root AP, which has no parent to hear, reports none of them.
+func (d *apMeshDescs) collect(ch chan<- prometheus.Metric, meshAPs []wnc.MeshAPOperData) {
+	seen := make(map[string]bool, len(meshAPs))
+
+	for i := range meshAPs {
+		meshAP := &meshAPs[i]
+		if meshAP.WtpMAC == "" || seen[meshAP.WtpMAC] {
+			continue
+		}
+		seen[meshAP.WtpMAC] = true
+
+		mac := meshAP.WtpMAC
+		isRoot := meshAP.Role == APMeshRoleRoot
+
+		if meshAP.ParentMAC != "" && !isRoot {
+			ch <- prometheus.MustNewConstMetric(d.info, prometheus.GaugeValue, 1, mac, meshAP.ParentMAC)
+		}
+
+		if meshAP.Role != "" {
+			ch <- prometheus.MustNewConstMetric(d.root, prometheus.GaugeValue, boolToFloat64(isRoot), mac)
+		}
+
+		// Zero is a reading on a root AP and the unreported leaf on any other.
+		if isRoot || meshAP.HopCount > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.hopCount, prometheus.GaugeValue, float64(meshAP.HopCount), mac,
+			)
+		}
+
+		radio := strconv.Itoa(meshAP.BackhaulSlotID)
+
+		if meshAP.BackhaulChannel > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.backhaulChannel, prometheus.GaugeValue, float64(meshAP.BackhaulChannel), mac, radio,
+			)
+		}
+
+		if isRoot {
+			continue
+		}
+
+		if meshAP.LinkSNR > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.linkSNR, prometheus.GaugeValue, float64(meshAP.LinkSNR), mac, radio,
+			)
+		}
+
+		if rate, err := meshAP.BackhaulDataRate.Float64(); err == nil && rate > 0 {
+			ch <- prometheus.MustNewConstMetric(d.linkRate, prometheus.GaugeValue, rate, mac, radio)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the RRM neighbor module of the AP collector.
+package collector
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// apNeighborDescs holds the descriptors of the neighbors module. A nil value means the
+// module is disabled, which is what keeps every series of it out of a default scrape.
+type apNeighborDescs struct {
+	rssi *prometheus.Desc
+	name *prometheus.Desc
+
+	// topN bounds the neighbors published per radio. A dense deployment hears dozens of
+	// radios from each one, and the weak tail carries no contention worth a series.
+	topN int
+}
+
+// newAPNeighborDescs builds the descriptors of the neighbors module.
+//
+// A neighbor is keyed by its radio MAC, the key of wnc_ap_joined, and its name is
+// published as its own series rather than as a label, for the reason the join module
+// gives: renaming an AP would otherwise start a fresh series on every radio hearing it.
+func newAPNeighborDescs(families *familySet, topN int) *apNeighborDescs {
+	return &apNeighborDescs{
+		rssi: families.gauge(
+			"wnc_ap_neighbor_rssi_dbm",
+			"RSSI in dBm at which this radio hears a neighbor AP radio, as RRM reports it. "+
+				"Only the strongest neighbors of each radio are published, up to "+
+				"--collector.ap.neighbors-top-n, so a neighbor can leave and rejoin the set "+
+				"as readings move",
+			[]string{labelMAC, labelRadio, labelNeighborMAC, labelChannel}, wnc.DataRRMAPAutoRFDot11Data,
+		),
+		name: families.gauge(
+			"wnc_ap_neighbor_info",
+			"AP name of a neighbor radio published by wnc_ap_neighbor_rssi_dbm, always 1. "+
+				"Absent for a neighbor the AP name map does not carry, such as an AP joined "+
+				"to another controller",
+			[]string{labelNeighborMAC, labelName}, wnc.DataRRMAPAutoRFDot11Data, wnc.DataAPNameMACMap,
+		),
+		topN: topN,
+	}
+}
+
+// describe sends every descriptor of the neighbors module.
+func (d *apNeighborDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.rssi
+	ch <- d.name
+}
+
+// neighborReads carries the two lists the neighbors module reads, each with whether its
+// fetch succeeded, because a failed list and an empty one withhold different series.
+type neighborReads struct {
+	neighbors   []wnc.RRMNeighborData
+	neighborsOK bool
+	names       []ap.ApNameMACMap
+	namesOK     bool
+}
+
+// collect publishes the strongest neighbors of every radio the neighbor list carries,
+// and the name of every neighbor it published. The names need the neighbor list to
+// know which radios were published, so a failed neighbor list withholds both families.
+func (d *apNeighborDescs) collect(ch chan<- prometheus.Metric, reads neighborReads) {
+	if !reads.neighborsOK {
+		return
+	}
+
+	published := d.collectRSSI(ch, reads.neighbors)
+
+	if reads.namesOK {
+		d.collectNames(ch, reads.names, published)
+	}
+}
+
+// collectRSSI publishes up to topN neighbors per radio and returns the neighbor MACs it
+// published.
+//
+// A neighbor is ranked by RSSI and then by its MAC and channel, so two neighbors heard
+// at the same level keep their order across scrapes instead of trading the last place.
+// A neighbor heard on two channels is two label sets and two candidates, and the list
+// is keyed by the neighbor radio rather than by the label set, so the same label set is
+// emitted once, since Gather rejects a duplicate by failing the entire endpoint.
+func (d *apNeighborDescs) collectRSSI(
+	ch chan<- prometheus.Metric, records []wnc.RRMNeighborData,
+) map[string]bool {
+	published := make(map[string]bool)
+	seen := make(map[[4]string]bool)
+	counts := make(map[[2]string]int)
+
+	for i := range records {
+		record := &records[i]
+		if record.WtpMAC == "" {
+			continue
+		}
+
+		radio := strconv.Itoa(record.RadioSlotID)
+		radioKey := [2]string{record.WtpMAC, radio}
+
+		for _, neighbor := range rankNeighbors(record.NeighborRadioInfo.NeighborRadioList) {
+			if counts[radioKey] >= d.topN {
+				break
+			}
+
+			labels := [4]string{
+				record.WtpMAC, radio, neighbor.NeighborRadioMAC, strconv.Itoa(neighbor.Channel),
+			}
+			if seen[labels] {
+				continue
+			}
+
+			seen[labels] = true
+			counts[radioKey]++
+			published[neighbor.NeighborRadioMAC] = true
+			ch <- prometheus.MustNewConstMetric(
+				d.rssi, prometheus.GaugeValue, float64(neighbor.RSSI),
+				labels[0], labels[1], labels[2], labels[3],
+			)
+		}
+	}
+
+	return published
+}
+
+// rankNeighbors returns the neighbors of one radio that carry a reading, strongest
+// first. A neighbor with no MAC, no channel or an RSSI of zero or above is dropped: the
+// controller leaves an unreported leaf at zero, and no radio is heard at 0 dBm.
+func rankNeighbors(entries []wnc.RRMNeighborRadioEntry) []wnc.RRMNeighborRadio {
+	ranked := make([]wnc.RRMNeighborRadio, 0, len(entries))
+	for i := range entries {
+		neighbor := entries[i].NeighborRadioInfo
+		if neighbor.NeighborRadioMAC == "" || neighbor.Channel <= 0 || neighbor.RSSI >= 0 {
+			continue
+		}
+		ranked = append(ranked, neighbor)
+	}
+
+	slices.SortFunc(ranked, func(a, b wnc.RRMNeighborRadio) int {
+		return cmp.Or(
+			cmp.Compare(b.RSSI, a.RSSI),
+			cmp.Compare(a.NeighborRadioMAC, b.NeighborRadioMAC),
+			cmp.Compare(a.Channel, b.Channel),
+		)
+	})
+
+	return ranked
+}
+
+// collectNames publishes the name of every published neighbor the AP name map carries.
+// The map is keyed by name, so a radio MAC listed under two names is published once,
+// under the first.
+func (d *apNeighborDescs) collectNames(
+	ch chan<- prometheus.Metric, names []ap.ApNameMACMap, published map[string]bool,
+) {
+	done := make(map[string]bool, len(published))
+
+	for i := range names {
+		entry := &names[i]
+		if entry.WtpName == "" || !published[entry.WtpMAC] || done[entry.WtpMAC] {
+			continue
+		}
+		done[entry.WtpMAC] = true
+
+		ch <- prometheus.MustNewConstMetric(d.name, prometheus.GaugeValue, 1, entry.WtpMAC, entry.WtpName)
+	}
+}
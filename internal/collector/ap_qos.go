@@ -0,0 +1,84 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the WMM QoS module of the AP collector.
+package collector
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// apQoSDescs holds the descriptors of the qos module. A nil value means the module is
+// disabled, which is what keeps every series of it out of a default scrape.
+type apQoSDescs struct {
+	txFrames   *prometheus.Desc
+	queueDrops *prometheus.Desc
+}
+
+// newAPQoSDescs builds the descriptors of the qos module.
+//
+// Every series is keyed by the AP radio MAC and the radio slot, the key of every
+// per-radio wnc_ap_* series, and by the WMM access category as the controller spells
+// it, so a radio publishes one series per queue.
+func newAPQoSDescs(families *familySet) *apQoSDescs {
+	labels := []string{labelMAC, labelRadio, labelAccessCategory}
+
+	return &apQoSDescs{
+		txFrames: families.counter(
+			"wnc_ap_qos_transmitted_frames_total",
+			"Frames this radio transmitted from the WMM queue of this access category",
+			labels, wnc.DataAPRadioWMMStats,
+		),
+		queueDrops: families.counter(
+			"wnc_ap_qos_queue_drops_total",
+			"Frames this radio dropped from the WMM queue of this access category before "+
+				"transmitting them, because the queue was full or the frame aged out",
+			labels, wnc.DataAPRadioWMMStats,
+		),
+	}
+}
+
+// describe sends every descriptor of the qos module.
+func (d *apQoSDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.txFrames
+	ch <- d.queueDrops
+}
+
+// collect publishes every queue the WMM statistics list carries.
+//
+// A record with no AP or no access category cannot be told apart from another, so it
+// is dropped, and the same label set is emitted once, since Gather rejects a duplicate
+// by failing the entire endpoint. A counter the record omits or garbles is withheld
+// rather than published as 0, which would read as a counter that had been reset.
+func (d *apQoSDescs) collect(ch chan<- prometheus.Metric, stats []wnc.RadioWMMStats) {
+	seen := make(map[[3]string]bool, len(stats))
+
+	for i := range stats {
+		entry := &stats[i]
+		if entry.WtpMAC == "" || entry.AccessCategory == "" {
+			continue
+		}
+
+		labels := [3]string{entry.WtpMAC, strconv.Itoa(entry.RadioSlotID), entry.AccessCategory}
+		if seen[labels] {
+			continue
+		}
+		seen[labels] = true
+
+		for _, counter := range []struct {
+			desc  *prometheus.Desc
+			value wnc.Number
+		}{
+			{d.txFrames, entry.TxFrames},
+			{d.queueDrops, entry.QueueDrops},
+		} {
+			value, err := counter.value.Float64()
+			if err != nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(counter.desc, prometheus.CounterValue, value, labels[:]...)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the reboot and rejoin module of the AP collector.
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// restartJitter is the least an AP's boot or join time must move to count as a new
+// one. The controller reports both as instants, and a move smaller than this is read
+// as the same instant spelled again rather than an AP that rebooted and rejoined in a
+// few seconds, which no AP does.
+const restartJitter = 5 * time.Second
+
+// restartRetention is how long the restarts module remembers an AP that left the
+// inventory, so the rejoin that brings it back is counted. A day covers a maintenance
+// window and an overnight outage, and bounds the memory a decommissioned AP holds.
+const restartRetention = 24 * time.Hour
+
+// restartReasonUnknown stands in for a reason the join statistics did not spell, either
+// because the record left it empty or because the list could not be read.
+const restartReasonUnknown = "unknown"
+
+// apRestartTimes is what the restarts module remembers of an AP between two snapshots.
+// A zero instant means the controller has not reported a usable one yet.
+type apRestartTimes struct {
+	bootedAt time.Time
+	joinedAt time.Time
+	// listedAt is the refresh time of the last snapshot that listed the AP.
+	listedAt time.Time
+}
+
+// apRestartDescs holds the state of the restarts module. A nil value means the module
+// is disabled, which is what keeps every series of it out of a default scrape.
+//
+// Like the client onboarding and sessions modules it keeps state across snapshots: the
+// controller reports when an AP last booted and joined, and a reboot or a rejoin is only
+// visible as that instant moving between two snapshots.
+type apRestartDescs struct {
+	reboots *prometheus.CounterVec
+	rejoins *prometheus.CounterVec
+
+	mu    sync.Mutex
+	clock snapshotClock
+	// seen maps each AP radio MAC to the instants the last snapshot listing it found.
+	// An AP that leaves the inventory is kept for restartRetention, so the rejoin that
+	// brings it back is counted.
+	seen map[string]apRestartTimes
+}
+
+// newAPRestartDescs builds the descriptors of the restarts module.
+//
+// Both counters are keyed by the AP radio MAC, the key of every per-AP wnc_ap_* series,
+// and by the reason the join statistics spelled when the event was counted, under the
+// name of the join series that numbers it, so an AP carries one series per reason it
+// has been seen to restart for.
+func newAPRestartDescs(families *familySet) *apRestartDescs {
+	return &apRestartDescs{
+		reboots: families.counterVec(
+			"wnc_ap_reboots_observed_total",
+			"Times this AP's boot time moved forward between two refreshes, by the "+
+				"reboot reason the join statistics spelled at the refresh that saw it. "+
+				"Several reboots between two refreshes count once",
+			[]string{labelMAC, labelLastRebootReason}, wnc.DataAPCAPWAPData, wnc.DataAPJoinStats,
+		),
+		rejoins: families.counterVec(
+			"wnc_ap_rejoins_observed_total",
+			"Times this AP's CAPWAP join time moved forward between two refreshes, "+
+				"by the disconnect reason the join statistics spelled at the refresh that "+
+				"saw it. A reboot rejoins too, so this includes the reboots",
+			[]string{labelMAC, labelLastDisconnectReason}, wnc.DataAPCAPWAPData, wnc.DataAPJoinStats,
+		),
+		seen: make(map[string]apRestartTimes),
+	}
+}
+
+// describe sends every descriptor of the restarts module.
+func (d *apRestartDescs) describe(ch chan<- *prometheus.Desc) {
+	d.reboots.Describe(ch)
+	d.rejoins.Describe(ch)
+}
+
+// collect compares the boot and join time of every AP in the inventory of a snapshot
+// not compared yet with the ones the last snapshot listing it found, and publishes both
+// counters.
+//
+// An AP seen for the first time, which is every AP on the first snapshot after a
+// restart, only records its instants, since there is nothing to compare them with. An
+// instant the controller omits or spells at the epoch is not recorded, so it neither
+// counts nor erases the one remembered. An AP the inventory has not listed for
+// restartRetention is forgotten and its series deleted, so a decommissioned AP does not stay
+// in memory and on the endpoint for the life of the process.
+func (d *apRestartDescs) collect(
+	ch chan<- prometheus.Metric,
+	refreshedAt time.Time,
+	capwapData []ap.CAPWAPData,
+	joinStats []ap.ApJoinStats,
+) {
+	d.mu.Lock()
+	if d.clock.advance(refreshedAt) {
+		d.compare(refreshedAt, capwapData, joinStats)
+		d.prune(refreshedAt)
+	}
+	d.mu.Unlock()
+
+	d.reboots.Collect(ch)
+	d.rejoins.Collect(ch)
+}
+
+// compare counts the instants that moved since the last snapshot listing each AP.
+func (d *apRestartDescs) compare(refreshedAt time.Time, capwapData []ap.CAPWAPData, joinStats []ap.ApJoinStats) {
+	reasons := make(map[string]*ap.ApJoinStats, len(joinStats))
+	for i := range joinStats {
+		reasons[joinStats[i].WtpMAC] = &joinStats[i]
+	}
+
+	for i := range capwapData {
+		data := &capwapData[i]
+		if data.WtpMAC == "" {
+			continue
+		}
+
+		previous, known := d.seen[data.WtpMAC]
+		current := previous
+		current.listedAt = refreshedAt
+		record := reasons[data.WtpMAC]
+
+		if bootedAt, ok := parseAPTimestamp(data.ApTimeInfo.BootTime); ok {
+			if known && movedForward(previous.bootedAt, bootedAt) {
+				reason := restartReasonUnknown
+				if record != nil {
+					reason = restartReasonOrUnknown(record.RebootReason)
+				}
+				d.reboots.WithLabelValues(data.WtpMAC, reason).Inc()
+			}
+			current.bootedAt = bootedAt
+		}
+
+		if joinedAt, ok := parseAPTimestamp(data.ApTimeInfo.JoinTime); ok {
+			if known && movedForward(previous.joinedAt, joinedAt) {
+				reason := restartReasonUnknown
+				if record != nil {
+					reason = restartReasonOrUnknown(record.DisconnectReason)
+				}
+				d.rejoins.WithLabelValues(data.WtpMAC, reason).Inc()
+			}
+			current.joinedAt = joinedAt
+		}
+
+		d.seen[data.WtpMAC] = current
+	}
+}
+
+// prune forgets every AP the inventory has not listed for restartRetention and deletes
+// its series. The refresh time is the clock, so a snapshot served past its refresh
+// does not age an AP it still lists.
+func (d *apRestartDescs) prune(refreshedAt time.Time) {
+	for mac, times := range d.seen {
+		if refreshedAt.Sub(times.listedAt) <= restartRetention {
+			continue
+		}
+		delete(d.seen, mac)
+		d.reboots.DeletePartialMatch(prometheus.Labels{labelMAC: mac})
+		d.rejoins.DeletePartialMatch(prometheus.Labels{labelMAC: mac})
+	}
+}
+
+// movedForward reports whether an instant moved later than the remembered one by more
+// than restartJitter. A remembered zero has nothing to move from.
+func movedForward(previous, current time.Time) bool {
+	return !previous.IsZero() && current.Sub(previous) > restartJitter
+}
+
+// restartReasonOrUnknown returns the reason spelling, or restartReasonUnknown when the
+// record left it empty. An empty label value would read as an absent label, which no
+// query can match by equality.
+func restartReasonOrUnknown(reason string) string {
+	if reason == "" {
+		return restartReasonUnknown
+	}
+
+	return reason
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/rrm"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
 )
 
 // apRRMDescs holds the descriptors of the band-keyed RRM run instants. A nil value means
@@ -19,23 +20,23 @@ type apRRMDescs struct {
 //
 // The band is the whole identifier. The controller keeps one record per band rather than
 // one per radio, so neither the AP nor the radio an assignment lands on keys the series.
-func newAPRRMDescs() *apRRMDescs {
+func newAPRRMDescs(families *familySet) *apRRMDescs {
 	bandLabels := []string{labelBand}
 
 	return &apRRMDescs{
-		lastRFGroupingRunAt: prometheus.NewDesc(
+		lastRFGroupingRunAt: families.gauge(
 			"wnc_rrm_last_rf_grouping_run_timestamp_seconds",
 			"Unix timestamp of the last RF grouping run the controller reports for this band. "+
 				"It advances when the algorithm runs rather than when a channel or a transmit "+
 				"power level changes, so it can advance with every reading beside it unchanged",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMMainData,
 		),
-		lastDCARunAt: prometheus.NewDesc(
+		lastDCARunAt: families.gauge(
 			"wnc_rrm_last_dca_run_timestamp_seconds",
 			"Unix timestamp of the last DCA run the controller reports for this band. It "+
 				"advances when the algorithm runs rather than when a radio's channel changes, "+
 				"so wnc_ap_channel_changes_total can stay flat across a run",
-			bandLabels, nil,
+			bandLabels, wnc.DataRRMMainData,
 		),
 	}
 }
@@ -1,7 +1,12 @@
 package collector
 
 import (
+	"encoding/json"
+	"errors"
+	"io/fs"
 	"maps"
+	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -208,6 +213,41 @@ func TestAPCollector_isAnyMetricFlagEnabled(t *testing.T) {
 			APMetrics{Info: true},
 			true,
 		},
+		{
+			"Uplink enabled",
+			APMetrics{Uplink: true},
+			true,
+		},
+		{
+			"Mesh enabled",
+			APMetrics{Mesh: true},
+			true,
+		},
+		{
+			"QoS enabled",
+			APMetrics{QoS: true},
+			true,
+		},
+		{
+			"Restarts enabled",
+			APMetrics{Restarts: true},
+			true,
+		},
+		{
+			"Departed enabled",
+			APMetrics{Departed: true},
+			true,
+		},
+		{
+			"Neighbors enabled",
+			APMetrics{Neighbors: true},
+			true,
+		},
+		{
+			"Interferers enabled",
+			APMetrics{Interferers: true},
+			true,
+		},
 		{
 			"Multiple enabled",
 			APMetrics{General: true, Radio: true},
@@ -269,12 +309,49 @@ func TestAPCollector_Describe(t *testing.T) {
 			// joined, join_info, 14 counters, 9 timestamps, 7 reasons
 			32,
 		},
+		{
+			"Uplink module only",
+			APMetrics{Uplink: true},
+			// uplink_info, speed, full_duplex, power_full
+			4,
+		},
+		{
+			"Mesh module only",
+			APMetrics{Mesh: true},
+			// mesh_info, root, hops, backhaul_channel, link_snr, link_rate
+			6,
+		},
+		{
+			"QoS module only",
+			APMetrics{QoS: true},
+			2, // qos_transmitted_frames, qos_queue_drops
+		},
+		{
+			"Restarts module only",
+			APMetrics{Restarts: true},
+			2, // reboots_observed, rejoins_observed
+		},
+		{
+			"Departed module only",
+			APMetrics{Departed: true},
+			2, // joined, last_seen
+		},
+		{
+			"Neighbors module only",
+			APMetrics{Neighbors: true, NeighborsTopN: 5},
+			2, // neighbor_rssi, neighbor_info
+		},
 		{
 			"Spectrum module only",
 			APMetrics{Spectrum: true},
 			// The four per-radio air quality series and the four band-keyed ones
 			8,
 		},
+		{
+			"Interferers module only",
+			APMetrics{Interferers: true},
+			3, // interferer_devices, severity_max, duty_cycle_ratio_max
+		},
 		{
 			"Info module only",
 			APMetrics{Info: true},
@@ -283,15 +360,23 @@ func TestAPCollector_Describe(t *testing.T) {
 		{
 			"All modules enabled",
 			APMetrics{
-				General:  true,
-				Radio:    true,
-				Traffic:  true,
-				Errors:   true,
-				Join:     true,
-				Spectrum: true,
-				Info:     true,
+				General:     true,
+				Radio:       true,
+				Traffic:     true,
+				Errors:      true,
+				Join:        true,
+				Uplink:      true,
+				Mesh:        true,
+				QoS:         true,
+				Restarts:    true,
+				Departed:    true,
+				Neighbors:   true,
+				Spectrum:    true,
+				Interferers: true,
+				Info:        true,
 			},
-			87, // 8+15+10+13+32+8+1
+			// The join and departed modules send the one wnc_ap_joined descriptor each
+			108, // 8+15+10+13+32+4+6+2+2+2+2+8+3+1
 		},
 	}
 
@@ -300,7 +385,7 @@ func TestAPCollector_Describe(t *testing.T) {
 			t.Parallel()
 			collector := NewAPCollector(nil, nil, nil, tt.metrics)
 
-			ch := make(chan *prometheus.Desc, 100)
+			ch := make(chan *prometheus.Desc, 120)
 			collector.Describe(ch)
 			close(ch)
 
@@ -3196,3 +3281,710 @@ func TestAirQualityOnCurrentChannel_RecordWithoutTheContainer(t *testing.T) {
 			"matching record carries no per-radio container", got)
 	}
 }
+
+// gatherUplink collects the uplink module alone over the given snapshot and returns the
+// label sets of wnc_ap_uplink_info, each joined by "|", and the link settings by MAC.
+func gatherUplink(t *testing.T, data *wnc.WNCDataCache) (info []string, byMAC map[string]map[string]float64) {
+	t.Helper()
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Uplink: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion
+	// that the module emits each neighbor once.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	byMAC = make(map[string]map[string]float64, len(families))
+	for _, family := range families {
+		values := make(map[string]float64, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			if family.GetName() == "wnc_ap_uplink_info" {
+				info = append(info, strings.Join([]string{
+					labels[labelMAC], labels[labelNeighbor], labels[labelPort], labels[labelPlatform],
+				}, "|"))
+				continue
+			}
+			values[labels[labelMAC]] = metric.GetGauge().GetValue()
+		}
+		byMAC[family.GetName()] = values
+	}
+	slices.Sort(info)
+	return info, byMAC
+}
+
+// TestAPUplinkModule_PrefersCDP pins which list names an AP's neighbor. An AP CDP names
+// must not also carry its LLDP entry, which would give one switch port two label sets,
+// and an AP only LLDP names must still carry one. The fixture's AP has two identical CDP
+// entries, so the gather succeeding is what pins the dedupe.
+func TestAPUplinkModule_PrefersCDP(t *testing.T) {
+	t.Parallel()
+
+	cdp := fixtureAPMAC + "|access-sw01|GigabitEthernet1/0/1|cisco C9300-48P"
+	lldp := fixtureLLDPOnlyAPMAC + "|00:11:22:33:44:55|ge-0/0/7|"
+
+	tests := []struct {
+		name   string
+		failed string
+		want   []string
+	}{
+		{"both lists read", "", []string{cdp, lldp}},
+		{"LLDP failed keeps the CDP neighbors", typeAPLLDPNeigh, []string{cdp}},
+		// Without the CDP list there is no telling which AP LLDP may speak for.
+		{"CDP failed withholds every neighbor", typeAPCDPCacheData, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := fullFixtureSnapshot()
+			if tt.failed != "" {
+				data.FetchErrors[tt.failed] = errors.New("fetch failed")
+			}
+
+			info, _ := gatherUplink(t, data)
+			if !slices.Equal(info, tt.want) {
+				t.Errorf("wnc_ap_uplink_info = %v, want %v", info, tt.want)
+			}
+		})
+	}
+}
+
+// TestAPUplinkModule_WithholdsWhatItCannotRead covers the leaves whose absence must not
+// read as a value: a speed that is not a number would otherwise publish 0 Mbps, a duplex
+// spelling naming neither mode would publish half duplex, and an empty power status
+// would publish a PoE shortfall on an AP drawing full power.
+func TestAPUplinkModule_WithholdsWhatItCannotRead(t *testing.T) {
+	t.Parallel()
+
+	// The list is decoded as the controller writes it, so the speed spelled as a word
+	// reaches the collector the way a refresh delivers it.
+	data := fullFixtureSnapshot()
+	data.CDPNeighbors = nil
+	cdp := `[
+		{"mac-addr": "` + fixtureAPMAC + `", "cdp-cache-device-id": "access-sw01",
+		 "cdp-cache-duplex": "full-duplex", "cdp-cache-interface-speed": "auto"},
+		{"mac-addr": "` + fixtureLLDPOnlyAPMAC + `", "cdp-cache-device-id": "access-sw02",
+		 "cdp-cache-interface-speed": 100}
+	]`
+	if err := json.Unmarshal([]byte(cdp), &data.CDPNeighbors); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	data.APPowerInfo = []wnc.APPowerInfo{
+		{WtpMAC: fixtureAPMAC, Status: APPowerStatusFull},
+		{WtpMAC: fixtureLLDPOnlyAPMAC, Status: ""},
+	}
+
+	_, values := gatherUplink(t, data)
+
+	if _, ok := values["wnc_ap_uplink_speed_mbps"][fixtureAPMAC]; ok {
+		t.Error("wnc_ap_uplink_speed_mbps is published for a speed that is not a number")
+	}
+	if got := values["wnc_ap_uplink_speed_mbps"][fixtureLLDPOnlyAPMAC]; got != 100 {
+		t.Errorf("wnc_ap_uplink_speed_mbps = %v, want 100", got)
+	}
+	if got, ok := values["wnc_ap_uplink_full_duplex"][fixtureAPMAC]; !ok || got != 1 {
+		t.Errorf("wnc_ap_uplink_full_duplex = %v (present=%t), want 1 for a prefixed spelling", got, ok)
+	}
+	if _, ok := values["wnc_ap_uplink_full_duplex"][fixtureLLDPOnlyAPMAC]; ok {
+		t.Error("wnc_ap_uplink_full_duplex is published for an empty duplex leaf")
+	}
+	if got := values["wnc_ap_uplink_power_full"][fixtureAPMAC]; got != 1 {
+		t.Errorf("wnc_ap_uplink_power_full = %v, want 1 for %q", got, APPowerStatusFull)
+	}
+	if _, ok := values["wnc_ap_uplink_power_full"][fixtureLLDPOnlyAPMAC]; ok {
+		t.Error("wnc_ap_uplink_power_full is published for an empty status leaf")
+	}
+}
+
+// gatherNeighbors collects the neighbors module alone over the given snapshot, keeping
+// topN neighbors per radio, and returns its RSSI samples as "mac|radio|neighbor_mac|channel"
+// mapped to their value, and its name samples as "neighbor_mac|name".
+func gatherNeighbors(
+	t *testing.T, data *wnc.WNCDataCache, topN int,
+) (rssi map[string]float64, names []string) {
+	t.Helper()
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Neighbors: true, NeighborsTopN: topN},
+	))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	rssi = make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			if family.GetName() == "wnc_ap_neighbor_info" {
+				names = append(names, labels[labelNeighborMAC]+"|"+labels[labelName])
+				continue
+			}
+			key := strings.Join([]string{
+				labels[labelMAC], labels[labelRadio], labels[labelNeighborMAC], labels[labelChannel],
+			}, "|")
+			rssi[key] = metric.GetGauge().GetValue()
+		}
+	}
+	slices.Sort(names)
+	return rssi, names
+}
+
+// TestAPNeighborsModule_KeepsStrongestTopN pins the selection of each radio's neighbors:
+// the strongest first, a tie broken by MAC so the set holds still across scrapes, an
+// unreported reading dropped rather than ranked as the strongest, and a repeated entry
+// emitted once, which the gather succeeding asserts.
+func TestAPNeighborsModule_KeepsStrongestTopN(t *testing.T) {
+	t.Parallel()
+
+	entry := func(mac string, rssi, channel int) wnc.RRMNeighborRadioEntry {
+		return wnc.RRMNeighborRadioEntry{NeighborRadioInfo: wnc.RRMNeighborRadio{
+			NeighborRadioMAC: mac, RSSI: rssi, Channel: channel,
+		}}
+	}
+
+	data := fullFixtureSnapshot()
+	data.RRMNeighbors = []wnc.RRMNeighborData{{
+		WtpMAC: fixtureAPMAC, RadioSlotID: 1,
+		NeighborRadioInfo: wnc.RRMNeighborRadioInfo{NeighborRadioList: []wnc.RRMNeighborRadioEntry{
+			entry("aa:bb:cc:dd:ef:03", -70, 36),
+			entry("aa:bb:cc:dd:ef:02", -70, 36),
+			entry("aa:bb:cc:dd:ef:01", -50, 36),
+			entry("aa:bb:cc:dd:ef:01", -50, 36),
+			entry("aa:bb:cc:dd:ef:04", 0, 36),
+			entry("aa:bb:cc:dd:ef:05", -40, 0),
+		}},
+	}}
+
+	tests := []struct {
+		name string
+		topN int
+		want map[string]float64
+	}{
+		{"cap below the list keeps the strongest and breaks the tie by MAC", 2, map[string]float64{
+			fixtureAPMAC + "|1|aa:bb:cc:dd:ef:01|36": -50,
+			fixtureAPMAC + "|1|aa:bb:cc:dd:ef:02|36": -70,
+		}},
+		{"cap above the list keeps every reading once", 10, map[string]float64{
+			fixtureAPMAC + "|1|aa:bb:cc:dd:ef:01|36": -50,
+			fixtureAPMAC + "|1|aa:bb:cc:dd:ef:02|36": -70,
+			fixtureAPMAC + "|1|aa:bb:cc:dd:ef:03|36": -70,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rssi, _ := gatherNeighbors(t, data, tt.topN)
+			if !maps.Equal(rssi, tt.want) {
+				t.Errorf("wnc_ap_neighbor_rssi_dbm = %v, want %v", rssi, tt.want)
+			}
+		})
+	}
+}
+
+// TestAPNeighborsModule_NamesOnlyPublishedNeighbors covers the name series: it names a
+// neighbor the RSSI family published and never an AP no radio was heard from, and a
+// failed name map withholds the names alone.
+func TestAPNeighborsModule_NamesOnlyPublishedNeighbors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		failed   string
+		unheard  bool
+		wantRSSI int
+		want     []string
+	}{
+		{"name map read", "", false, 3, []string{fixtureAPMAC + "|" + fixtureAPName}},
+		{"name map failed keeps the readings", typeAPNameMACMap, false, 3, nil},
+		// The fixture AP stays in the name map but no radio hears it.
+		{"named AP unheard", "", true, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := fullFixtureSnapshot()
+			if tt.failed != "" {
+				data.FetchErrors[tt.failed] = errors.New("fetch failed")
+			}
+			if tt.unheard {
+				data.RRMNeighbors = data.RRMNeighbors[:1]
+			}
+
+			rssi, names := gatherNeighbors(t, data, fixtureNeighborTopN)
+			if len(rssi) != tt.wantRSSI {
+				t.Errorf("wnc_ap_neighbor_rssi_dbm has %d samples, want %d", len(rssi), tt.wantRSSI)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("wnc_ap_neighbor_info = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+// gatherInterferers collects the interferers module alone over the given snapshot and
+// returns every sample as "family|mac|radio|channel|type" mapped to its value.
+func gatherInterferers(t *testing.T, data *wnc.WNCDataCache) map[string]float64 {
+	t.Helper()
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Interferers: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion that
+	// a repeated device record is emitted once.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			key := strings.Join([]string{
+				family.GetName(), labels[labelMAC], labels[labelRadio], labels[labelChannel], labels[labelType],
+			}, "|")
+			values[key] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+// TestAPInterferersModule_CountsDevicesByType pins the per-type series: the devices of
+// one type a radio hears counted once each whatever identifier CleanAir gives them, the
+// highest severity and duty cycle among them, a device heard by two APs kept apart, and
+// every reading the controller left unreported or cannot be keyed withheld.
+func TestAPInterferersModule_CountsDevicesByType(t *testing.T) {
+	t.Parallel()
+
+	const other = "aa:bb:cc:dd:ef:30"
+
+	data := fullFixtureSnapshot()
+	data.SpectrumDevices = []wnc.SpectrumDevice{
+		{
+			DeviceID: "1", WtpMAC: fixtureAPMAC, RadioSlotID: 0, Channel: 6,
+			DeviceType: "si-dev-type-microwave-oven", Severity: 30, DutyCycle: 50,
+		},
+		// The same record twice, as two pages of the table can carry it.
+		{
+			DeviceID: "1", WtpMAC: fixtureAPMAC, RadioSlotID: 0, Channel: 6,
+			DeviceType: "si-dev-type-microwave-oven", Severity: 30, DutyCycle: 50,
+		},
+		// A second oven on the same radio, milder but transmitting more.
+		{
+			DeviceID: "9", WtpMAC: fixtureAPMAC, RadioSlotID: 0, Channel: 6,
+			DeviceType: "si-dev-type-microwave-oven", Severity: 10, DutyCycle: 70,
+		},
+		// The same device heard by another AP.
+		{
+			DeviceID: "1", WtpMAC: other, RadioSlotID: 0, Channel: 6,
+			DeviceType: "si-dev-type-microwave-oven", Severity: 12, DutyCycle: 50,
+		},
+		// An unclassified device whose severity and duty cycle are unreported.
+		{DeviceID: "2", WtpMAC: fixtureAPMAC, RadioSlotID: 1, Channel: 36, DeviceType: "si-dev-type-unclassified"},
+		// A type no release names keeps its spelling, and a missing one is counted.
+		{DeviceID: "3", WtpMAC: fixtureAPMAC, RadioSlotID: 1, Channel: 36, DeviceType: "si-dev-type-new", Severity: 5},
+		{DeviceID: "6", WtpMAC: fixtureAPMAC, RadioSlotID: 1, Channel: 36},
+		// Three records that cannot be keyed.
+		{DeviceID: "", WtpMAC: fixtureAPMAC, Channel: 6, DeviceType: "si-dev-type-jammer", Severity: 90},
+		{DeviceID: "4", WtpMAC: "", Channel: 6, DeviceType: "si-dev-type-jammer", Severity: 90},
+		{DeviceID: "5", WtpMAC: fixtureAPMAC, Channel: 0, DeviceType: "si-dev-type-jammer", Severity: 90},
+	}
+
+	want := map[string]float64{
+		"wnc_ap_interferer_devices|" + fixtureAPMAC + "|0|6|microwave-oven":              2,
+		"wnc_ap_interferer_severity_max|" + fixtureAPMAC + "|0|6|microwave-oven":         30,
+		"wnc_ap_interferer_duty_cycle_ratio_max|" + fixtureAPMAC + "|0|6|microwave-oven": 0.7,
+		"wnc_ap_interferer_devices|" + other + "|0|6|microwave-oven":                     1,
+		"wnc_ap_interferer_severity_max|" + other + "|0|6|microwave-oven":                12,
+		"wnc_ap_interferer_duty_cycle_ratio_max|" + other + "|0|6|microwave-oven":        0.5,
+		"wnc_ap_interferer_devices|" + fixtureAPMAC + "|1|36|unclassified":               1,
+		"wnc_ap_interferer_devices|" + fixtureAPMAC + "|1|36|new":                        1,
+		"wnc_ap_interferer_severity_max|" + fixtureAPMAC + "|1|36|new":                   5,
+		"wnc_ap_interferer_devices|" + fixtureAPMAC + "|1|36|unknown":                    1,
+	}
+
+	if got := gatherInterferers(t, data); !maps.Equal(got, want) {
+		t.Errorf("interferers module published %v, want %v", got, want)
+	}
+}
+
+// TestAPMeshModule_TreeAndWithheldReadings pins the shape of the mesh module. Each mesh
+// AP is one edge of the tree to its parent, a root AP is no edge and reports hop count
+// 0, a mesh AP reporting no hop count or link reading has that series withheld rather
+// than published as 0, and a repeated record is published once.
+func TestAPMeshModule_TreeAndWithheldReadings(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rootMAC  = "aa:bb:cc:dd:f0:00"
+		childMAC = "aa:bb:cc:dd:f0:10"
+		quietMAC = "aa:bb:cc:dd:f0:20"
+	)
+
+	data := fullFixtureSnapshot()
+	data.MeshAPs = []wnc.MeshAPOperData{
+		{WtpMAC: rootMAC, Role: APMeshRoleRoot, BackhaulSlotID: 1, BackhaulChannel: 149},
+		{
+			WtpMAC: childMAC, Role: "mesh-ap", ParentMAC: rootMAC, HopCount: 1,
+			BackhaulSlotID: 1, BackhaulChannel: 149, LinkSNR: 38, BackhaulDataRate: "390",
+		},
+		{WtpMAC: childMAC, Role: "mesh-ap", ParentMAC: quietMAC, HopCount: 2},
+		{WtpMAC: quietMAC, Role: "mesh-ap", ParentMAC: rootMAC},
+		{Role: "mesh-ap", ParentMAC: rootMAC, HopCount: 1},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Mesh: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion
+	// that the repeated record is published once.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	var edges []string
+	byMAC := make(map[string]map[string]float64, len(families))
+	for _, family := range families {
+		values := make(map[string]float64, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			if family.GetName() == "wnc_ap_mesh_info" {
+				edges = append(edges, labels[labelMAC]+">"+labels[labelParentMAC])
+			}
+			values[labels[labelMAC]] = metric.GetGauge().GetValue()
+		}
+		byMAC[family.GetName()] = values
+	}
+
+	slices.Sort(edges)
+	if want := []string{childMAC + ">" + rootMAC, quietMAC + ">" + rootMAC}; !slices.Equal(edges, want) {
+		t.Errorf("wnc_ap_mesh_info edges = %v, want %v", edges, want)
+	}
+
+	if got, ok := byMAC["wnc_ap_mesh_hops"][rootMAC]; !ok || got != 0 {
+		t.Errorf("wnc_ap_mesh_hops{mac=%q} = %v (present %v), want 0", rootMAC, got, ok)
+	}
+	if got := byMAC["wnc_ap_mesh_root"][childMAC]; got != 0 {
+		t.Errorf("wnc_ap_mesh_root{mac=%q} = %v, want 0", childMAC, got)
+	}
+	if got := byMAC["wnc_ap_mesh_link_rate_mbps"][childMAC]; got != 390 {
+		t.Errorf("wnc_ap_mesh_link_rate_mbps{mac=%q} = %v, want 390 from the first record", childMAC, got)
+	}
+
+	for _, name := range []string{
+		"wnc_ap_mesh_hops", "wnc_ap_mesh_backhaul_channel",
+		"wnc_ap_mesh_link_snr_db", "wnc_ap_mesh_link_rate_mbps",
+	} {
+		if _, ok := byMAC[name][quietMAC]; ok {
+			t.Errorf("%s{mac=%q} is present for a leaf the record does not carry", name, quietMAC)
+		}
+	}
+	for _, name := range []string{"wnc_ap_mesh_link_snr_db", "wnc_ap_mesh_link_rate_mbps"} {
+		if _, ok := byMAC[name][rootMAC]; ok {
+			t.Errorf("%s{mac=%q} is present for a root AP, which has no parent link", name, rootMAC)
+		}
+	}
+	if _, ok := byMAC["wnc_ap_mesh_root"][""]; ok {
+		t.Error("wnc_ap_mesh_root is present for a record with no MAC")
+	}
+}
+
+// TestAPQoSModule_WithholdsWhatTheRecordOmits pins the qos module: each queue is one
+// series per counter, a counter the record omits or garbles is withheld rather than
+// published as 0, a record naming no access category is dropped, and a repeated record
+// is published once.
+func TestAPQoSModule_WithholdsWhatTheRecordOmits(t *testing.T) {
+	t.Parallel()
+
+	data := fullFixtureSnapshot()
+	data.RadioWMMStats = []wnc.RadioWMMStats{
+		{WtpMAC: fixtureAPMAC, RadioSlotID: 1, AccessCategory: "voice", TxFrames: "120", QueueDrops: "4"},
+		{WtpMAC: fixtureAPMAC, RadioSlotID: 1, AccessCategory: "voice", TxFrames: "999", QueueDrops: "99"},
+		{WtpMAC: fixtureAPMAC, RadioSlotID: 1, AccessCategory: "video", TxFrames: "80", QueueDrops: "garbled"},
+		{WtpMAC: fixtureAPMAC, RadioSlotID: 1, TxFrames: "70", QueueDrops: "7"},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{QoS: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion
+	// that the repeated record is published once.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == labelAccessCategory {
+					got[family.GetName()+"|"+pair.GetValue()] = metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"wnc_ap_qos_transmitted_frames_total|voice": 120,
+		"wnc_ap_qos_queue_drops_total|voice":        4,
+		"wnc_ap_qos_transmitted_frames_total|video": 80,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("qos module published %v, want %v", got, want)
+	}
+}
+
+// TestAPRestartsModule_CountsTheInstantsThatMove pins the comparison: the first snapshot
+// only records, a join time that moves forward is a rejoin under the disconnect reason
+// spelled then, a boot time that moves is a reboot under the reboot reason, an instant
+// that moves by less than restartJitter counts nothing, a snapshot scraped twice is
+// compared once, and an AP absent past the retention is forgotten with its series.
+func TestAPRestartsModule_CountsTheInstantsThatMove(t *testing.T) {
+	t.Parallel()
+
+	bootedAt := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	joinedAt := bootedAt.Add(2 * time.Minute)
+
+	data := fullFixtureSnapshot()
+	setTimes := func(boot, join time.Time) {
+		data.CAPWAPData[0].ApTimeInfo.BootTime = boot.Format(time.RFC3339)
+		data.CAPWAPData[0].ApTimeInfo.JoinTime = join.Format(time.RFC3339)
+	}
+	setReasons := func(reboot, disconnect string) {
+		data.JoinStats = []ap.ApJoinStats{
+			{WtpMAC: fixtureAPMAC, RebootReason: reboot, DisconnectReason: disconnect},
+		}
+	}
+	data.CAPWAPData = data.CAPWAPData[:1]
+	data.CAPWAPData[0].WtpMAC = fixtureAPMAC
+	setTimes(bootedAt, joinedAt)
+	setReasons("ap-reboot-reason-none", "")
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Restarts: true},
+	))
+
+	// scrape returns every sample as "family|reason" mapped to its value, whichever of
+	// the two reason labels the family carries.
+	scrape := func() map[string]float64 {
+		t.Helper()
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v, want nil", err)
+		}
+
+		values := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == labelLastRebootReason || pair.GetName() == labelLastDisconnectReason {
+						values[family.GetName()+"|"+pair.GetValue()] = metric.GetCounter().GetValue()
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	if got := scrape(); len(got) != 0 {
+		t.Errorf("first scrape counted %v, want nothing: it only records", got)
+	}
+
+	// The AP rejoins without rebooting, and the controller re-derives the boot time a
+	// second later, which is the same instant.
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	setTimes(bootedAt.Add(time.Second), joinedAt.Add(time.Hour))
+	setReasons("ap-reboot-reason-none", "Heartbeat timeout")
+
+	want := map[string]float64{"wnc_ap_rejoins_observed_total|Heartbeat timeout": 1}
+	if got := scrape(); !maps.Equal(got, want) {
+		t.Errorf("after a rejoin counted %v, want %v", got, want)
+	}
+
+	// The AP reboots, which rejoins it too. The disconnect reason is left empty. The
+	// same snapshot is scraped twice and compared once.
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	setTimes(bootedAt.Add(2*time.Hour), joinedAt.Add(2*time.Hour))
+	setReasons("ap-reboot-reason-power-cycle", "")
+	scrape()
+
+	want = map[string]float64{
+		"wnc_ap_rejoins_observed_total|Heartbeat timeout":            1,
+		"wnc_ap_rejoins_observed_total|" + restartReasonUnknown:      1,
+		"wnc_ap_reboots_observed_total|ap-reboot-reason-power-cycle": 1,
+	}
+	if got := scrape(); !maps.Equal(got, want) {
+		t.Errorf("after a reboot counted %v, want %v", got, want)
+	}
+
+	// The AP leaves the inventory. It is remembered for the retention, then forgotten.
+	listed := data.CAPWAPData
+	data.CAPWAPData = nil
+	data.RefreshedAt = data.RefreshedAt.Add(restartRetention)
+	if got := scrape(); !maps.Equal(got, want) {
+		t.Errorf("within the retention counted %v, want %v", got, want)
+	}
+
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	if got := scrape(); len(got) != 0 {
+		t.Errorf("past the retention counted %v, want nothing", got)
+	}
+
+	// Coming back after being forgotten is a first sighting again, which only records.
+	data.CAPWAPData = listed
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	setTimes(bootedAt.Add(3*time.Hour), joinedAt.Add(3*time.Hour))
+	if got := scrape(); len(got) != 0 {
+		t.Errorf("after coming back counted %v, want nothing", got)
+	}
+}
+
+// TestAPDepartedModule_ReportsAPsThatLeftTheInventory pins the record: an AP that leaves
+// the inventory keeps its last-seen time and reads 0 in wnc_ap_joined, unless the join
+// module already publishes that series for it, the last-seen time is the refresh behind
+// the snapshot, the record survives a restart through the state file, which is written
+// once per snapshot, and an AP past the retention is dropped.
+func TestAPDepartedModule_ReportsAPsThatLeftTheInventory(t *testing.T) {
+	t.Parallel()
+
+	const stayer, leaver = fixtureAPMAC, "aa:bb:cc:00:00:02"
+	stateFile := filepath.Join(t.TempDir(), "departed.json")
+
+	data := fullFixtureSnapshot()
+	data.CAPWAPData = []ap.CAPWAPData{{WtpMAC: stayer}, {WtpMAC: leaver}}
+	data.JoinStats = []ap.ApJoinStats{{WtpMAC: stayer}}
+
+	// scrape gathers a fresh collector's registry and returns every sample of the two
+	// families as "family|mac" mapped to its value.
+	scrape := func(registry *prometheus.Registry) map[string]float64 {
+		t.Helper()
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v, want nil", err)
+		}
+
+		values := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == labelMAC {
+						values[family.GetName()+"|"+pair.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return values
+	}
+	newRegistry := func(retention time.Duration) *prometheus.Registry {
+		src := fixtureSource{data: data}
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(NewAPCollector(
+			wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+			APMetrics{Departed: true, DepartedRetention: retention, DepartedStateFile: stateFile},
+		))
+		return registry
+	}
+
+	registry := newRegistry(time.Hour)
+	if got := scrape(registry); len(got) != 2 || got["wnc_ap_joined|"+leaver] != 0 {
+		t.Fatalf("first scrape published %v, want the last-seen time of both APs alone", got)
+	}
+	// The same snapshot scraped again leaves the state file alone.
+	if err := os.Remove(stateFile); err != nil {
+		t.Fatalf("Remove() error = %v, want nil", err)
+	}
+	if _, ok := scrape(registry)["wnc_ap_joined|"+leaver]; ok {
+		t.Errorf("an AP still listed reads wnc_ap_joined, want it left to the join module")
+	}
+	if _, err := os.Stat(stateFile); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() error = %v after a scrape of the same snapshot, want it not rewritten", err)
+	}
+
+	listedAt := data.RefreshedAt
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	data.CAPWAPData = data.CAPWAPData[:1]
+	got := scrape(registry)
+	if joined, ok := got["wnc_ap_joined|"+leaver]; !ok || joined != 0 {
+		t.Errorf("departed AP wnc_ap_joined = %v (present %v), want 0", joined, ok)
+	}
+	if seenAt := got["wnc_ap_last_seen_timestamp_seconds|"+leaver]; seenAt != float64(listedAt.Unix()) {
+		t.Errorf("departed AP last-seen time = %v, want %v, the refresh that last listed it",
+			seenAt, listedAt.Unix())
+	}
+	if seenAt := got["wnc_ap_last_seen_timestamp_seconds|"+stayer]; seenAt != float64(data.RefreshedAt.Unix()) {
+		t.Errorf("listed AP last-seen time = %v, want %v, the refresh behind the snapshot",
+			seenAt, data.RefreshedAt.Unix())
+	}
+
+	// A restart reloads the record, so the departed AP is still reported.
+	if _, ok := scrape(newRegistry(time.Hour))["wnc_ap_joined|"+leaver]; !ok {
+		t.Errorf("after a restart the departed AP is gone, want it loaded from the state file")
+	}
+
+	// The join module publishes wnc_ap_joined from the record the AP left behind, so
+	// the departed module must not publish it again. Gather fails on a duplicate.
+	src := fixtureSource{data: data}
+	joinRegistry := prometheus.NewRegistry()
+	joinRegistry.MustRegister(NewAPCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		APMetrics{Join: true, Departed: true, DepartedRetention: time.Hour, DepartedStateFile: stateFile},
+	))
+	data.CAPWAPData = nil
+	if _, err := joinRegistry.Gather(); err != nil {
+		t.Errorf("Gather() with the join module error = %v, want nil", err)
+	}
+
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	if got := scrape(newRegistry(time.Nanosecond)); len(got) != 0 {
+		t.Errorf("past the retention published %v, want nothing", got)
+	}
+}
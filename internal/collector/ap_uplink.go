@@ -0,0 +1,203 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the uplink neighbor module of the AP collector.
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// apUplinkDescs holds the descriptors of the uplink module. A nil value means the module
+// is disabled, which is what keeps every series of it out of a default scrape.
+type apUplinkDescs struct {
+	info       *prometheus.Desc
+	speed      *prometheus.Desc
+	fullDuplex *prometheus.Desc
+	powerFull  *prometheus.Desc
+}
+
+// newAPUplinkDescs builds the descriptors of the uplink module.
+//
+// Every series is keyed by the AP radio MAC, the key of wnc_ap_joined, so an alert on a
+// departed AP can pull in the switch port with a plain join on mac. The neighbor is
+// published as labels of an info series rather than on the readings, because a port
+// move would otherwise start fresh series for the link settings beside it.
+func newAPUplinkDescs(families *familySet) *apUplinkDescs {
+	apLabels := []string{labelMAC}
+
+	return &apUplinkDescs{
+		info: families.gauge(
+			"wnc_ap_uplink_info",
+			"Neighbor the AP's Ethernet uplink is cabled to, always 1. CDP entries are used "+
+				"where the AP has any, and LLDP entries only for an AP with none; platform is "+
+				"empty for an LLDP neighbor",
+			[]string{labelMAC, labelNeighbor, labelPort, labelPlatform}, wnc.DataAPCDPCacheData, wnc.DataAPLLDPNeigh,
+		),
+		speed: families.gauge(
+			"wnc_ap_uplink_speed_mbps",
+			"Ethernet link speed of the AP's uplink in Mbps, as its CDP neighbor reports it",
+			apLabels, wnc.DataAPCDPCacheData,
+		),
+		fullDuplex: families.gauge(
+			"wnc_ap_uplink_full_duplex",
+			"Whether the AP's uplink runs full duplex (1) or half duplex (0), as its CDP "+
+				"neighbor reports it. Absent when the neighbor reports neither",
+			apLabels, wnc.DataAPCDPCacheData,
+		),
+		powerFull: families.gauge(
+			"wnc_ap_uplink_power_full",
+			"Whether the AP draws full power from its uplink (1=full-power, 0=any other "+
+				"value). 0 usually means a PoE budget the switch port cannot meet, which "+
+				"leaves radios disabled or derated",
+			apLabels, wnc.DataAPPwrInfo,
+		),
+	}
+}
+
+// describe sends every descriptor of the uplink module.
+func (d *apUplinkDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.info
+	ch <- d.speed
+	ch <- d.fullDuplex
+	ch <- d.powerFull
+}
+
+// uplinkReads carries the three lists the uplink module reads, each with whether its
+// fetch succeeded, because a failed list and an empty one withhold different series.
+type uplinkReads struct {
+	cdp     []wnc.CDPNeighbor
+	cdpOK   bool
+	lldp    []wnc.LLDPNeighbor
+	lldpOK  bool
+	power   []wnc.APPowerInfo
+	powerOK bool
+}
+
+// collect publishes the uplink module for every AP either neighbor list names.
+//
+// The neighbor series need the CDP list even for an AP that has none: LLDP is consulted
+// only for an AP CDP does not name, and without the CDP list that set is unknown.
+// Publishing LLDP for every AP in that case would move an AP from one label set to
+// another across a failed read and back, so the whole info family is withheld instead.
+func (d *apUplinkDescs) collect(ch chan<- prometheus.Metric, reads uplinkReads) {
+	if reads.cdpOK {
+		d.collectNeighbors(ch, reads.cdp, reads.lldp, reads.lldpOK)
+		d.collectLink(ch, reads.cdp)
+	}
+
+	if reads.powerOK {
+		d.collectPower(ch, reads.power)
+	}
+}
+
+// collectNeighbors publishes one info series per distinct neighbor of each AP.
+//
+// The CDP cache is keyed by more than the neighbor and the port, so two entries can
+// carry the same label set, and Gather rejects a duplicate by failing the entire
+// endpoint. Each label set is therefore emitted once.
+func (d *apUplinkDescs) collectNeighbors(
+	ch chan<- prometheus.Metric, cdp []wnc.CDPNeighbor, lldp []wnc.LLDPNeighbor, lldpOK bool,
+) {
+	seen := make(map[[4]string]bool, len(cdp)+len(lldp))
+	emit := func(labels [4]string) {
+		if labels[0] == "" || seen[labels] {
+			return
+		}
+
+		seen[labels] = true
+		ch <- prometheus.MustNewConstMetric(
+			d.info, prometheus.GaugeValue, 1, labels[0], labels[1], labels[2], labels[3],
+		)
+	}
+
+	hasCDP := make(map[string]bool, len(cdp))
+	for i := range cdp {
+		neighbor := &cdp[i]
+		hasCDP[neighbor.WtpMAC] = true
+		emit([4]string{neighbor.WtpMAC, neighbor.DeviceID, neighbor.PortID, neighbor.Platform})
+	}
+
+	if !lldpOK {
+		return
+	}
+
+	for i := range lldp {
+		neighbor := &lldp[i]
+		if hasCDP[neighbor.WtpMAC] {
+			continue
+		}
+
+		// A neighbor that advertises no system name is still identified by its chassis
+		// MAC, which every LLDP entry carries as part of its key.
+		name := neighbor.SystemName
+		if name == "" {
+			name = neighbor.NeighborMAC
+		}
+
+		emit([4]string{neighbor.WtpMAC, name, neighbor.PortID, ""})
+	}
+}
+
+// collectLink publishes the link settings from the first CDP entry of each AP. An AP
+// has one Ethernet uplink in service, so a second entry names another device on the
+// same segment rather than a second link, and reading it would publish the settings of
+// a port the AP is not cabled to.
+func (d *apUplinkDescs) collectLink(ch chan<- prometheus.Metric, cdp []wnc.CDPNeighbor) {
+	done := make(map[string]bool, len(cdp))
+
+	for i := range cdp {
+		neighbor := &cdp[i]
+		if neighbor.WtpMAC == "" || done[neighbor.WtpMAC] {
+			continue
+		}
+		done[neighbor.WtpMAC] = true
+
+		if speed, err := neighbor.InterfaceSpeed.Float64(); err == nil && speed > 0 {
+			ch <- prometheus.MustNewConstMetric(d.speed, prometheus.GaugeValue, speed, neighbor.WtpMAC)
+		}
+
+		if full, ok := uplinkFullDuplex(neighbor.Duplex); ok {
+			ch <- prometheus.MustNewConstMetric(
+				d.fullDuplex, prometheus.GaugeValue, boolToFloat64(full), neighbor.WtpMAC,
+			)
+		}
+	}
+}
+
+// collectPower publishes the power status of every AP the power list carries, and
+// nothing for an AP whose status is empty, since an absent leaf is not a reading.
+func (d *apUplinkDescs) collectPower(ch chan<- prometheus.Metric, power []wnc.APPowerInfo) {
+	seen := make(map[string]bool, len(power))
+
+	for i := range power {
+		record := &power[i]
+		if record.WtpMAC == "" || record.Status == "" || seen[record.WtpMAC] {
+			continue
+		}
+		seen[record.WtpMAC] = true
+
+		ch <- prometheus.MustNewConstMetric(
+			d.powerFull, prometheus.GaugeValue,
+			boolToFloat64(record.Status == APPowerStatusFull), record.WtpMAC,
+		)
+	}
+}
+
+// uplinkFullDuplex reads the duplex a CDP neighbor reports, and reports false for a
+// spelling that names neither mode. The leaf is matched by substring because releases
+// spell it both bare and with a prefix.
+func uplinkFullDuplex(duplex string) (full, ok bool) {
+	duplex = strings.ToLower(duplex)
+
+	switch {
+	case strings.Contains(duplex, "full"):
+		return true, true
+	case strings.Contains(duplex, "half"):
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -2,6 +2,7 @@
 package collector
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -23,17 +24,35 @@ type InfoCacheCollector struct {
 	base      prometheus.Collector
 	infoCache *MetricsCache
 	name      string
+	// infoDescs are the descriptors of the families the cache holds, decided once from
+	// the families base declares.
+	infoDescs map[*prometheus.Desc]bool
 }
 
 // NewInfoCacheCollector creates a new collector with info-only metrics caching.
 func NewInfoCacheCollector(base prometheus.Collector, name string, cacheTTL time.Duration) *InfoCacheCollector {
+	infoDescs := make(map[*prometheus.Desc]bool)
+	if families := declaredFamilies(base); families != nil {
+		for desc := range families.byDesc {
+			if f, _ := families.lookup(desc); isInfoFamily(f.name) {
+				infoDescs[desc] = true
+			}
+		}
+	}
+
 	return &InfoCacheCollector{
 		base:      base,
 		infoCache: cache.New[[]prometheus.Metric](cacheTTL, name+" info metrics cache"),
 		name:      name,
+		infoDescs: infoDescs,
 	}
 }
 
+// declaredFamilies passes the declaration of the base collector through.
+func (c *InfoCacheCollector) declaredFamilies() *familySet {
+	return declaredFamilies(c.base)
+}
+
 // Describe implements prometheus.Collector interface by delegating to base collector.
 func (c *InfoCacheCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.base.Describe(ch)
@@ -54,7 +73,7 @@ func (c *InfoCacheCollector) Collect(ch chan<- prometheus.Metric) {
 	var infoMetrics []prometheus.Metric
 
 	for metric := range baseCh {
-		if isInfoMetric(metric) {
+		if c.infoDescs[metric.Desc()] {
 			infoMetrics = append(infoMetrics, metric)
 		} else {
 			nonInfoMetrics = append(nonInfoMetrics, metric)
@@ -79,13 +98,15 @@ func (c *InfoCacheCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
-// isInfoMetric determines if a metric belongs to an _info family, the only class this
-// cache holds. Desc exposes no accessor for the name, so the name is read back out of
-// Desc.String, whose single return path renders it first and quoted; a descriptor
-// carrying an error renders an empty name there rather than another shape.
-func isInfoMetric(metric prometheus.Metric) bool {
-	_, quoted, _ := strings.Cut(metric.Desc().String(), `fqName: "`)
-	fqName, _, _ := strings.Cut(quoted, `"`)
+// liveInfoFamilies are _info families that describe topology which changes under a
+// running AP: the switch port it is cabled to, the mesh parent it backhauls through and
+// the name of a neighbor it hears. The cache would hide such a change for up to its TTL,
+// so they are served fresh on every scrape like any other family.
+var liveInfoFamilies = []string{"wnc_ap_mesh_info", "wnc_ap_neighbor_info", "wnc_ap_uplink_info"}
 
-	return strings.HasSuffix(fqName, "_info")
+// isInfoFamily reports whether the family of the given name is one the cache holds:
+// every _info family but liveInfoFamilies. The name is the one the family is declared
+// under, so neither its help text nor its label names can move it into the cache.
+func isInfoFamily(name string) bool {
+	return strings.HasSuffix(name, "_info") && !slices.Contains(liveInfoFamilies, name)
 }
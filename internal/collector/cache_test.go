@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -10,21 +11,28 @@ import (
 
 // testCollector is a simple collector for testing purposes.
 type testCollector struct {
-	desc    *prometheus.Desc
-	metrics []prometheus.Metric
+	families *familySet
+	metrics  []prometheus.Metric
 }
 
 func newTestCollector(metricName string) *testCollector {
-	desc := prometheus.NewDesc(metricName, "Test metric", nil, nil)
+	families := &familySet{}
+	desc := families.gauge(metricName, "Test metric", nil)
 	metric := prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1.0)
 	return &testCollector{
-		desc:    desc,
-		metrics: []prometheus.Metric{metric},
+		families: families,
+		metrics:  []prometheus.Metric{metric},
 	}
 }
 
+func (c *testCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
 func (c *testCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.desc
+	for desc := range c.families.byDesc {
+		ch <- desc
+	}
 }
 
 func (c *testCollector) Collect(ch chan<- prometheus.Metric) {
@@ -117,15 +125,16 @@ func TestInfoCacheCollector_Collect_InfoMetrics(t *testing.T) {
 func TestInfoCacheCollector_Collect_MixedMetrics(t *testing.T) {
 	t.Parallel()
 	// Create a collector with both info and non-info metrics
+	families := &familySet{}
 	collector := &testCollector{
-		desc: prometheus.NewDesc("mixed_test", "Mixed test", nil, nil),
+		families: families,
 		metrics: []prometheus.Metric{
 			prometheus.MustNewConstMetric(
-				prometheus.NewDesc("test_info", "Info metric", nil, nil),
+				families.gauge("test_info", "Info metric", nil),
 				prometheus.GaugeValue, 1.0,
 			),
 			prometheus.MustNewConstMetric(
-				prometheus.NewDesc("test_count", "Count metric", nil, nil),
+				families.counter("test_count", "Count metric", nil),
 				prometheus.CounterValue, 42.0,
 			),
 		},
@@ -147,66 +156,32 @@ func TestInfoCacheCollector_Collect_MixedMetrics(t *testing.T) {
 	}
 }
 
-func TestIsInfoMetric(t *testing.T) {
+func TestIsInfoFamily(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name     string
-		metric   prometheus.Metric
+		family   string
 		expected bool
 	}{
-		{
-			name: "info metric",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_ap_info", "AP info", nil, nil),
-				prometheus.GaugeValue,
-				1.0,
-			),
-			expected: true,
-		},
-		{
-			name: "client info metric",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_client_info", "Client info", nil, nil),
-				prometheus.GaugeValue,
-				1.0,
-			),
-			expected: true,
-		},
-		{
-			name: "wlan info metric",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_wlan_info", "WLAN info", nil, nil),
-				prometheus.GaugeValue,
-				1.0,
-			),
-			expected: true,
-		},
-		{
-			name: "non-info metric",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_ap_count", "AP count", nil, nil),
-				prometheus.GaugeValue,
-				1.0,
-			),
-			expected: false,
-		},
-		{
-			name: "counter metric",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_bytes_total", "Total bytes", nil, nil),
-				prometheus.CounterValue,
-				1000.0,
-			),
-			expected: false,
-		},
+		{name: "info metric", family: "wnc_ap_info", expected: true},
+		{name: "client info metric", family: "wnc_client_info", expected: true},
+		{name: "wlan info metric", family: "wnc_wlan_info", expected: true},
+		{name: "live topology info metric", family: "wnc_ap_uplink_info", expected: false},
+		{name: "non-info metric", family: "wnc_ap_count", expected: false},
+		{name: "counter metric", family: "wnc_bytes_total", expected: false},
+		{name: "info at the beginning", family: "info_test_metric", expected: false},
+		{name: "info between two words", family: "wnc_ap_info_labels_total", expected: false},
+		// The name ends in the upper-case form deliberately: one merely containing it
+		// fails the suffix test whatever the case, so it would pass against a
+		// predicate that folded case before comparing.
+		{name: "case sensitive", family: "test_metric_INFO", expected: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			result := isInfoMetric(tt.metric)
-			if result != tt.expected {
-				t.Errorf("isInfoMetric() = %v, expected %v", result, tt.expected)
+			if got := isInfoFamily(tt.family); got != tt.expected {
+				t.Errorf("isInfoFamily(%s) = %v, expected %v", tt.family, got, tt.expected)
 			}
 		})
 	}
@@ -257,48 +232,11 @@ func TestMetricChannelBuffer(t *testing.T) {
 	}
 }
 
-func TestIsInfoMetric_DescString(t *testing.T) {
-	t.Parallel()
-	// Test that isInfoMetric correctly parses the descriptor string
-	desc := prometheus.NewDesc(
-		"test_metric_info",
-		"Test info metric",
-		[]string{"label1"},
-		prometheus.Labels{"const": "value"},
-	)
-	metric := prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1.0, "label_value")
-
-	if !isInfoMetric(metric) {
-		t.Error("expected metric with '_info' in name to be identified as info metric")
-	}
-
-	// Test edge case: info at the beginning (should NOT match since it's not "_info")
-	desc2 := prometheus.NewDesc("info_test_metric", "Info test metric", nil, nil)
-	metric2 := prometheus.MustNewConstMetric(desc2, prometheus.GaugeValue, 1.0)
-
-	if isInfoMetric(metric2) {
-		t.Error("expected metric with 'info_' at beginning to NOT be identified as info metric (requires '_info')")
-	}
-
-	// Test case sensitivity. The name ends in the upper-case form deliberately: one
-	// merely containing it fails the suffix test whatever the case, so it would pass
-	// against a predicate that folded case before comparing.
-	desc3 := prometheus.NewDesc("test_metric_INFO", "Test INFO metric", nil, nil)
-	metric3 := prometheus.MustNewConstMetric(desc3, prometheus.GaugeValue, 1.0)
-
-	result := isInfoMetric(metric3)
-	if result {
-		t.Error("expected case-sensitive matching for info detection")
-	}
-}
-
 func TestInfoCacheCollector_Collect_EmptyMetrics(t *testing.T) {
 	t.Parallel()
 	// Test behavior with a collector that returns no metrics
-	emptyCollector := &testCollector{
-		desc:    prometheus.NewDesc("empty_test", "Empty test", nil, nil),
-		metrics: []prometheus.Metric{}, // No metrics
-	}
+	emptyCollector := newTestCollector("empty_test")
+	emptyCollector.metrics = []prometheus.Metric{} // No metrics
 
 	cacheCollector := NewInfoCacheCollector(emptyCollector, "empty", 5*time.Second)
 
@@ -336,108 +274,78 @@ func TestInfoCacheCollector_Collect_CacheError(t *testing.T) {
 	}
 }
 
-// TestIsInfoMetric_KeysOnlyOnTheNameSuffix holds the predicate to the fully qualified
-// name. Desc.String embeds the HELP text and the variable label names either side of
-// the name, so a substring test reaches prose an author writes freely: a counter whose
-// HELP merely names an _info family would land in a cache sized for label churn and
-// then report one value for a whole TTL, which turns rate() into a staircase. The HELP
-// text and the label names below are the hypothetical ones, not the published ones.
-func TestIsInfoMetric_KeysOnlyOnTheNameSuffix(t *testing.T) {
+// TestInfoCacheCollector_KeysOnlyOnTheDeclaredName holds the cache to the name a family
+// is declared under. The help text and the variable label names sit either side of the
+// name in a descriptor, and are prose an author writes freely: a counter whose help
+// merely names an _info family would land in a cache sized for label churn and then
+// report one value for a whole TTL, which turns rate() into a staircase. The help text
+// and the label names below are the hypothetical ones, not the published ones.
+func TestInfoCacheCollector_KeysOnlyOnTheDeclaredName(t *testing.T) {
 	t.Parallel()
+
+	families := &familySet{}
 	tests := []struct {
 		name     string
-		metric   prometheus.Metric
+		desc     *prometheus.Desc
 		expected bool
 	}{
 		{
 			name: "help names an info family",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc(
-					"wnc_ap_uptime_seconds",
-					"Seconds since the AP booted. Join wnc_ap_info for the AP name.",
-					nil, nil,
-				),
-				prometheus.GaugeValue,
-				1.0,
-			),
+			desc: families.gauge("wnc_ap_uptime_seconds",
+				"Seconds since the AP booted. Join wnc_ap_info for the AP name.", nil),
 			expected: false,
 		},
 		{
-			name: "variable label name ends in _info",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_ap_channel_number", "Radio channel", []string{"radio_info"}, nil),
-				prometheus.GaugeValue,
-				36.0,
-				"0",
-			),
-			expected: false,
-		},
-		{
-			name: "name carries _info between two words",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_ap_info_labels_total", "Info labels published", nil, nil),
-				prometheus.CounterValue,
-				7.0,
-			),
+			name:     "variable label name ends in _info",
+			desc:     families.gauge("wnc_ap_channel_number", "Radio channel", []string{"radio_info"}),
 			expected: false,
 		},
 		{
-			name: "name ends in _info",
-			metric: prometheus.MustNewConstMetric(
-				prometheus.NewDesc("wnc_ap_join_info", "AP join info", nil, nil),
-				prometheus.GaugeValue,
-				1.0,
-			),
+			name:     "name ends in _info",
+			desc:     families.gauge("wnc_ap_join_info", "AP join info", nil),
 			expected: true,
 		},
 	}
+	collector := NewInfoCacheCollector(&testCollector{families: families}, "test", time.Hour)
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			if got := isInfoMetric(tt.metric); got != tt.expected {
-				t.Errorf("isInfoMetric(%s) = %v, expected %v", metricFamilyName(t, tt.metric), got, tt.expected)
-			}
-		})
+		if got := collector.infoDescs[tt.desc]; got != tt.expected {
+			t.Errorf("%s: cached = %v, expected %v", tt.name, got, tt.expected)
+		}
 	}
 }
 
-// TestIsInfoMetric_MatchesOnlyTheInfoFamilies keeps every other family out of the info
-// cache across the whole published surface, where the table above states the rule over
-// four names. Nothing else in the suite would notice a family joining the cache.
-func TestIsInfoMetric_MatchesOnlyTheInfoFamilies(t *testing.T) {
+// TestInfoCacheCollector_CachesOnlyTheInfoFamilies keeps every other family out of the
+// info cache across the whole published surface, where the tests above state the rule
+// over a few names. Nothing else in the suite would notice a family joining the cache,
+// or a live topology family falling into it.
+func TestInfoCacheCollector_CachesOnlyTheInfoFamilies(t *testing.T) {
 	t.Parallel()
 
-	metrics := make(chan prometheus.Metric, MetricChannelBuffer*10)
-	go func() {
-		defer close(metrics)
-		for _, collector := range fixtureCollectors(t, fullFixtureSnapshot()) {
-			collector.Collect(metrics)
-		}
-	}()
-
 	seen := 0
-	for metric := range metrics {
-		seen++
-		name := metricFamilyName(t, metric)
-		if got, want := isInfoMetric(metric), strings.HasSuffix(name, "_info"); got != want {
-			t.Errorf("isInfoMetric(%s) = %v, want %v", name, got, want)
+	for _, base := range fixtureCollectors(t, fullFixtureSnapshot()) {
+		collector := NewInfoCacheCollector(base, "test", time.Hour)
+		families := declaredFamilies(base)
+
+		metrics := make(chan prometheus.Metric, MetricChannelBuffer*10)
+		go func() {
+			defer close(metrics)
+			base.Collect(metrics)
+		}()
+		for metric := range metrics {
+			seen++
+			f, ok := families.lookup(metric.Desc())
+			if !ok {
+				t.Errorf("%T collected an undeclared descriptor %s", base, metric.Desc())
+				continue
+			}
+			want := strings.HasSuffix(f.name, "_info") && !slices.Contains(liveInfoFamilies, f.name)
+			if got := collector.infoDescs[metric.Desc()]; got != want {
+				t.Errorf("%s cached = %v, want %v", f.name, got, want)
+			}
 		}
 	}
 	if seen == 0 {
 		t.Fatal("no metric was collected, so the classification above proves nothing")
 	}
 }
-
-// metricFamilyName reads the fully qualified name back out of the descriptor, which
-// exposes no accessor for it.
-func metricFamilyName(t *testing.T, metric prometheus.Metric) string {
-	t.Helper()
-
-	_, rest, found := strings.Cut(metric.Desc().String(), `fqName: "`)
-	if !found {
-		t.Fatalf("descriptor %q carries no fqName", metric.Desc().String())
-	}
-	name, _, _ := strings.Cut(rest, `"`)
-	return name
-}
@@ -0,0 +1,380 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the metric catalog: every descriptor the collectors register for a
+// module configuration, read from the collectors themselves rather than kept by hand.
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// Catalog output formats.
+const (
+	CatalogFormatMarkdown = "markdown"
+	CatalogFormatJSON     = "json"
+)
+
+// CatalogFormats lists the output formats WriteCatalog accepts.
+var CatalogFormats = []string{CatalogFormatMarkdown, CatalogFormatJSON}
+
+// catalogExporter is the collector the catalog lists the series of no module under:
+// the build information and the refresh health series.
+const catalogExporter = "exporter"
+
+// errUnknownCatalogFormat is returned by WriteCatalog for a format not in CatalogFormats.
+var errUnknownCatalogFormat = errors.New("unknown catalog format")
+
+// errUndeclaredCollector is returned by the catalog for a collector that does not
+// declare its families.
+var errUndeclaredCollector = errors.New("collector declares no metric families")
+
+// CatalogEntry describes one metric family.
+type CatalogEntry struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"`
+	// Collector and Modules name the --collector.<collector>.<module> flags that enable
+	// the family, any one of them being enough. The exporter's own families have none.
+	Collector string   `json:"collector"`
+	Modules   []string `json:"modules"`
+	// DataTypes are the data types the family is read from, as the data label of the
+	// refresh series names them. A failed one can withhold the family.
+	DataTypes []string `json:"data_types"`
+}
+
+// catalogModule is one collector module, with the flag that enables it.
+type catalogModule struct {
+	collector string
+	name      string
+	flag      func(modules *config.Collectors) *bool
+}
+
+// catalogModules lists every module in the order the collectors are registered.
+var catalogModules = []catalogModule{
+	{"ap", "general", func(m *config.Collectors) *bool { return &m.AP.General }},
+	{"ap", "radio", func(m *config.Collectors) *bool { return &m.AP.Radio }},
+	{"ap", "traffic", func(m *config.Collectors) *bool { return &m.AP.Traffic }},
+	{"ap", "errors", func(m *config.Collectors) *bool { return &m.AP.Errors }},
+	{"ap", "join", func(m *config.Collectors) *bool { return &m.AP.Join }},
+	{"ap", "uplink", func(m *config.Collectors) *bool { return &m.AP.Uplink }},
+	{"ap", "mesh", func(m *config.Collectors) *bool { return &m.AP.Mesh }},
+	{"ap", "qos", func(m *config.Collectors) *bool { return &m.AP.QoS }},
+	{"ap", "restarts", func(m *config.Collectors) *bool { return &m.AP.Restarts }},
+	{"ap", "departed", func(m *config.Collectors) *bool { return &m.AP.Departed }},
+	{"ap", "neighbors", func(m *config.Collectors) *bool { return &m.AP.Neighbors }},
+	{"ap", "spectrum", func(m *config.Collectors) *bool { return &m.AP.Spectrum }},
+	{"ap", "interferers", func(m *config.Collectors) *bool { return &m.AP.Interferers }},
+	{"ap", "info", func(m *config.Collectors) *bool { return &m.AP.Info }},
+	{"wlan", "general", func(m *config.Collectors) *bool { return &m.WLAN.General }},
+	{"wlan", "traffic", func(m *config.Collectors) *bool { return &m.WLAN.Traffic }},
+	{"wlan", "config", func(m *config.Collectors) *bool { return &m.WLAN.Config }},
+	{"wlan", "applications", func(m *config.Collectors) *bool { return &m.WLAN.Applications }},
+	{"wlan", "atf", func(m *config.Collectors) *bool { return &m.WLAN.ATF }},
+	{"wlan", "info", func(m *config.Collectors) *bool { return &m.WLAN.Info }},
+	{"controller", "general", func(m *config.Collectors) *bool { return &m.Controller.General }},
+	{"controller", "aaa", func(m *config.Collectors) *bool { return &m.Controller.AAA }},
+	{"rrm", "channels", func(m *config.Collectors) *bool { return &m.RRM.Channels }},
+	{"client", "general", func(m *config.Collectors) *bool { return &m.Client.General }},
+	{"client", "radio", func(m *config.Collectors) *bool { return &m.Client.Radio }},
+	{"client", "traffic", func(m *config.Collectors) *bool { return &m.Client.Traffic }},
+	{"client", "errors", func(m *config.Collectors) *bool { return &m.Client.Errors }},
+	{"client", "devices", func(m *config.Collectors) *bool { return &m.Client.Devices }},
+	{"client", "onboarding", func(m *config.Collectors) *bool { return &m.Client.Onboarding }},
+	{"client", "sessions", func(m *config.Collectors) *bool { return &m.Client.Sessions }},
+	{"client", "info", func(m *config.Collectors) *bool { return &m.Client.Info }},
+}
+
+// EnableAllModules enables every collector module, for a catalog of every family the
+// exporter can publish.
+func EnableAllModules(modules *config.Collectors) {
+	for _, module := range catalogModules {
+		*module.flag(modules) = true
+	}
+}
+
+// Catalog returns every metric family the collectors register for the configuration:
+// the exporter's own first, then each module's in registration order. Each module is
+// registered on its own, against a registerer that only keeps the families the
+// collectors declare, so a family is attributed to the modules that register it and
+// nothing is fetched or published.
+func Catalog(cfg *config.Config) ([]CatalogEntry, error) {
+	var moduleEntries []CatalogEntry
+	for _, module := range catalogModules {
+		if !*module.flag(&cfg.Collectors) {
+			continue
+		}
+		entries, err := describeModule(cfg, module)
+		if err != nil {
+			return nil, err
+		}
+		moduleEntries = mergeCatalogEntries(moduleEntries, entries)
+	}
+
+	described := &describingRegisterer{}
+	(&Collector{registerer: described, cfg: cfg}).RegisterBuildInfo("")
+	entries, err := described.entries(catalogExporter, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The refresh health series are registered once any module is, and carry the
+	// constant labels as the module series do.
+	if len(moduleEntries) > 0 {
+		refresh := &describingRegisterer{}
+		refresh.MustRegister(NewRefreshCollector(nil))
+		refreshEntries, err := refresh.entries(catalogExporter, "", constLabelNames(cfg))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, refreshEntries...)
+	}
+
+	return append(entries, moduleEntries...), nil
+}
+
+// mergeCatalogEntries appends entries to catalog, folding a family already listed,
+// such as wnc_ap_joined which two modules publish, into its first entry.
+func mergeCatalogEntries(catalog, entries []CatalogEntry) []CatalogEntry {
+	for _, entry := range entries {
+		i := slices.IndexFunc(catalog, func(listed CatalogEntry) bool { return listed.Name == entry.Name })
+		if i < 0 {
+			catalog = append(catalog, entry)
+			continue
+		}
+		catalog[i].Modules = append(catalog[i].Modules, entry.Modules...)
+		for _, dataType := range entry.DataTypes {
+			if !slices.Contains(catalog[i].DataTypes, dataType) {
+				catalog[i].DataTypes = append(catalog[i].DataTypes, dataType)
+			}
+		}
+	}
+	return catalog
+}
+
+// WriteCatalog writes the catalog to w in the given format.
+func WriteCatalog(w io.Writer, entries []CatalogEntry, format string) error {
+	switch format {
+	case CatalogFormatMarkdown:
+		return writeCatalogMarkdown(w, entries)
+	case CatalogFormatJSON:
+		if entries == nil {
+			entries = []CatalogEntry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownCatalogFormat, format)
+	}
+}
+
+// writeCatalogMarkdown writes one table per collector, in the layout of the collector
+// documents.
+func writeCatalogMarkdown(w io.Writer, entries []CatalogEntry) error {
+	var b strings.Builder
+	b.WriteString("# Metric catalog\n")
+
+	collector := ""
+	for _, entry := range entries {
+		if entry.Collector != collector {
+			collector = entry.Collector
+			fmt.Fprintf(&b, "\n## %s\n\n", collector)
+			b.WriteString("| Module | Metric | Type | Labels | Data types | Description |\n")
+			b.WriteString("| :----- | :----- | :--- | :----- | :--------- | :---------- |\n")
+		}
+
+		modules := strings.Join(entry.Modules, ", ")
+		if modules == "" {
+			modules = "-"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s | %s |\n",
+			modules, entry.Name, entry.Type, codeList(entry.Labels), codeList(entry.DataTypes),
+			strings.ReplaceAll(entry.Help, "|", `\|`))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// codeList formats names as a comma-separated list of code spans, or "-" for none.
+func codeList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "`" + name + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// describeModule returns a catalog entry per family the module registers, leaving out
+// those the metric include and exclude lists withhold.
+func describeModule(cfg *config.Config, module catalogModule) ([]CatalogEntry, error) {
+	// The module's parameters, such as its info labels, are kept, and its state file
+	// is not loaded.
+	moduleCfg := *cfg
+	for _, other := range catalogModules {
+		*other.flag(&moduleCfg.Collectors) = false
+	}
+	*module.flag(&moduleCfg.Collectors) = true
+	moduleCfg.Collectors.AP.DepartedStateFile = ""
+
+	// Without a data source the refresh collector is not registered, so only the
+	// module's own collector is described. It is registered without the constant
+	// labels, whose wrapper would hide the declaration, and they are listed instead.
+	moduleCfg.Collectors.ConstLabels = nil
+	described := &describingRegisterer{}
+	(&Collector{registerer: described, cfg: &moduleCfg}).RegisterServiceCollectors()
+	return described.entries(module.collector, module.name, constLabelNames(cfg))
+}
+
+// constLabelNames returns the names of the configured constant labels, in the order
+// they come first in a series.
+func constLabelNames(cfg *config.Config) []string {
+	return slices.Sorted(maps.Keys(cfg.Collectors.ConstLabels))
+}
+
+// ActiveModules returns the modules of the configuration with every module disabled
+// whose families the metric include and exclude lists withhold all of, so they are not
+// registered. RequiredDataTypes decides what is fetched for the modules left.
+func ActiveModules(cfg *config.Config) config.Collectors {
+	modules := cfg.Collectors
+	if !modules.FiltersMetrics() {
+		return modules
+	}
+
+	unfiltered := *cfg
+	unfiltered.Collectors.MetricsInclude = nil
+	unfiltered.Collectors.MetricsExclude = nil
+	for _, module := range catalogModules {
+		if !*module.flag(&modules) {
+			continue
+		}
+		entries, err := describeModule(&unfiltered, module)
+		if err != nil {
+			// Fetching for a module that publishes nothing costs requests, while
+			// disabling one that publishes withholds its series.
+			slog.Warn("Metric filter not applied to module",
+				"collector", module.collector, "module", module.name, "error", err)
+			continue
+		}
+		if !slices.ContainsFunc(entries, func(entry CatalogEntry) bool {
+			return modules.MetricAllowed(entry.Name)
+		}) {
+			*module.flag(&modules) = false
+			slog.Info("Disabled module, the metric filter withholds all of its families",
+				"collector", module.collector, "module", module.name)
+		}
+	}
+	return modules
+}
+
+// RequiredDataTypes returns the data types the families the metric include and exclude
+// lists publish are read from, in fetch order, so a data type only withheld families
+// read is not fetched even for a module keeping another family. Without a filter it is
+// every data type the enabled modules read.
+func RequiredDataTypes(cfg *config.Config) []string {
+	required := wnc.DataTypes(cfg.Collectors)
+	if !cfg.Collectors.FiltersMetrics() {
+		return required
+	}
+
+	read := make(map[string]bool, len(required))
+	for _, module := range catalogModules {
+		if !*module.flag(&cfg.Collectors) {
+			continue
+		}
+		entries, err := describeModule(cfg, module)
+		if err != nil {
+			// As for ActiveModules, fetching what nothing publishes costs requests,
+			// while leaving out what a family reads withholds its series.
+			slog.Warn("Metric filter not applied to the data types of module",
+				"collector", module.collector, "module", module.name, "error", err)
+			return required
+		}
+		for _, entry := range entries {
+			for _, dataType := range entry.DataTypes {
+				read[dataType] = true
+			}
+		}
+	}
+	return slices.DeleteFunc(required, func(dataType string) bool { return !read[dataType] })
+}
+
+// describingRegisterer is a prometheus.Registerer that keeps the families declared by
+// what it is given instead of registering it.
+type describingRegisterer struct {
+	families []*familySet
+	err      error
+}
+
+// Register implements prometheus.Registerer.
+func (r *describingRegisterer) Register(c prometheus.Collector) error {
+	families := declaredFamilies(c)
+	if families == nil {
+		err := fmt.Errorf("%w: %T", errUndeclaredCollector, c)
+		r.err = errors.Join(r.err, err)
+		return err
+	}
+	r.families = append(r.families, families)
+	return nil
+}
+
+// MustRegister implements prometheus.Registerer. A collector declaring no families is
+// reported by entries rather than by a panic.
+func (r *describingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		_ = r.Register(c)
+	}
+}
+
+// Unregister implements prometheus.Registerer.
+func (r *describingRegisterer) Unregister(prometheus.Collector) bool {
+	return false
+}
+
+// entries returns a catalog entry per family declared so far, with the constant label
+// names ahead of its own.
+func (r *describingRegisterer) entries(collector, module string, constLabels []string) ([]CatalogEntry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var entries []CatalogEntry
+	for _, families := range r.families {
+		for _, f := range families.families {
+			// Every list is its own and never nil, so merging one entry does not reach
+			// into another and the JSON output carries [] rather than null.
+			entry := CatalogEntry{
+				Name:      f.name,
+				Type:      f.kind,
+				Help:      f.help,
+				Labels:    append(slices.Clone(constLabels), f.labels...),
+				Collector: collector,
+				Modules:   []string{},
+				DataTypes: append([]string{}, f.dataTypes...),
+			}
+			if entry.Labels == nil {
+				entry.Labels = []string{}
+			}
+			if module != "" {
+				entry.Modules = append(entry.Modules, module)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// TestCatalog_MatchesGatheredFamilies binds the catalog to what a scrape publishes:
+// every family the full fixture gathers is listed with the type, help and labels it
+// was gathered with, so a declaration that disagrees with its descriptor fails here.
+func TestCatalog_MatchesGatheredFamilies(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	EnableAllModules(&cfg.Collectors)
+	entries, err := Catalog(cfg)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+	byName := make(map[string]CatalogEntry, len(entries))
+	for _, entry := range entries {
+		if _, listed := byName[entry.Name]; listed {
+			t.Errorf("%s is listed twice", entry.Name)
+		}
+		byName[entry.Name] = entry
+	}
+
+	families, err := fixtureRegistry(t, "").Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	types := map[dto.MetricType]string{
+		dto.MetricType_COUNTER:   metricTypeCounter,
+		dto.MetricType_GAUGE:     metricTypeGauge,
+		dto.MetricType_HISTOGRAM: metricTypeHistogram,
+	}
+	for _, family := range families {
+		entry, listed := byName[family.GetName()]
+		if !listed {
+			t.Errorf("%s is gathered but not in the catalog", family.GetName())
+			continue
+		}
+		if want := types[family.GetType()]; entry.Type != want {
+			t.Errorf("%s type = %s, want %s", entry.Name, entry.Type, want)
+		}
+		if entry.Help != family.GetHelp() {
+			t.Errorf("%s help = %q, want %q", entry.Name, entry.Help, family.GetHelp())
+		}
+
+		var labels []string
+		for _, pair := range family.GetMetric()[0].GetLabel() {
+			labels = append(labels, pair.GetName())
+		}
+		if got := slices.Sorted(slices.Values(entry.Labels)); !slices.Equal(got, labels) {
+			t.Errorf("%s labels = %v, want %v", entry.Name, got, labels)
+		}
+	}
+}
+
+// TestCatalog_AttributesModules lists only the enabled modules, each family under the
+// modules that register it with the data types they read, and the refresh health
+// series once any module is enabled.
+func TestCatalog_AttributesModules(t *testing.T) {
+	t.Parallel()
+
+	entries, err := Catalog(&config.Config{})
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "wnc_build_info" {
+		t.Errorf("Catalog() with no module = %v, want only wnc_build_info", entries)
+	}
+
+	cfg := &config.Config{Collectors: config.Collectors{
+		AP: config.APCollectorModules{Join: true, Departed: true},
+	}}
+	entries, err = Catalog(cfg)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	byName := make(map[string]CatalogEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+	joined := byName["wnc_ap_joined"]
+	if joined.Collector != "ap" || !slices.Equal(joined.Modules, []string{"join", "departed"}) {
+		t.Errorf("wnc_ap_joined = %s %v, want ap [join departed]", joined.Collector, joined.Modules)
+	}
+	// The join module reads the statistics list alone; the departed module adds the
+	// inventory it records departures from.
+	if !slices.Equal(joined.DataTypes, []string{"ap_join_stats", "ap_capwap_data"}) {
+		t.Errorf("wnc_ap_joined data types = %v, want [ap_join_stats ap_capwap_data]", joined.DataTypes)
+	}
+	if _, listed := byName["wnc_up"]; !listed {
+		t.Error("wnc_up is not listed with a module enabled")
+	}
+	if _, listed := byName["wnc_ap_oper_state"]; listed {
+		t.Error("wnc_ap_oper_state is listed with the general module disabled")
+	}
+}
+
+// TestCatalog_DeclaresEveryDescriptor holds each collector to its declaration: every
+// descriptor it describes is one it declared, and every family it declared is
+// described, so the catalog, the metric filter and the info cache miss none.
+func TestCatalog_DeclaresEveryDescriptor(t *testing.T) {
+	t.Parallel()
+
+	for _, collector := range fixtureCollectors(t, fullFixtureSnapshot()) {
+		families := declaredFamilies(collector)
+		if families == nil {
+			t.Errorf("%T declares no families", collector)
+			continue
+		}
+
+		described := make(map[string]bool)
+		for desc := range describe(collector) {
+			f, ok := families.lookup(desc)
+			if !ok {
+				t.Errorf("%T describes an undeclared descriptor %s", collector, desc)
+				continue
+			}
+			described[f.name] = true
+		}
+		for _, f := range families.families {
+			if !described[f.name] {
+				t.Errorf("%s is declared by %T but not described", f.name, collector)
+			}
+		}
+	}
+}
+
+// TestCatalog_DeclaresTheDataTypesThatWithholdAFamily holds the declared data types to
+// what a scrape does: a family a failed data type withholds lists that data type, so
+// the catalog names every source an operator should check for a missing family.
+func TestCatalog_DeclaresTheDataTypesThatWithholdAFamily(t *testing.T) {
+	t.Parallel()
+
+	declared := make(map[string][]string)
+	for _, collector := range fixtureCollectors(t, fullFixtureSnapshot()) {
+		for _, f := range declaredFamilies(collector).families {
+			declared[f.name] = f.dataTypes
+		}
+	}
+
+	baseline := gatherAllCollectors(t, "")
+	for _, dataType := range allDataTypes {
+		t.Run(dataType, func(t *testing.T) {
+			t.Parallel()
+
+			present := gatherAllCollectors(t, dataType)
+			for name, published := range baseline {
+				if published && !present[name] && !slices.Contains(declared[name], dataType) {
+					t.Errorf("%s is withheld when %s fails, but declares %v", name, dataType, declared[name])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCatalog(t *testing.T) {
+	t.Parallel()
+
+	entries := []CatalogEntry{
+		{Name: "wnc_build_info", Type: "gauge", Help: "Build", Labels: []string{"version"},
+			Collector: "exporter", Modules: []string{}, DataTypes: []string{}},
+		{Name: "wnc_ap_cpu_usage_percent", Type: "gauge", Help: "CPU | usage", Labels: []string{"mac"},
+			Collector: "ap", Modules: []string{"general"}, DataTypes: []string{"ap_oper_data"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCatalog(&buf, entries, CatalogFormatMarkdown); err != nil {
+		t.Fatalf("WriteCatalog(markdown) error = %v", err)
+	}
+	for _, want := range []string{
+		"## exporter\n",
+		"| - | `wnc_build_info` | gauge | `version` | - | Build |\n",
+		"## ap\n",
+		"| general | `wnc_ap_cpu_usage_percent` | gauge | `mac` | `ap_oper_data` | CPU \\| usage |\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteCatalog(markdown) lacks %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := WriteCatalog(&buf, entries, CatalogFormatJSON); err != nil {
+		t.Fatalf("WriteCatalog(json) error = %v", err)
+	}
+	var decoded []CatalogEntry
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteCatalog(json) output does not decode: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1].Modules[0] != "general" {
+		t.Errorf("WriteCatalog(json) = %+v", decoded)
+	}
+
+	if err := WriteCatalog(&buf, entries, "yaml"); !errors.Is(err, errUnknownCatalogFormat) {
+		t.Errorf("WriteCatalog(yaml) error = %v, want %v", err, errUnknownCatalogFormat)
+	}
+}
@@ -20,15 +20,22 @@ type ClientMetrics struct {
 	Radio      bool
 	Traffic    bool
 	Errors     bool
+	Devices    bool
 	Info       bool
+	Onboarding bool
+	Sessions   bool
 	InfoLabels []string
 }
 
 // ClientCollector implements prometheus.Collector for Client metrics.
 type ClientCollector struct {
 	metrics        ClientMetrics
+	families       *familySet
 	infoDesc       *prometheus.Desc
 	infoLabelNames []string
+	devices        *clientDeviceDescs
+	onboarding     *clientOnboardingDescs
+	sessions       *clientSessionDescs
 	src            wnc.ClientSource
 
 	stateDesc                  *prometheus.Desc
@@ -61,162 +68,176 @@ type ClientCollector struct {
 
 // NewClientCollector creates a new ClientCollector for retrieving metrics from WNC.
 func NewClientCollector(src wnc.ClientSource, metrics ClientMetrics) *ClientCollector {
+	families := &familySet{}
 	collector := &ClientCollector{
-		src:     src,
-		metrics: metrics,
+		src:      src,
+		metrics:  metrics,
+		families: families,
 	}
 
 	labels := []string{labelMAC}
 
+	if metrics.Devices {
+		collector.devices = newClientDeviceDescs(families)
+	}
+
+	if metrics.Onboarding {
+		collector.onboarding = newClientOnboardingDescs(families)
+	}
+
+	if metrics.Sessions {
+		collector.sessions = newClientSessionDescs(families)
+	}
+
 	if metrics.General {
-		collector.stateDesc = prometheus.NewDesc(
+		collector.stateDesc = families.gauge(
 			"wnc_client_state",
 			"Client connection state, as the value the controller's own enumeration assigns "+
 				"its spelling. 11 is client-status-run, the state a client holds while it "+
 				"passes traffic. The numbering follows the onboarding sequence, so a value "+
 				"below 11 has not reached it and a value above 11 is a deletion",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData,
 		)
-		collector.roamTypeDesc = prometheus.NewDesc(
+		collector.roamTypeDesc = families.gauge(
 			"wnc_client_roam_type",
 			"How the client reached the association it currently holds, as the value the "+
 				"controller's own enumeration assigns its spelling (0=dot11-roam-type-none, "+
 				"1=dot11-roam-type-slow-11i, 2=dot11-roam-type-fast-okc, 3=dot11-roam-type-cckm, "+
 				"4=dot11-roam-type-fast-11r). It is a property of that association rather than "+
 				"a count, so it does not move until the client associates again",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientMMIFHistory,
 		)
-		collector.associationUptimeDesc = prometheus.NewDesc(
+		collector.associationUptimeDesc = families.gauge(
 			"wnc_client_uptime_seconds",
 			"Client association uptime in seconds. Withheld rather than measured from a "+
 				"zero timestamp when the controller reports no association time, so a "+
 				"session-age check has no reading instead of a false one",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
 		)
-		collector.stateTransitionSecondsDesc = prometheus.NewDesc(
+		collector.stateTransitionSecondsDesc = families.gauge(
 			"wnc_client_state_transition_seconds",
 			"Client state transition latency in seconds",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientMMIFHistory,
 		)
-		collector.powerSaveStateDesc = prometheus.NewDesc(
+		collector.powerSaveStateDesc = families.gauge(
 			"wnc_client_power_save_state",
 			"Power save state as reported",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
 	}
 
 	if metrics.Radio {
-		collector.protocolDesc = prometheus.NewDesc(
+		collector.protocolDesc = families.gauge(
 			"wnc_client_protocol",
 			"Client wireless protocol (0=unknown, 1=802.11a, 2=802.11b, 3=802.11g, "+
 				"4=802.11n, 5=802.11ac, 6=802.11ax, 7=802.11be)",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
 		)
-		collector.mcsIndexDesc = prometheus.NewDesc(
+		collector.mcsIndexDesc = families.gauge(
 			"wnc_client_mcs_index",
 			"Client MCS index, -1 when the rate carries none",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.spatialStreamsDesc = prometheus.NewDesc(
+		collector.spatialStreamsDesc = families.gauge(
 			"wnc_client_spatial_streams",
 			"Number of spatial streams",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.speedDesc = prometheus.NewDesc(
+		collector.speedDesc = families.gauge(
 			"wnc_client_speed_mbps",
 			"Connection speed in Mbps",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.rssiDesc = prometheus.NewDesc(
+		collector.rssiDesc = families.gauge(
 			"wnc_client_rssi_dbm",
 			"Received signal strength in dBm",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.snrDesc = prometheus.NewDesc(
+		collector.snrDesc = families.gauge(
 			"wnc_client_snr_decibels",
 			"Signal-to-noise ratio in dB",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
 	}
 
 	if metrics.Traffic {
-		collector.bytesRxDesc = prometheus.NewDesc(
+		collector.bytesRxDesc = families.counter(
 			"wnc_client_rx_bytes_total",
 			"Total bytes received",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.bytesTxDesc = prometheus.NewDesc(
+		collector.bytesTxDesc = families.counter(
 			"wnc_client_tx_bytes_total",
 			"Total bytes transmitted",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.packetsRxDesc = prometheus.NewDesc(
+		collector.packetsRxDesc = families.counter(
 			"wnc_client_rx_packets_total",
 			"Total packets received",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.packetsTxDesc = prometheus.NewDesc(
+		collector.packetsTxDesc = families.counter(
 			"wnc_client_tx_packets_total",
 			"Total packets transmitted",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
 	}
 
 	if metrics.Errors {
-		collector.policyErrorsDesc = prometheus.NewDesc(
+		collector.policyErrorsDesc = families.counter(
 			"wnc_client_policy_errors_total",
 			"Policy errors",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.duplicateReceivedDesc = prometheus.NewDesc(
+		collector.duplicateReceivedDesc = families.counter(
 			"wnc_client_duplicate_received_total",
 			"Duplicate packets received",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.decryptionFailedDesc = prometheus.NewDesc(
+		collector.decryptionFailedDesc = families.counter(
 			"wnc_client_decryption_failed_total",
 			"Decryption failed packets",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.micMismatchDesc = prometheus.NewDesc(
+		collector.micMismatchDesc = families.counter(
 			"wnc_client_mic_mismatch_total",
 			"MIC mismatch errors",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.micMissingDesc = prometheus.NewDesc(
+		collector.micMissingDesc = families.counter(
 			"wnc_client_mic_missing_total",
 			"MIC missing errors",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.excessiveRetriesDesc = prometheus.NewDesc(
+		collector.excessiveRetriesDesc = families.counter(
 			"wnc_client_excessive_retries_total",
 			"Excessive retries",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.rxGroupCounterDesc = prometheus.NewDesc(
+		collector.rxGroupCounterDesc = families.counter(
 			"wnc_client_rx_group_total",
 			"RX group counter (rx-group-counter)",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.txTotalDropsDesc = prometheus.NewDesc(
+		collector.txTotalDropsDesc = families.counter(
 			"wnc_client_tx_drops_total",
 			"Total TX drops",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.dataRetriesDesc = prometheus.NewDesc(
+		collector.dataRetriesDesc = families.counter(
 			"wnc_client_data_retries_total",
 			"Data retries",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.rtsRetriesDesc = prometheus.NewDesc(
+		collector.rtsRetriesDesc = families.counter(
 			"wnc_client_rts_retries_total",
 			"RTS retries",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
-		collector.txRetriesDesc = prometheus.NewDesc(
+		collector.txRetriesDesc = families.counter(
 			"wnc_client_tx_retries_total",
 			"TX retries",
-			labels, nil,
+			labels, wnc.DataClientCommonOperData, wnc.DataClientTrafficStats,
 		)
 	}
 
@@ -226,10 +247,10 @@ func NewClientCollector(src wnc.ClientSource, metrics ClientMetrics) *ClientColl
 			metrics.InfoLabels,
 			[]string{labelAP, labelBand, labelWLAN, labelName, labelUsername, labelIPv4, labelIPv6},
 		)
-		collector.infoDesc = prometheus.NewDesc(
+		collector.infoDesc = families.gauge(
 			"wnc_client_info",
 			"Client information labels for joining with other metrics",
-			infoLabels, nil,
+			infoLabels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData, wnc.DataClientDCInfo, wnc.DataClientSISFDBMac,
 		)
 		collector.infoLabelNames = infoLabels
 	}
@@ -238,7 +259,16 @@ func NewClientCollector(src wnc.ClientSource, metrics ClientMetrics) *ClientColl
 }
 
 func (c *ClientCollector) isAnyMetricFlagEnabled() bool {
-	return IsEnabled(c.metrics.General, c.metrics.Radio, c.metrics.Traffic, c.metrics.Errors, c.metrics.Info)
+	return IsEnabled(
+		c.metrics.General, c.metrics.Radio, c.metrics.Traffic, c.metrics.Errors,
+		c.metrics.Devices, c.metrics.Info, c.metrics.Onboarding,
+		c.metrics.Sessions,
+	)
+}
+
+// declaredFamilies returns the families of the enabled modules.
+func (c *ClientCollector) declaredFamilies() *familySet {
+	return c.families
 }
 
 // Describe sends the descriptors of all metrics to the provided channel.
@@ -280,6 +310,15 @@ func (c *ClientCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- c.rtsRetriesDesc
 		ch <- c.txRetriesDesc
 	}
+	if c.metrics.Devices {
+		c.devices.describe(ch)
+	}
+	if c.metrics.Onboarding {
+		c.onboarding.describe(ch)
+	}
+	if c.metrics.Sessions {
+		c.sessions.describe(ch)
+	}
 }
 
 // Collect implements the prometheus.Collector interface.
@@ -290,6 +329,20 @@ func (c *ClientCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	// The onboarding and sessions modules compare each snapshot once. The refresh time
+	// is read before the lists, so a refresh landing between the reads replays the newer
+	// lists under the older time, and the next scrape compares those lists with
+	// themselves.
+	var refreshedAt time.Time
+	if IsEnabled(c.metrics.Onboarding, c.metrics.Sessions) {
+		at, err := c.src.GetRefreshedAt(ctx)
+		if err != nil {
+			slog.Debug("Failed to retrieve snapshot refresh time", "error", err)
+			return
+		}
+		refreshedAt = at
+	}
+
 	clientData, err := c.src.GetClientData(ctx)
 	if err != nil {
 		slog.Debug("Failed to retrieve client data", "error", err)
@@ -297,21 +350,30 @@ func (c *ClientCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	var deviceMap map[string]client.DcInfo
-	if IsEnabled(c.metrics.Info) {
+	if IsEnabled(c.metrics.Devices, c.metrics.Info) {
 		deviceData, err := c.src.GetDeviceData(ctx)
 		if err != nil {
 			slog.Debug("Failed to retrieve device data", "error", err)
 		}
 		deviceMap = buildDeviceMap(deviceData)
+
+		// The info label falls back to an empty name, while a count taken without the
+		// classification would report every client as unknown.
+		if c.metrics.Devices && err == nil {
+			c.devices.collect(ch, clientData, deviceMap)
+		}
 	}
 
 	var dot11Map map[string]client.Dot11OperData
-	if IsEnabled(c.metrics.General, c.metrics.Radio, c.metrics.Info) {
+	var dot11Read bool
+	if IsEnabled(c.metrics.General, c.metrics.Radio, c.metrics.Info, c.metrics.Onboarding,
+		c.metrics.Sessions) {
 		dot11Data, err := c.src.GetDot11Data(ctx)
 		if err != nil {
 			slog.Debug("Failed to retrieve dot11 data", "error", err)
 		}
 		dot11Map = buildDot11Map(dot11Data)
+		dot11Read = err == nil
 	}
 
 	var sisfMap map[string]client.SisfDBMac
@@ -333,12 +395,24 @@ func (c *ClientCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	var mobilityMap map[string]client.MmIfClientHistory
-	if IsEnabled(c.metrics.General) {
+	var mobilityRead bool
+	if IsEnabled(c.metrics.General, c.metrics.Onboarding) {
 		mobilityData, err := c.src.GetMobilityHistory(ctx)
 		if err != nil {
 			slog.Debug("Failed to retrieve mobility history data", "error", err)
 		}
 		mobilityMap = buildMobilityMap(mobilityData)
+		mobilityRead = err == nil
+	}
+
+	// Both histograms are keyed by WLAN and the snapshot counts clients held short of
+	// the run state, so the module reads the whole list rather than one client at a time.
+	if c.metrics.Onboarding {
+		c.onboarding.collect(ch, refreshedAt, clientData, dot11Map, dot11Read, mobilityMap, mobilityRead)
+	}
+	// The sessions module compares the whole list with the one the last snapshot held.
+	if c.metrics.Sessions {
+		c.sessions.collect(ch, refreshedAt, clientData, dot11Map)
 	}
 
 	for _, data := range clientData {
@@ -0,0 +1,100 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the device classification module of the Client collector.
+package collector
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// deviceClassUnknown stands in for a classification leaf the controller left empty, and
+// for every leaf of a client it has not classified at all.
+const deviceClassUnknown = "unknown"
+
+// clientDeviceDescs holds the descriptors of the devices module. A nil value means the
+// module is disabled, which is what keeps every series of it out of a default scrape.
+type clientDeviceDescs struct {
+	clients *prometheus.Desc
+}
+
+// newClientDeviceDescs builds the descriptors of the devices module.
+//
+// The series is keyed by the classification rather than by the client, so its
+// cardinality follows the mix of devices on the air rather than their number. The WLAN
+// is keyed by its ID, the key of every wnc_wlan_* series, so no per-client read is
+// needed to name it.
+func newClientDeviceDescs(families *familySet) *clientDeviceDescs {
+	return &clientDeviceDescs{
+		clients: families.gauge(
+			"wnc_client_devices",
+			"Number of clients in the run state on this WLAN and band that the controller's "+
+				"device classification assigns this device type, OS and vendor. A leaf the "+
+				"controller has not classified reads unknown. Withheld entirely when the "+
+				"classification cannot be read, rather than counting every client as unknown",
+			[]string{labelID, labelBand, labelDeviceType, labelOS, labelVendor}, wnc.DataClientCommonOperData, wnc.DataClientDCInfo,
+		),
+	}
+}
+
+// describe sends every descriptor of the devices module.
+func (d *clientDeviceDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.clients
+}
+
+// clientDeviceKey identifies one series of the devices module.
+type clientDeviceKey struct {
+	wlanID     int
+	band       string
+	deviceType string
+	os         string
+	vendor     string
+}
+
+// collect counts the clients in the run state by WLAN, band and classification. The
+// run state is the filter wnc_wlan_clients counts under, so summing this family over its
+// classification labels reads the same number.
+func (d *clientDeviceDescs) collect(
+	ch chan<- prometheus.Metric,
+	clientData []client.CommonOperData,
+	deviceMap map[string]client.DcInfo,
+) {
+	counts := make(map[clientDeviceKey]int)
+
+	for i := range clientData {
+		data := &clientData[i]
+		if data.CoState != ClientStatusRun {
+			continue
+		}
+
+		device := deviceMap[data.ClientMAC]
+		counts[clientDeviceKey{
+			wlanID:     data.WlanID,
+			band:       ClientBand(*data),
+			deviceType: deviceClassOrUnknown(device.DeviceType),
+			os:         deviceClassOrUnknown(device.DeviceOs),
+			vendor:     deviceClassOrUnknown(device.DeviceVendor),
+		}]++
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			d.clients, prometheus.GaugeValue, float64(count),
+			strconv.Itoa(key.wlanID), key.band, key.deviceType, key.os, key.vendor,
+		)
+	}
+}
+
+// deviceClassOrUnknown returns the classification leaf, or deviceClassUnknown when the
+// controller left it empty. An empty label value would read as an absent label, which
+// no query can match by equality.
+func deviceClassOrUnknown(value string) string {
+	if value == "" {
+		return deviceClassUnknown
+	}
+
+	return value
+}
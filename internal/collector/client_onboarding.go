@@ -0,0 +1,355 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the onboarding latency module of the Client collector.
+package collector
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// onboardingLatencyBuckets bound the association-to-run latency. A PSK join completes in
+// well under a second and an 802.1X one in a few, while a client held past a minute has
+// met a timeout rather than a slow server.
+var onboardingLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// onboardingPendingBuckets bound how long a client has been held short of the run
+// state. A phase completes in milliseconds, so a client still held at the first bucket
+// is stalled rather than slow.
+var onboardingPendingBuckets = []float64{1, 5, 10, 30, 60, 300, 900, 3600}
+
+// clientOnboardingPhases assigns each co-state short of the run state the phase of
+// wnc_wlan_onboarding_clients it belongs to, so the two families agree on the label
+// values. The grouping is this exporter's own: the controller numbers the states in
+// onboarding order but names no phase for them.
+var clientOnboardingPhases = map[string]string{
+	"client-status-associating":                "l2auth",
+	"client-status-associated":                 "l2auth",
+	"client-status-authenticating":             "l2auth",
+	"client-status-authenticated":              "l2auth",
+	"client-status-mobility-discovery":         "mobility",
+	"client-status-mobility-complete":          "mobility",
+	"client-status-ip-learning":                "iplearn",
+	"client-status-ip-learn-complete":          "iplearn",
+	"client-status-static-ip-anchor-discovery": "iplearn",
+	"client-status-webauth-required":           "webauth_pending",
+}
+
+// onboardingPhaseRun is the phase label value of the association-to-run latency the
+// controller records, which spans every phase before it.
+const onboardingPhaseRun = "run"
+
+// onboardingClient is what the onboarding module remembers of a client between two
+// snapshots to time the phases it passes through.
+type onboardingClient struct {
+	wlanID int
+	// associatedAt identifies the association, so a client that reassociates starts
+	// its phases again rather than carrying the old ones over.
+	associatedAt time.Time
+	phase        string
+	// enteredAt is when the client entered its phase, zero when no snapshot saw it
+	// enter.
+	enteredAt time.Time
+}
+
+// clientOnboardingDescs holds the state of the onboarding module. A nil value means the
+// module is disabled, which is what keeps every series of it out of a default scrape.
+//
+// Unlike most modules it keeps state across snapshots: the latency histogram counts
+// associations and phases, and an association stays in the client list for as long as
+// it lasts, so each one is observed once, on the first snapshot that sees it past the
+// phase.
+type clientOnboardingDescs struct {
+	latency *prometheus.HistogramVec
+	pending *prometheus.Desc
+
+	mu    sync.Mutex
+	clock snapshotClock
+	// observed maps each client MAC to the association time of the association whose
+	// run latency was last observed for it.
+	observed map[string]time.Time
+	// clients maps each client MAC in the last snapshot to the phase it was held in.
+	clients map[string]onboardingClient
+	// wlans holds the WLANs the latency children were created for.
+	wlans map[int]bool
+	// primed is set once a snapshot has recorded the run latencies it found without
+	// observing them.
+	primed bool
+}
+
+// newClientOnboardingDescs builds the descriptors of the onboarding module.
+//
+// Both histograms are keyed by the WLAN identifier, the key of every wnc_wlan_* series,
+// rather than by the client, so their cardinality follows the WLANs and phases rather
+// than the clients.
+func newClientOnboardingDescs(families *familySet) *clientOnboardingDescs {
+	return &clientOnboardingDescs{
+		latency: families.histogramVec(
+			"wnc_client_onboarding_latency_seconds",
+			"Onboarding latency on this WLAN. phase=\"run\" is the time from "+
+				"association to the run state the controller recorded, counted once per "+
+				"association from the second refresh after the exporter started. Each "+
+				"other phase is the time a client was held in it, measured between the "+
+				"refreshes that saw it enter and leave, so only a client held across a "+
+				"refresh is observed there",
+			onboardingLatencyBuckets,
+			[]string{labelID, labelPhase},
+			wnc.DataClientCommonOperData, wnc.DataClientDot11OperData, wnc.DataClientMMIFHistory,
+		),
+		pending: families.histogram(
+			"wnc_client_onboarding_pending_seconds",
+			"Time since association of the clients on this WLAN held in this onboarding "+
+				"phase now. A snapshot rebuilt on every scrape rather than a cumulative "+
+				"histogram, so read it with histogram_quantile directly and not over rate()",
+			[]string{labelID, labelPhase}, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
+		),
+		observed: make(map[string]time.Time),
+		clients:  make(map[string]onboardingClient),
+		wlans:    make(map[int]bool),
+	}
+}
+
+// describe sends every descriptor of the onboarding module.
+func (d *clientOnboardingDescs) describe(ch chan<- *prometheus.Desc) {
+	d.latency.Describe(ch)
+	ch <- d.pending
+}
+
+// collect compares a snapshot not compared yet with the last one, observing the
+// latencies it shows, and publishes both histograms.
+//
+// The run latency is left untouched while the mobility history cannot be read, since a
+// snapshot without it would forget every association and count them all again once it
+// returns. The phases and the pending snapshot are left untouched while the dot11 list
+// cannot be read, for the same reason, and because every client would then be missing
+// from the snapshot rather than not held.
+func (d *clientOnboardingDescs) collect(
+	ch chan<- prometheus.Metric,
+	refreshedAt time.Time,
+	clients []client.CommonOperData,
+	dot11Map map[string]client.Dot11OperData,
+	dot11Read bool,
+	mobilityMap map[string]client.MmIfClientHistory,
+	mobilityRead bool,
+) {
+	d.mu.Lock()
+	if d.clock.advance(refreshedAt) {
+		d.trackWLANs(clients, mobilityRead, dot11Read)
+		if mobilityRead {
+			d.observeLatency(clients, mobilityMap)
+		}
+		if dot11Read {
+			d.observePhases(refreshedAt, clients, dot11Map)
+		}
+	}
+	d.mu.Unlock()
+
+	d.latency.Collect(ch)
+
+	if dot11Read {
+		d.collectPending(ch, clients, dot11Map)
+	}
+}
+
+// trackWLANs creates the latency children of every WLAN with a client in the snapshot
+// and deletes those of a WLAN the whole snapshot no longer names, so a WLAN's histogram
+// starts at zero rather than appearing with its first observation, and a WLAN that was
+// removed does not keep its series for the life of the process. The run phase reads the
+// mobility history and the others the dot11 list, so each is created only once the list
+// it reads was.
+func (d *clientOnboardingDescs) trackWLANs(clients []client.CommonOperData, mobilityRead, dot11Read bool) {
+	present := make(map[int]bool, len(d.wlans))
+	for i := range clients {
+		present[clients[i].WlanID] = true
+	}
+
+	for wlanID := range present {
+		d.wlans[wlanID] = true
+		id := strconv.Itoa(wlanID)
+		if mobilityRead {
+			d.latency.WithLabelValues(id, onboardingPhaseRun)
+		}
+		if dot11Read {
+			for _, phase := range onboardingPhases {
+				d.latency.WithLabelValues(id, phase.name)
+			}
+		}
+	}
+
+	for wlanID := range d.wlans {
+		if !present[wlanID] {
+			delete(d.wlans, wlanID)
+			d.latency.DeletePartialMatch(prometheus.Labels{labelID: strconv.Itoa(wlanID)})
+		}
+	}
+}
+
+// observeLatency records the run latency of every association not observed yet.
+//
+// An association is identified by the client MAC and the association time of the
+// mobility entry the latency comes from, so a client that reassociates is observed
+// again while one that stays is not. The first snapshot only records what it finds,
+// because those associations completed before the exporter was watching and counting
+// them would add a burst of old latencies on every restart. A client that leaves the
+// list is forgotten, so the map follows the client list rather than growing with it.
+func (d *clientOnboardingDescs) observeLatency(
+	clients []client.CommonOperData,
+	mobilityMap map[string]client.MmIfClientHistory,
+) {
+	present := make(map[string]bool, len(clients))
+
+	for i := range clients {
+		data := &clients[i]
+		present[data.ClientMAC] = true
+		if data.CoState != ClientStatusRun {
+			continue
+		}
+
+		mobility := mobilityMap[data.ClientMAC]
+		latency, ok := determineLastRunLatency(mobility)
+		if !ok {
+			continue
+		}
+		associatedAt := mobility.MobilityHistory.Entry[0].MsAssocTime
+		if last, seen := d.observed[data.ClientMAC]; seen && last.Equal(associatedAt) {
+			continue
+		}
+		d.observed[data.ClientMAC] = associatedAt
+
+		if d.primed {
+			d.latency.WithLabelValues(strconv.Itoa(data.WlanID), onboardingPhaseRun).Observe(latency)
+		}
+	}
+
+	for mac := range d.observed {
+		if !present[mac] {
+			delete(d.observed, mac)
+		}
+	}
+
+	d.primed = true
+}
+
+// observePhases observes the time each client was held in a phase it left since the
+// last snapshot.
+//
+// A phase is timed from the snapshot that first saw the client in it to the one that
+// first saw it past it, so a reading is accurate to a refresh interval and a phase the
+// client entered and left between two refreshes is not observed at all. The first
+// phase is the exception: a client seen in it entered it on associating, so it is
+// timed from the association time the controller recorded, which is also what lets a
+// snapshot taken before the exporter started time it. A client that leaves the list
+// or reassociates before leaving its phase failed rather than completed it, so it is
+// not observed.
+func (d *clientOnboardingDescs) observePhases(
+	refreshedAt time.Time,
+	clients []client.CommonOperData,
+	dot11Map map[string]client.Dot11OperData,
+) {
+	current := make(map[string]onboardingClient, len(clients))
+
+	for i := range clients {
+		data := &clients[i]
+		dot11, ok := dot11Map[data.ClientMAC]
+		if !ok || dot11.MsAssocTime.Year() <= epochYear {
+			continue
+		}
+
+		phase := clientOnboardingPhases[data.CoState]
+		if data.CoState == ClientStatusRun {
+			phase = onboardingPhaseRun
+		}
+
+		state := onboardingClient{wlanID: data.WlanID, associatedAt: dot11.MsAssocTime, phase: phase}
+		previous, seen := d.clients[data.ClientMAC]
+
+		switch {
+		case seen && previous.associatedAt.Equal(state.associatedAt) && previous.phase == phase:
+			state.enteredAt = previous.enteredAt
+		case seen && previous.associatedAt.Equal(state.associatedAt):
+			if phase != "" && previous.phase != onboardingPhaseRun && previous.phase != "" &&
+				!previous.enteredAt.IsZero() && refreshedAt.After(previous.enteredAt) {
+				d.latency.WithLabelValues(strconv.Itoa(previous.wlanID), previous.phase).
+					Observe(refreshedAt.Sub(previous.enteredAt).Seconds())
+			}
+			state.enteredAt = refreshedAt
+		case phase == onboardingPhases[0].name:
+			state.enteredAt = state.associatedAt
+		}
+
+		current[data.ClientMAC] = state
+	}
+
+	d.clients = current
+}
+
+// collectPending publishes, per WLAN and phase, how long the clients held short of the
+// run state have been associated. Every WLAN with a client in the list carries all four
+// phases, so a phase nobody is held in reads an empty histogram rather than none.
+func (d *clientOnboardingDescs) collectPending(
+	ch chan<- prometheus.Metric,
+	clients []client.CommonOperData,
+	dot11Map map[string]client.Dot11OperData,
+) {
+	type key struct {
+		wlanID int
+		phase  string
+	}
+
+	durations := make(map[key][]float64)
+	wlans := make(map[int]bool)
+
+	for i := range clients {
+		data := &clients[i]
+		wlans[data.WlanID] = true
+
+		phase, ok := clientOnboardingPhases[data.CoState]
+		if !ok {
+			continue
+		}
+		// A client with no association time has nothing to measure from, and the
+		// zero time would read as a client held since year 1.
+		dot11, ok := dot11Map[data.ClientMAC]
+		if !ok || dot11.MsAssocTime.Year() <= epochYear {
+			continue
+		}
+
+		k := key{data.WlanID, phase}
+		durations[k] = append(durations[k], time.Since(dot11.MsAssocTime).Seconds())
+	}
+
+	for wlanID := range wlans {
+		for _, phase := range onboardingPhases {
+			count, sum, buckets := bucketize(durations[key{wlanID, phase.name}], onboardingPendingBuckets)
+			ch <- prometheus.MustNewConstHistogram(
+				d.pending, count, sum, buckets, strconv.Itoa(wlanID), phase.name,
+			)
+		}
+	}
+}
+
+// bucketize folds observations into the cumulative bucket counts a const histogram
+// takes.
+func bucketize(values, bounds []float64) (uint64, float64, map[float64]uint64) {
+	buckets := make(map[float64]uint64, len(bounds))
+	for _, bound := range bounds {
+		buckets[bound] = 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+		for _, bound := range bounds {
+			if value <= bound {
+				buckets[bound]++
+			}
+		}
+	}
+
+	return uint64(len(values)), sum, buckets
+}
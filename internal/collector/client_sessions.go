@@ -0,0 +1,282 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the session tracking module of the Client collector.
+package collector
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// sessionDurationBuckets bound the length of a client session. A phone passing through
+// holds a few minutes, a laptop in a meeting an hour and a desk device a working day.
+var sessionDurationBuckets = []float64{60, 300, 900, 1800, 3600, 7200, 14400, 28800, 86400}
+
+// clientSession is what the sessions module remembers of a client in the run state
+// between two snapshots.
+type clientSession struct {
+	wlanID int
+	ap     string
+	band   string
+	// associatedAt is the association time the controller last reported, which a
+	// roam moves, and startedAt the one the session began with, which it does not.
+	// Both are zero while the dot11 data has not named them.
+	associatedAt time.Time
+	startedAt    time.Time
+	lastSeen     time.Time
+}
+
+// clientSessionDescs holds the state of the sessions module. A nil value means the
+// module is disabled, which is what keeps every series of it out of a default scrape.
+//
+// Like the onboarding module it keeps state across snapshots: the controller reports
+// the clients it holds now and nothing about the ones that left, so a start, an end or
+// a roam is only visible as the difference between two snapshots.
+type clientSessionDescs struct {
+	started  *prometheus.CounterVec
+	ended    *prometheus.CounterVec
+	roams    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	mu    sync.Mutex
+	clock snapshotClock
+	// sessions maps each client MAC in the run state in the last snapshot to its
+	// session.
+	sessions map[string]clientSession
+	// children maps the labels of each counter child to whether a session of the last
+	// snapshot was counted under them, and durations the same for the histogram.
+	children  map[[3]string]bool
+	durations map[[2]string]bool
+	// primed is set once a snapshot has recorded the clients it found without counting
+	// them.
+	primed bool
+}
+
+// newClientSessionDescs builds the descriptors of the sessions module.
+//
+// The counters are keyed by the WLAN identifier, the key of every wnc_wlan_* series,
+// the AP name and the band, so their cardinality follows the WLANs and APs rather than
+// the clients. The duration histogram drops the AP, since a session that roamed belongs
+// to no single AP.
+func newClientSessionDescs(families *familySet) *clientSessionDescs {
+	labels := []string{labelID, labelAP, labelBand}
+
+	return &clientSessionDescs{
+		started: families.counterVec(
+			"wnc_client_sessions_started_total",
+			"Clients that reached the run state on this WLAN, AP and band between two "+
+				"refreshes, counted from the second refresh after the exporter started",
+			labels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
+		),
+		ended: families.counterVec(
+			"wnc_client_sessions_ended_total",
+			"Clients that left the run state between two refreshes, by the WLAN, AP and "+
+				"band they were last seen on",
+			labels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
+		),
+		roams: families.counterVec(
+			"wnc_client_roams_observed_total",
+			"Clients seen on another AP of the same WLAN than at the previous refresh, "+
+				"by the AP and band they roamed to. Several roams between two refreshes count once",
+			labels, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
+		),
+		duration: families.histogramVec(
+			"wnc_client_session_duration_seconds",
+			"Length of the sessions that ended on this WLAN and band, from the "+
+				"association that began the session to the last refresh that saw it, so "+
+				"short by up to one refresh interval",
+			sessionDurationBuckets,
+			[]string{labelID, labelBand}, wnc.DataClientCommonOperData, wnc.DataClientDot11OperData,
+		),
+		sessions:  make(map[string]clientSession),
+		children:  make(map[[3]string]bool),
+		durations: make(map[[2]string]bool),
+	}
+}
+
+// describe sends every descriptor of the sessions module.
+func (d *clientSessionDescs) describe(ch chan<- *prometheus.Desc) {
+	d.started.Describe(ch)
+	d.ended.Describe(ch)
+	d.roams.Describe(ch)
+	d.duration.Describe(ch)
+}
+
+// collect compares the clients in the run state in a snapshot not compared yet with
+// the ones the last snapshot held, and publishes the four families.
+//
+// A client present now and not then started a session, one present then and not now
+// ended it, and one on another AP of the same WLAN roamed. A client seen on another
+// WLAN, or on the same AP with a new association time, left and came back between the
+// snapshots, so it ends one session and starts another. The first snapshot only records
+// what it finds, because those sessions began before the exporter was watching and
+// counting them would add a burst of starts on every restart.
+func (d *clientSessionDescs) collect(
+	ch chan<- prometheus.Metric,
+	refreshedAt time.Time,
+	clients []client.CommonOperData,
+	dot11Map map[string]client.Dot11OperData,
+) {
+	d.mu.Lock()
+	if d.clock.advance(refreshedAt) {
+		d.compareSnapshot(refreshedAt, clients, dot11Map)
+	}
+	d.mu.Unlock()
+
+	d.started.Collect(ch)
+	d.ended.Collect(ch)
+	d.roams.Collect(ch)
+	d.duration.Collect(ch)
+}
+
+// compareSnapshot counts what changed between the last snapshot and this one.
+func (d *clientSessionDescs) compareSnapshot(
+	refreshedAt time.Time,
+	clients []client.CommonOperData,
+	dot11Map map[string]client.Dot11OperData,
+) {
+	current := make(map[string]clientSession, len(clients))
+
+	for i := range clients {
+		data := &clients[i]
+		if data.CoState != ClientStatusRun || data.ClientMAC == "" {
+			continue
+		}
+
+		session := clientSession{
+			wlanID:   data.WlanID,
+			ap:       data.ApName,
+			band:     ClientBand(*data),
+			lastSeen: refreshedAt,
+		}
+		// A client with no association time begins no measurable session, and the
+		// zero time would read as a session held since year 1.
+		if dot11, ok := dot11Map[data.ClientMAC]; ok && dot11.MsAssocTime.Year() > epochYear {
+			session.associatedAt = dot11.MsAssocTime
+			session.startedAt = dot11.MsAssocTime
+		}
+
+		if previous, ok := d.sessions[data.ClientMAC]; ok {
+			session = d.compare(previous, session)
+		} else if d.primed {
+			d.start(session)
+		}
+
+		current[data.ClientMAC] = session
+	}
+
+	if d.primed {
+		for mac, previous := range d.sessions {
+			if _, ok := current[mac]; !ok {
+				d.end(previous)
+			}
+		}
+	}
+
+	d.prune(current)
+	d.sessions = current
+	d.primed = true
+}
+
+// prune creates the children the sessions of this snapshot are counted under, and
+// deletes those no session was counted under in this snapshot or the last.
+//
+// A child is created before anything is counted, so a WLAN, AP and band starts at zero
+// rather than appearing with its first event. The module sees an AP or a WLAN only
+// through the clients on it, so one with no client in the run state for a whole
+// snapshot is gone to it, and its children are deleted rather than kept for the life of
+// the process. The snapshot a client was last seen in keeps its children for one more,
+// so the end that snapshot counts is published before they go.
+func (d *clientSessionDescs) prune(current map[string]clientSession) {
+	children := make(map[[3]string]bool, len(d.children))
+	durations := make(map[[2]string]bool, len(d.durations))
+
+	for _, session := range current {
+		labels := sessionLabels(session)
+		d.started.WithLabelValues(labels...)
+		d.ended.WithLabelValues(labels...)
+		d.roams.WithLabelValues(labels...)
+		d.duration.WithLabelValues(labels[0], labels[2])
+		children[[3]string(labels)] = true
+		durations[[2]string{labels[0], labels[2]}] = true
+	}
+
+	for key, active := range d.children {
+		if children[key] {
+			continue
+		}
+		if active {
+			children[key] = false
+			continue
+		}
+		d.started.DeleteLabelValues(key[:]...)
+		d.ended.DeleteLabelValues(key[:]...)
+		d.roams.DeleteLabelValues(key[:]...)
+	}
+
+	for key, active := range d.durations {
+		if durations[key] {
+			continue
+		}
+		if active {
+			durations[key] = false
+			continue
+		}
+		d.duration.DeleteLabelValues(key[:]...)
+	}
+
+	d.children = children
+	d.durations = durations
+}
+
+// compare counts what happened to a client seen in both snapshots and returns the
+// session to remember for it.
+func (d *clientSessionDescs) compare(previous, session clientSession) clientSession {
+	reassociated := !previous.associatedAt.IsZero() && !session.associatedAt.IsZero() &&
+		!previous.associatedAt.Equal(session.associatedAt)
+
+	switch {
+	case previous.wlanID != session.wlanID,
+		previous.ap == session.ap && reassociated:
+		d.end(previous)
+		d.start(session)
+		return session
+	case previous.ap != session.ap:
+		d.roams.WithLabelValues(sessionLabels(session)...).Inc()
+	}
+
+	// The session continues, so it keeps the start it began with. A start the dot11
+	// data could not name earlier is taken from this snapshot.
+	if !previous.startedAt.IsZero() {
+		session.startedAt = previous.startedAt
+	}
+
+	return session
+}
+
+// start counts a session that began since the last snapshot.
+func (d *clientSessionDescs) start(session clientSession) {
+	d.started.WithLabelValues(sessionLabels(session)...).Inc()
+}
+
+// end counts a session that ended since the last snapshot and observes its length, when
+// the dot11 data ever named the association that began it.
+func (d *clientSessionDescs) end(session clientSession) {
+	d.ended.WithLabelValues(sessionLabels(session)...).Inc()
+
+	if session.startedAt.IsZero() {
+		return
+	}
+	d.duration.WithLabelValues(strconv.Itoa(session.wlanID), session.band).
+		Observe(session.lastSeen.Sub(session.startedAt).Seconds())
+}
+
+// sessionLabels returns the label values of the three counters.
+func sessionLabels(session clientSession) []string {
+	return []string{strconv.Itoa(session.wlanID), session.ap, session.band}
+}
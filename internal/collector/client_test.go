@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"maps"
 	"strings"
 	"testing"
 	"time"
@@ -203,6 +204,21 @@ func TestNewClientCollector(t *testing.T) {
 			ClientMetrics{Errors: true},
 			false,
 		},
+		{
+			"Devices module enabled",
+			ClientMetrics{Devices: true},
+			false,
+		},
+		{
+			"Onboarding module enabled",
+			ClientMetrics{Onboarding: true},
+			false,
+		},
+		{
+			"Sessions module enabled",
+			ClientMetrics{Sessions: true},
+			false,
+		},
 		{
 			"Info module enabled",
 			ClientMetrics{Info: true},
@@ -290,6 +306,21 @@ func TestClientCollector_isAnyMetricFlagEnabled(t *testing.T) {
 			ClientMetrics{Errors: true},
 			true,
 		},
+		{
+			"Devices enabled",
+			ClientMetrics{Devices: true},
+			true,
+		},
+		{
+			"Onboarding enabled",
+			ClientMetrics{Onboarding: true},
+			true,
+		},
+		{
+			"Sessions enabled",
+			ClientMetrics{Sessions: true},
+			true,
+		},
 		{
 			"Info enabled",
 			ClientMetrics{Info: true},
@@ -345,6 +376,21 @@ func TestClientCollector_Describe(t *testing.T) {
 			ClientMetrics{Errors: true},
 			11, // policy_errors, duplicate_received, decryption_failed, mic_mismatch, mic_missing, excessive_retries, rx_group, tx_drops, data_retries, rts_retries, tx_retries
 		},
+		{
+			"Devices module only",
+			ClientMetrics{Devices: true},
+			1, // devices
+		},
+		{
+			"Onboarding module only",
+			ClientMetrics{Onboarding: true},
+			2, // onboarding_latency, onboarding_pending
+		},
+		{
+			"Sessions module only",
+			ClientMetrics{Sessions: true},
+			4, // sessions_started, sessions_ended, roams_observed, session_duration
+		},
 		{
 			"Info module only",
 			ClientMetrics{Info: true},
@@ -353,13 +399,16 @@ func TestClientCollector_Describe(t *testing.T) {
 		{
 			"All modules enabled",
 			ClientMetrics{
-				General: true,
-				Radio:   true,
-				Traffic: true,
-				Errors:  true,
-				Info:    true,
+				General:    true,
+				Radio:      true,
+				Traffic:    true,
+				Errors:     true,
+				Devices:    true,
+				Onboarding: true,
+				Sessions:   true,
+				Info:       true,
 			},
-			27, // 5+6+4+11+1
+			34, // 5+6+4+11+1+2+4+1
 		},
 	}
 
@@ -1530,3 +1579,378 @@ func TestClientCollector_collectMetrics_NilSafety(t *testing.T) {
 		})
 	}
 }
+
+// gatherClientDevices collects the devices module alone over the given snapshot and
+// returns every sample as "id|band|device_type|os|vendor" mapped to its value.
+func gatherClientDevices(t *testing.T, data *wnc.WNCDataCache) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewClientCollector(
+		wnc.NewClientSource(fixtureSource{data: data}), ClientMetrics{Devices: true},
+	))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			key := strings.Join([]string{
+				labels[labelID], labels[labelBand], labels[labelDeviceType], labels[labelOS], labels[labelVendor],
+			}, "|")
+			values[key] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+// TestClientDevicesModule_CountsByClassification pins the fold: clients sharing a WLAN,
+// a band and a classification are one series, a client the controller has not
+// classified counts as unknown, and a client short of the run state is not counted.
+func TestClientDevicesModule_CountsByClassification(t *testing.T) {
+	t.Parallel()
+
+	run := func(mac string, wlanID int, radioType string) client.CommonOperData {
+		return client.CommonOperData{ClientMAC: mac, WlanID: wlanID, MsRadioType: radioType, CoState: ClientStatusRun}
+	}
+	phone := func(mac string) client.DcInfo {
+		return client.DcInfo{ClientMAC: mac, DeviceType: "Apple-iPhone", DeviceOs: "iOS", DeviceVendor: "Apple"}
+	}
+
+	data := fullFixtureSnapshot()
+	data.CommonOperData = []client.CommonOperData{
+		run("00:00:00:00:00:01", 1, "client-dot11ax-5ghz-prot"),
+		run("00:00:00:00:00:02", 1, "client-dot11ac"),
+		run("00:00:00:00:00:03", 2, "client-dot11ax-5ghz-prot"),
+		run("00:00:00:00:00:04", 1, "client-dot11n-24-ghz-prot"),
+		run("00:00:00:00:00:05", 1, "client-dot11g"),
+		{ClientMAC: "00:00:00:00:00:06", WlanID: 1, MsRadioType: "client-dot11ac", CoState: "client-status-authenticating"},
+	}
+	data.DCInfo = []client.DcInfo{
+		phone("00:00:00:00:00:01"), phone("00:00:00:00:00:02"), phone("00:00:00:00:00:03"),
+		{ClientMAC: "00:00:00:00:00:04", DeviceType: "Microsoft-Windows", DeviceOs: "Windows"},
+		phone("00:00:00:00:00:06"),
+	}
+
+	want := map[string]float64{
+		"1|" + Band5GHz + "|Apple-iPhone|iOS|Apple":             2,
+		"2|" + Band5GHz + "|Apple-iPhone|iOS|Apple":             1,
+		"1|" + Band24GHz + "|Microsoft-Windows|Windows|unknown": 1,
+		"1|" + Band24GHz + "|unknown|unknown|unknown":           1,
+	}
+
+	if got := gatherClientDevices(t, data); !maps.Equal(got, want) {
+		t.Errorf("devices module published %v, want %v", got, want)
+	}
+}
+
+// TestClientOnboardingModule_ObservesEachAssociationOnce pins the state the module keeps
+// across snapshots: the first snapshot only records the run latencies it finds, a later
+// one observes an association once however many snapshots it stays for, a snapshot read
+// by two scrapes is compared once, and a reassociation is observed again. It also pins
+// the pending snapshot, which counts a held client in its phase.
+func TestClientOnboardingModule_ObservesEachAssociationOnce(t *testing.T) {
+	t.Parallel()
+
+	const runMAC, heldMAC = "00:00:00:00:00:01", "00:00:00:00:00:02"
+
+	history := newFixtureMobilityHistory()
+	history.ClientMAC = runMAC
+	history.MobilityHistory.Entry[0].MsAssocTime = time.Now().Add(-time.Hour)
+
+	data := fullFixtureSnapshot()
+	data.CommonOperData = []client.CommonOperData{
+		{ClientMAC: runMAC, WlanID: 1, CoState: ClientStatusRun},
+		{ClientMAC: heldMAC, WlanID: 1, CoState: "client-status-ip-learning"},
+	}
+	data.Dot11OperData = []client.Dot11OperData{
+		{MsMACAddress: heldMAC, MsAssocTime: time.Now().Add(-20 * time.Second)},
+	}
+	data.MmIfClientHistory = []client.MmIfClientHistory{history}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewClientCollector(
+		wnc.NewClientSource(fixtureSource{data: data}), ClientMetrics{Onboarding: true},
+	))
+
+	// scrape returns the run latency count and sum of WLAN 1, and the pending count and
+	// the count at or below 10s for each phase.
+	scrape := func() (uint64, float64, map[string][2]uint64) {
+		t.Helper()
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v, want nil", err)
+		}
+
+		var count uint64
+		var sum float64
+		pending := make(map[string][2]uint64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				var phase string
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == labelPhase {
+						phase = pair.GetValue()
+					}
+				}
+				histogram := metric.GetHistogram()
+				switch family.GetName() {
+				case "wnc_client_onboarding_latency_seconds":
+					if phase == onboardingPhaseRun {
+						count, sum = histogram.GetSampleCount(), histogram.GetSampleSum()
+					}
+				case "wnc_client_onboarding_pending_seconds":
+					var withinTen uint64
+					for _, bucket := range histogram.GetBucket() {
+						if bucket.GetUpperBound() == 10 {
+							withinTen = bucket.GetCumulativeCount()
+						}
+					}
+					pending[phase] = [2]uint64{histogram.GetSampleCount(), withinTen}
+				}
+			}
+		}
+		return count, sum, pending
+	}
+
+	count, _, pending := scrape()
+	if count != 0 {
+		t.Errorf("first snapshot observed %d associations, want 0: it only primes", count)
+	}
+	wantPending := map[string][2]uint64{
+		"l2auth": {0, 0}, "mobility": {0, 0}, "iplearn": {1, 0}, "webauth_pending": {0, 0},
+	}
+	if !maps.Equal(pending, wantPending) {
+		t.Errorf("pending = %v, want %v", pending, wantPending)
+	}
+
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	if count, _, _ = scrape(); count != 0 {
+		t.Errorf("second snapshot observed %d associations, want 0: nothing reassociated", count)
+	}
+
+	// The client reassociates: a new association time and a new latency. The snapshot is
+	// read by two scrapes and observed by the first alone, and the one after it holds
+	// the same association.
+	data.MmIfClientHistory[0].MobilityHistory.Entry[0].MsAssocTime = time.Now()
+	data.MmIfClientHistory[0].MobilityHistory.Entry[0].RunLatency = 1500
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+
+	for range 2 {
+		count, sum, _ := scrape()
+		if count != 1 || sum != 1.5 {
+			t.Errorf("after reassociation observed count %d sum %v, want 1 and 1.5", count, sum)
+		}
+	}
+
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	if count, _, _ := scrape(); count != 1 {
+		t.Errorf("next snapshot observed count %d, want 1: the association was observed", count)
+	}
+}
+
+// TestClientOnboardingModule_TimesEachPhase pins the per-phase latency: a client seen in
+// the first phase is timed from its association, a later phase from the snapshot that
+// first saw the client in it to the one that first saw it past it, and a client that
+// left the list before completing its phase is not observed. It also pins that the
+// children of a WLAN no client is on any more are deleted.
+func TestClientOnboardingModule_TimesEachPhase(t *testing.T) {
+	t.Parallel()
+
+	const fast, slow, quitter = "00:00:00:00:00:01", "00:00:00:00:00:02", "00:00:00:00:00:03"
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	associatedAt := start.Add(-10 * time.Second)
+	state := func(mac string, wlanID int, coState string) client.CommonOperData {
+		return client.CommonOperData{ClientMAC: mac, WlanID: wlanID, CoState: coState}
+	}
+
+	data := fullFixtureSnapshot()
+	data.RefreshedAt = start
+	data.CommonOperData = []client.CommonOperData{
+		state(fast, 1, "client-status-authenticating"),
+		state(slow, 1, "client-status-authenticating"),
+		state(quitter, 2, "client-status-webauth-required"),
+	}
+	data.Dot11OperData = []client.Dot11OperData{
+		{MsMACAddress: fast, MsAssocTime: associatedAt},
+		{MsMACAddress: slow, MsAssocTime: associatedAt},
+		{MsMACAddress: quitter, MsAssocTime: associatedAt},
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewClientCollector(
+		wnc.NewClientSource(fixtureSource{data: data}), ClientMetrics{Onboarding: true},
+	))
+
+	// scrape returns the sample count and sum of every latency child as "id|phase".
+	scrape := func() map[string][2]float64 {
+		t.Helper()
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v, want nil", err)
+		}
+
+		latencies := make(map[string][2]float64)
+		for _, family := range families {
+			if family.GetName() != "wnc_client_onboarding_latency_seconds" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				labels := make(map[string]string, len(metric.GetLabel()))
+				for _, pair := range metric.GetLabel() {
+					labels[pair.GetName()] = pair.GetValue()
+				}
+				histogram := metric.GetHistogram()
+				latencies[labels[labelID]+"|"+labels[labelPhase]] = [2]float64{
+					float64(histogram.GetSampleCount()), histogram.GetSampleSum(),
+				}
+			}
+		}
+		return latencies
+	}
+
+	if got := scrape(); len(got) != 10 || got["1|l2auth"] != [2]float64{} || got["2|run"] != [2]float64{} {
+		t.Errorf("first snapshot = %v, want five empty phases for each of the two WLANs", got)
+	}
+
+	// A minute on, one client reached IP learning and the other is still held, and the
+	// client on WLAN 2 left without completing web authentication.
+	data.RefreshedAt = start.Add(time.Minute)
+	data.CommonOperData = []client.CommonOperData{
+		state(fast, 1, "client-status-ip-learning"),
+		state(slow, 1, "client-status-authenticating"),
+	}
+
+	got := scrape()
+	if want := [2]float64{1, 70}; got["1|l2auth"] != want {
+		t.Errorf("l2auth latency = %v, want %v timed from the association", got["1|l2auth"], want)
+	}
+	if _, ok := got["2|webauth_pending"]; ok {
+		t.Errorf("WLAN 2 kept its children after its last client left: %v", got)
+	}
+
+	// Another minute on, the first client runs after a minute in IP learning.
+	data.RefreshedAt = start.Add(2 * time.Minute)
+	data.CommonOperData = []client.CommonOperData{
+		state(fast, 1, ClientStatusRun),
+		state(slow, 1, "client-status-authenticating"),
+	}
+
+	got = scrape()
+	if want := [2]float64{1, 60}; got["1|iplearn"] != want {
+		t.Errorf("iplearn latency = %v, want %v timed between the two snapshots", got["1|iplearn"], want)
+	}
+	if want := [2]float64{1, 70}; got["1|l2auth"] != want {
+		t.Errorf("l2auth latency = %v, want %v: the held client has not completed it", got["1|l2auth"], want)
+	}
+}
+
+// TestClientSessionsModule_CountsTheDifferenceBetweenSnapshots pins the comparison: the
+// first snapshot only records, and the second counts a client that arrived as a start,
+// one that left as an end with its duration, and one on another AP as a roam rather
+// than an end and a start. A snapshot read by two scrapes is compared once, and the
+// children of an AP no client is on are deleted once a whole snapshot has passed
+// without one.
+func TestClientSessionsModule_CountsTheDifferenceBetweenSnapshots(t *testing.T) {
+	t.Parallel()
+
+	const roamer, leaver, joiner = "00:00:00:00:00:01", "00:00:00:00:00:02", "00:00:00:00:00:03"
+
+	run := func(mac, ap string) client.CommonOperData {
+		return client.CommonOperData{
+			ClientMAC: mac, ApName: ap, WlanID: 1, MsRadioType: "client-dot11ax-5ghz-prot", CoState: ClientStatusRun,
+		}
+	}
+	associated := func(mac string, ago time.Duration) client.Dot11OperData {
+		return client.Dot11OperData{MsMACAddress: mac, MsAssocTime: time.Now().Add(-ago)}
+	}
+
+	data := fullFixtureSnapshot()
+	data.CommonOperData = []client.CommonOperData{run(roamer, "AP1"), run(leaver, "AP1")}
+	data.Dot11OperData = []client.Dot11OperData{
+		associated(roamer, time.Hour), associated(leaver, 10*time.Minute),
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewClientCollector(
+		wnc.NewClientSource(fixtureSource{data: data}), ClientMetrics{Sessions: true},
+	))
+
+	// scrape returns every sample as "family|ap" mapped to its value, reading the sample
+	// count of the histogram.
+	scrape := func() map[string]float64 {
+		t.Helper()
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v, want nil", err)
+		}
+
+		values := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				var ap string
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == labelAP {
+						ap = pair.GetValue()
+					}
+				}
+				values[family.GetName()+"|"+ap] = metric.GetCounter().GetValue() +
+					float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+		return values
+	}
+
+	want := map[string]float64{
+		"wnc_client_sessions_started_total|AP1": 0,
+		"wnc_client_sessions_ended_total|AP1":   0,
+		"wnc_client_roams_observed_total|AP1":   0,
+		"wnc_client_session_duration_seconds|":  0,
+	}
+	if got := scrape(); !maps.Equal(got, want) {
+		t.Errorf("first snapshot counted %v, want %v: it only primes", got, want)
+	}
+
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	data.CommonOperData = []client.CommonOperData{run(roamer, "AP2"), run(joiner, "AP2")}
+	data.Dot11OperData = []client.Dot11OperData{
+		associated(roamer, time.Second), associated(joiner, time.Second),
+	}
+
+	want = map[string]float64{
+		"wnc_client_sessions_started_total|AP1": 0,
+		"wnc_client_sessions_ended_total|AP1":   1,
+		"wnc_client_roams_observed_total|AP1":   0,
+		"wnc_client_sessions_started_total|AP2": 1,
+		"wnc_client_sessions_ended_total|AP2":   0,
+		"wnc_client_roams_observed_total|AP2":   1,
+		"wnc_client_session_duration_seconds|":  1,
+	}
+	for range 2 {
+		if got := scrape(); !maps.Equal(got, want) {
+			t.Errorf("second snapshot counted %v, want %v", got, want)
+		}
+	}
+
+	// A whole snapshot without a client on AP1 deletes its children.
+	data.RefreshedAt = data.RefreshedAt.Add(time.Minute)
+	for key := range want {
+		if strings.HasSuffix(key, "|AP1") {
+			delete(want, key)
+		}
+	}
+	if got := scrape(); !maps.Equal(got, want) {
+		t.Errorf("third snapshot counted %v, want %v", got, want)
+	}
+}
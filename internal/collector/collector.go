@@ -13,7 +13,10 @@ import (
 
 // Collector manages Prometheus collectors and registry.
 type Collector struct {
-	registry         *prometheus.Registry
+	registry *prometheus.Registry
+	// registerer is what the collectors are registered with. It is the registry
+	// itself, except for the metric catalog, which only describes them.
+	registerer       prometheus.Registerer
 	cfg              *config.Config
 	sharedDataSource wnc.DataSource
 }
@@ -30,12 +33,23 @@ type StringMetric struct {
 	Value string
 }
 
-// NewCollector creates a new collector manager.
+// NewCollector creates a new collector manager. A module whose families the metric
+// filter withholds all of is not registered, and a data type only withheld families
+// read is not fetched.
 func NewCollector(cfg *config.Config) *Collector {
-	sharedDataSource := wnc.NewDataSource(cfg.WNC, cfg.Collectors)
+	if cfg.Collectors.FiltersMetrics() {
+		active := *cfg
+		active.Collectors = ActiveModules(cfg)
+		cfg = &active
+	}
+
+	sharedDataSource := wnc.NewDataSourceFor(cfg.WNC, RequiredDataTypes(cfg))
+
+	registry := prometheus.NewRegistry()
 
 	return &Collector{
-		registry:         prometheus.NewRegistry(),
+		registry:         registry,
+		registerer:       registry,
 		cfg:              cfg,
 		sharedDataSource: sharedDataSource,
 	}
@@ -46,6 +60,11 @@ func (c *Collector) Registry() *prometheus.Registry {
 	return c.registry
 }
 
+// DataSource returns the WNC data source every collector reads from.
+func (c *Collector) DataSource() wnc.DataSource {
+	return c.sharedDataSource
+}
+
 // Setup configures and registers all collectors based on configuration.
 func (c *Collector) Setup(version string) {
 	c.RegisterBuildInfo(version)
@@ -55,25 +74,32 @@ func (c *Collector) Setup(version string) {
 
 // RegisterBuildInfo registers the build information metric.
 func (c *Collector) RegisterBuildInfo(version string) {
-	buildInfo := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "wnc_build_info",
-			Help: "Build information for the WNC exporter.",
-		},
-		[]string{"version"},
-	)
+	families := &familySet{}
+	buildInfo := families.gaugeVec("wnc_build_info", "Build information for the WNC exporter.", []string{"version"})
 	buildInfo.WithLabelValues(version).Set(1)
-	c.registry.MustRegister(buildInfo)
+	c.registerer.MustRegister(&buildInfoCollector{GaugeVec: buildInfo, families: families})
+}
+
+// buildInfoCollector publishes the build information series with its declaration.
+type buildInfoCollector struct {
+	*prometheus.GaugeVec
+
+	families *familySet
+}
+
+// declaredFamilies returns the build information family.
+func (c *buildInfoCollector) declaredFamilies() *familySet {
+	return c.families
 }
 
 // RegisterSystemCollectors registers Go and process collectors conditionally.
 func (c *Collector) RegisterSystemCollectors() {
 	if c.cfg.InternalCollector.EnableGoCollector {
-		c.registry.MustRegister(collectors.NewGoCollector())
+		c.registerer.MustRegister(collectors.NewGoCollector())
 		slog.Debug("Registered Go collector")
 	}
 	if c.cfg.InternalCollector.EnableProcessCollector {
-		c.registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		c.registerer.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 		slog.Debug("Registered process collector")
 	}
 }
@@ -89,7 +115,14 @@ func (c *Collector) RegisterServiceCollectors() {
 		c.cfg.Collectors.AP.Traffic,
 		c.cfg.Collectors.AP.Errors,
 		c.cfg.Collectors.AP.Join,
+		c.cfg.Collectors.AP.Uplink,
+		c.cfg.Collectors.AP.Mesh,
+		c.cfg.Collectors.AP.QoS,
+		c.cfg.Collectors.AP.Restarts,
+		c.cfg.Collectors.AP.Departed,
+		c.cfg.Collectors.AP.Neighbors,
 		c.cfg.Collectors.AP.Spectrum,
+		c.cfg.Collectors.AP.Interferers,
 		c.cfg.Collectors.AP.Info,
 	) {
 		apSource := wnc.NewAPSource(c.sharedDataSource)
@@ -106,6 +139,8 @@ func (c *Collector) RegisterServiceCollectors() {
 		c.cfg.Collectors.WLAN.General,
 		c.cfg.Collectors.WLAN.Traffic,
 		c.cfg.Collectors.WLAN.Config,
+		c.cfg.Collectors.WLAN.Applications,
+		c.cfg.Collectors.WLAN.ATF,
 		c.cfg.Collectors.WLAN.Info,
 	) {
 		wlanSource := wnc.NewWLANSource(c.sharedDataSource)
@@ -117,7 +152,7 @@ func (c *Collector) RegisterServiceCollectors() {
 	}
 
 	// Register the controller collector if any controller module is enabled
-	if IsEnabled(c.cfg.Collectors.Controller.General) {
+	if IsEnabled(c.cfg.Collectors.Controller.General, c.cfg.Collectors.Controller.AAA) {
 		controllerSource := wnc.NewControllerSource(c.sharedDataSource)
 		c.registerControllerCollector(controllerSource)
 		registered = true
@@ -125,12 +160,26 @@ func (c *Collector) RegisterServiceCollectors() {
 		slog.Debug("Skipped controller collector registration - all modules disabled")
 	}
 
+	// Register the RRM collector if any RRM module is enabled
+	if IsEnabled(c.cfg.Collectors.RRM.Channels) {
+		apSource := wnc.NewAPSource(c.sharedDataSource)
+		rrmSource := wnc.NewRRMSource(c.sharedDataSource)
+		clientSource := wnc.NewClientSource(c.sharedDataSource)
+		c.registerRRMCollector(apSource, rrmSource, clientSource)
+		registered = true
+	} else {
+		slog.Debug("Skipped RRM collector registration - all modules disabled")
+	}
+
 	// Register Client collector if any Client module is enabled
 	if IsEnabled(
 		c.cfg.Collectors.Client.General,
 		c.cfg.Collectors.Client.Radio,
 		c.cfg.Collectors.Client.Traffic,
 		c.cfg.Collectors.Client.Errors,
+		c.cfg.Collectors.Client.Devices,
+		c.cfg.Collectors.Client.Onboarding,
+		c.cfg.Collectors.Client.Sessions,
 		c.cfg.Collectors.Client.Info,
 	) {
 		clientSource := wnc.NewClientSource(c.sharedDataSource)
@@ -154,21 +203,51 @@ func (c *Collector) registerRefreshCollector() {
 		return
 	}
 
-	c.registry.MustRegister(NewSafeCollector(NewRefreshCollector(stats), "Refresh"))
+	c.serviceRegisterer().MustRegister(NewSafeCollector(NewRefreshCollector(stats), "Refresh"))
 	slog.Debug("Registered refresh collector")
 }
 
+// registerModuleCollector registers the collector of a module-driven service, behind the
+// metric filter when one is configured. The exporter's own series are never filtered.
+func (c *Collector) registerModuleCollector(collector prometheus.Collector) {
+	if c.cfg.Collectors.FiltersMetrics() {
+		collector = NewFilterCollector(collector, c.cfg.Collectors.MetricAllowed)
+	}
+	c.serviceRegisterer().MustRegister(collector)
+}
+
+// serviceRegisterer returns the registerer the module and refresh health collectors are
+// registered with, which adds the configured constant labels to their series. The build
+// information and the Go and process series are left as they are.
+func (c *Collector) serviceRegisterer() prometheus.Registerer {
+	if len(c.cfg.Collectors.ConstLabels) == 0 {
+		return c.registerer
+	}
+	return prometheus.WrapRegistererWith(c.cfg.Collectors.ConstLabels, c.registerer)
+}
+
 // registerAPCollector registers the AP collector with its modules.
 func (c *Collector) registerAPCollector(apSource wnc.APSource, rrmSource wnc.RRMSource, clientSource wnc.ClientSource) {
 	baseCollector := NewAPCollector(apSource, rrmSource, clientSource, APMetrics{
-		General:    c.cfg.Collectors.AP.General,
-		Radio:      c.cfg.Collectors.AP.Radio,
-		Traffic:    c.cfg.Collectors.AP.Traffic,
-		Errors:     c.cfg.Collectors.AP.Errors,
-		Join:       c.cfg.Collectors.AP.Join,
-		Spectrum:   c.cfg.Collectors.AP.Spectrum,
-		Info:       c.cfg.Collectors.AP.Info,
-		InfoLabels: c.cfg.Collectors.AP.InfoLabels,
+		General:       c.cfg.Collectors.AP.General,
+		Radio:         c.cfg.Collectors.AP.Radio,
+		Traffic:       c.cfg.Collectors.AP.Traffic,
+		Errors:        c.cfg.Collectors.AP.Errors,
+		Join:          c.cfg.Collectors.AP.Join,
+		Uplink:        c.cfg.Collectors.AP.Uplink,
+		Mesh:          c.cfg.Collectors.AP.Mesh,
+		QoS:           c.cfg.Collectors.AP.QoS,
+		Restarts:      c.cfg.Collectors.AP.Restarts,
+		Departed:      c.cfg.Collectors.AP.Departed,
+		Neighbors:     c.cfg.Collectors.AP.Neighbors,
+		NeighborsTopN: c.cfg.Collectors.AP.NeighborsTopN,
+		Spectrum:      c.cfg.Collectors.AP.Spectrum,
+		Interferers:   c.cfg.Collectors.AP.Interferers,
+		Info:          c.cfg.Collectors.AP.Info,
+		InfoLabels:    c.cfg.Collectors.AP.InfoLabels,
+
+		DepartedRetention: c.cfg.Collectors.AP.DepartedRetention,
+		DepartedStateFile: c.cfg.Collectors.AP.DepartedStateFile,
 	})
 
 	// Recover panics next to the base collector so the goroutine InfoCacheCollector spawns is covered.
@@ -179,18 +258,21 @@ func (c *Collector) registerAPCollector(apSource wnc.APSource, rrmSource wnc.RRM
 		collector = NewInfoCacheCollector(collector, "AP", c.cfg.Collectors.InfoCacheTTL)
 	}
 
-	c.registry.MustRegister(collector)
+	c.registerModuleCollector(collector)
 	slog.Debug("Registered AP collector")
 }
 
 // registerWLANCollector registers the WLAN collector with its modules.
 func (c *Collector) registerWLANCollector(wlanSource wnc.WLANSource, clientSource wnc.ClientSource) {
 	baseCollector := NewWLANCollector(wlanSource, clientSource, WLANMetrics{
-		General:    c.cfg.Collectors.WLAN.General,
-		Traffic:    c.cfg.Collectors.WLAN.Traffic,
-		Config:     c.cfg.Collectors.WLAN.Config,
-		Info:       c.cfg.Collectors.WLAN.Info,
-		InfoLabels: c.cfg.Collectors.WLAN.InfoLabels,
+		General:          c.cfg.Collectors.WLAN.General,
+		Traffic:          c.cfg.Collectors.WLAN.Traffic,
+		Config:           c.cfg.Collectors.WLAN.Config,
+		Applications:     c.cfg.Collectors.WLAN.Applications,
+		ApplicationsTopN: c.cfg.Collectors.WLAN.ApplicationsTopN,
+		ATF:              c.cfg.Collectors.WLAN.ATF,
+		Info:             c.cfg.Collectors.WLAN.Info,
+		InfoLabels:       c.cfg.Collectors.WLAN.InfoLabels,
 	})
 
 	// Recover panics next to the base collector so the goroutine InfoCacheCollector spawns is covered.
@@ -201,7 +283,7 @@ func (c *Collector) registerWLANCollector(wlanSource wnc.WLANSource, clientSourc
 		collector = NewInfoCacheCollector(collector, "WLAN", c.cfg.Collectors.InfoCacheTTL)
 	}
 
-	c.registry.MustRegister(collector)
+	c.registerModuleCollector(collector)
 	slog.Debug("Registered WLAN collector")
 }
 
@@ -210,12 +292,26 @@ func (c *Collector) registerWLANCollector(wlanSource wnc.WLANSource, clientSourc
 func (c *Collector) registerControllerCollector(controllerSource wnc.ControllerSource) {
 	baseCollector := NewControllerCollector(controllerSource, ControllerMetrics{
 		General: c.cfg.Collectors.Controller.General,
+		AAA:     c.cfg.Collectors.Controller.AAA,
 	})
 
-	c.registry.MustRegister(NewSafeCollector(baseCollector, "Controller"))
+	c.registerModuleCollector(NewSafeCollector(baseCollector, "Controller"))
 	slog.Debug("Registered controller collector")
 }
 
+// registerRRMCollector registers the RRM collector with its modules.
+// It has no info metrics, so no caching wrapper applies to it.
+func (c *Collector) registerRRMCollector(
+	apSource wnc.APSource, rrmSource wnc.RRMSource, clientSource wnc.ClientSource,
+) {
+	baseCollector := NewRRMCollector(apSource, rrmSource, clientSource, RRMMetrics{
+		Channels: c.cfg.Collectors.RRM.Channels,
+	})
+
+	c.registerModuleCollector(NewSafeCollector(baseCollector, "RRM"))
+	slog.Debug("Registered RRM collector")
+}
+
 // registerClientCollector registers the Client collector with its modules.
 func (c *Collector) registerClientCollector(clientSource wnc.ClientSource) {
 	baseCollector := NewClientCollector(clientSource, ClientMetrics{
@@ -223,6 +319,9 @@ func (c *Collector) registerClientCollector(clientSource wnc.ClientSource) {
 		Radio:      c.cfg.Collectors.Client.Radio,
 		Traffic:    c.cfg.Collectors.Client.Traffic,
 		Errors:     c.cfg.Collectors.Client.Errors,
+		Devices:    c.cfg.Collectors.Client.Devices,
+		Onboarding: c.cfg.Collectors.Client.Onboarding,
+		Sessions:   c.cfg.Collectors.Client.Sessions,
 		Info:       c.cfg.Collectors.Client.Info,
 		InfoLabels: c.cfg.Collectors.Client.InfoLabels,
 	})
@@ -235,6 +334,6 @@ func (c *Collector) registerClientCollector(clientSource wnc.ClientSource) {
 		collector = NewInfoCacheCollector(collector, "Client", c.cfg.Collectors.InfoCacheTTL)
 	}
 
-	c.registry.MustRegister(collector)
+	c.registerModuleCollector(collector)
 	slog.Debug("Registered Client collector")
 }
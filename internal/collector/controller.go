@@ -25,14 +25,19 @@ const (
 // ControllerMetrics represents which controller metrics are enabled.
 type ControllerMetrics struct {
 	General bool
+	AAA     bool
 }
 
 // ControllerCollector implements prometheus.Collector for controller-wide metrics.
 // Its series describe the controller itself rather than an AP, a client or a WLAN, so
 // none of them carries an identifying label.
 type ControllerCollector struct {
-	metrics ControllerMetrics
-	src     wnc.ControllerSource
+	metrics  ControllerMetrics
+	families *familySet
+	src      wnc.ControllerSource
+
+	// aaa holds the descriptors of the AAA module, nil while it is disabled.
+	aaa *controllerAAADescs
 
 	bootTimeDesc      *prometheus.Desc
 	clientDeletesDesc *prometheus.Desc
@@ -44,28 +49,30 @@ type ControllerCollector struct {
 
 // NewControllerCollector creates a new controller collector.
 func NewControllerCollector(src wnc.ControllerSource, metrics ControllerMetrics) *ControllerCollector {
+	families := &familySet{}
 	collector := &ControllerCollector{
-		src:     src,
-		metrics: metrics,
+		src:      src,
+		metrics:  metrics,
+		families: families,
 	}
 
 	if metrics.General {
-		collector.bootTimeDesc = prometheus.NewDesc(
+		collector.bootTimeDesc = families.gauge(
 			"wnc_controller_boot_time_seconds",
 			"Unix time the controller last booted. Withheld rather than reported as 0 when "+
 				"the controller does not carry the leaf, so a counter reset check has no epoch "+
 				"instead of a false one. The leaf moves by a second between reads, so compare it "+
 				"against a threshold rather than with changes() or an equality",
-			nil, nil,
+			nil, wnc.DataControllerBootTime,
 		)
-		collector.clientDeletesDesc = prometheus.NewDesc(
+		collector.clientDeletesDesc = families.counter(
 			"wnc_controller_client_deletes_total",
 			"Client deletions the controller counted for this reason, one series per reason "+
 				"leaf it reports, spelled as the controller spells it. The container carries no "+
 				"epoch leaf, so read a rise rather than the value",
-			[]string{labelReason}, nil,
+			[]string{labelReason}, wnc.DataCoClientDelReason,
 		)
-		collector.apAuthRoamsDesc = prometheus.NewDesc(
+		collector.apAuthRoamsDesc = families.counter(
 			"wnc_controller_client_ap_auth_roams_total",
 			"Roams the controller counted on the FlexConnect local-authentication path, "+
 				"from an instant the container does not report. A WLAN whose roams do "+
@@ -73,26 +80,35 @@ func NewControllerCollector(src wnc.ControllerSource, metrics ControllerMetrics)
 				"took this path rather than as nobody roamed. It advances on a roam and not on "+
 				"a fresh association, and the two dot11i counters "+
 				"are not a partition of it",
-			nil, nil,
+			nil, wnc.DataClientRoamingStats,
 		)
-		collector.apAuthFastRoamsDesc = prometheus.NewDesc(
+		collector.apAuthFastRoamsDesc = families.counter(
 			"wnc_controller_client_ap_auth_dot11i_fast_roams_total",
 			"802.11i fast roams on the same path and from the same unreported instant. "+
 				"The two dot11i counters are not a partition of the total, so zero here does "+
 				"not describe how the roams behind the total authenticated",
-			nil, nil,
+			nil, wnc.DataClientRoamingStats,
 		)
-		collector.apAuthSlowRoamsDesc = prometheus.NewDesc(
+		collector.apAuthSlowRoamsDesc = families.counter(
 			"wnc_controller_client_ap_auth_dot11i_slow_roams_total",
 			"802.11i slow roams on the same path and from the same unreported instant, "+
 				"each one a full authentication rather than a cached key",
-			nil, nil,
+			nil, wnc.DataClientRoamingStats,
 		)
 	}
 
+	if metrics.AAA {
+		collector.aaa = newControllerAAADescs(families)
+	}
+
 	return collector
 }
 
+// declaredFamilies returns the families of the enabled modules.
+func (c *ControllerCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
 // Describe implements prometheus.Collector.
 func (c *ControllerCollector) Describe(ch chan<- *prometheus.Desc) {
 	if c.metrics.General {
@@ -102,19 +118,37 @@ func (c *ControllerCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- c.apAuthFastRoamsDesc
 		ch <- c.apAuthSlowRoamsDesc
 	}
+
+	if c.aaa != nil {
+		c.aaa.describe(ch)
+	}
 }
 
 // Collect implements prometheus.Collector by retrieving controller data from WNC.
 func (c *ControllerCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx := context.Background()
 
-	if !c.metrics.General {
+	if c.metrics.General {
+		c.collectBootTime(ctx, ch)
+		c.collectClientDeletes(ctx, ch)
+		c.collectRoams(ctx, ch)
+	}
+
+	if c.aaa != nil {
+		c.collectAAA(ctx, ch)
+	}
+}
+
+// collectAAA publishes the AAA module. A failed read publishes nothing, rather than
+// reporting every server as dead.
+func (c *ControllerCollector) collectAAA(ctx context.Context, ch chan<- prometheus.Metric) {
+	servers, err := c.src.GetAAAServers(ctx)
+	if err != nil {
+		slog.Debug("Failed to get controller AAA server statistics", "error", err)
 		return
 	}
 
-	c.collectBootTime(ctx, ch)
-	c.collectClientDeletes(ctx, ch)
-	c.collectRoams(ctx, ch)
+	c.aaa.collect(ch, servers)
 }
 
 // collectRoams publishes the three roam counters the controller maintains, each only
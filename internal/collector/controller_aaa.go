@@ -0,0 +1,160 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the AAA module of the controller collector.
+package collector
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// millisecondsPerSecond converts the round-trip time the controller reports.
+const millisecondsPerSecond = 1000.0
+
+// controllerAAADescs holds the descriptors of the AAA module. A nil value means the
+// module is disabled, which is what keeps every series of it out of a default scrape.
+type controllerAAADescs struct {
+	authRequests    *prometheus.Desc
+	authAccepts     *prometheus.Desc
+	authRejects     *prometheus.Desc
+	authTimeouts    *prometheus.Desc
+	authRetransmits *prometheus.Desc
+	acctRequests    *prometheus.Desc
+	acctResponses   *prometheus.Desc
+	acctTimeouts    *prometheus.Desc
+	acctRetransmits *prometheus.Desc
+	responseTime    *prometheus.Desc
+	up              *prometheus.Desc
+}
+
+// newControllerAAADescs builds the descriptors of the AAA module.
+//
+// A server is keyed by its group and its two ports as well as its name and address,
+// which is the key the controller keeps the list by: it counts a server apart per group
+// it belongs to, and two servers on one address are told apart only by their ports, so
+// records under one name and address would otherwise collide.
+func newControllerAAADescs(families *familySet) *controllerAAADescs {
+	labels := []string{labelName, labelAddress, labelGroup, labelAuthPort, labelAcctPort}
+
+	counter := func(name, help string) *prometheus.Desc {
+		return families.counter(name, help+". The list carries no epoch leaf, so read a rise "+
+			"rather than the value", labels, wnc.DataAAARadiusStats)
+	}
+
+	return &controllerAAADescs{
+		authRequests: counter("wnc_controller_aaa_auth_requests_total",
+			"RADIUS Access-Requests the controller sent this server"),
+		authAccepts: counter("wnc_controller_aaa_auth_accepts_total",
+			"Access-Accepts this server answered with"),
+		authRejects: counter("wnc_controller_aaa_auth_rejects_total",
+			"Access-Rejects this server answered with"),
+		authTimeouts: counter("wnc_controller_aaa_auth_timeouts_total",
+			"Access-Requests to this server that went unanswered past every retransmit"),
+		authRetransmits: counter("wnc_controller_aaa_auth_retransmits_total",
+			"Access-Requests the controller sent this server again after no answer"),
+		acctRequests: counter("wnc_controller_aaa_acct_requests_total",
+			"RADIUS Accounting-Requests the controller sent this server"),
+		acctResponses: counter("wnc_controller_aaa_acct_responses_total",
+			"Accounting-Responses this server answered with"),
+		acctTimeouts: counter("wnc_controller_aaa_acct_timeouts_total",
+			"Accounting-Requests to this server that went unanswered past every retransmit"),
+		acctRetransmits: counter("wnc_controller_aaa_acct_retransmits_total",
+			"Accounting-Requests the controller sent this server again after no answer"),
+		responseTime: families.gauge(
+			"wnc_controller_aaa_response_time_seconds",
+			"Round-trip time of the last response from this server. Absent until the server "+
+				"has answered, rather than reported as an instant answer",
+			labels, wnc.DataAAARadiusStats,
+		),
+		up: families.gauge(
+			"wnc_controller_aaa_server_up",
+			"Whether the controller considers this server alive (0=dead or any other state, "+
+				"1=up). A dead server is skipped until its dead time expires, so requests fail "+
+				"over or time out while this reads 0",
+			labels, wnc.DataAAARadiusStats,
+		),
+	}
+}
+
+// describe sends every descriptor of the AAA module.
+func (d *controllerAAADescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.authRequests
+	ch <- d.authAccepts
+	ch <- d.authRejects
+	ch <- d.authTimeouts
+	ch <- d.authRetransmits
+	ch <- d.acctRequests
+	ch <- d.acctResponses
+	ch <- d.acctTimeouts
+	ch <- d.acctRetransmits
+	ch <- d.responseTime
+	ch <- d.up
+}
+
+// collect publishes every server the RADIUS statistics list carries.
+//
+// A record with no address cannot be told apart from another, so it is dropped, and
+// the same label set is emitted once, since Gather rejects a duplicate by failing the
+// entire endpoint. Both are logged, since either hides a server from every series. A
+// counter the record omits or garbles is withheld rather than published as 0, which
+// would read as a counter that had been reset.
+func (d *controllerAAADescs) collect(ch chan<- prometheus.Metric, servers []wnc.AAARadiusServer) {
+	seen := make(map[[5]string]bool, len(servers))
+
+	for i := range servers {
+		server := &servers[i]
+		if server.ServerIP == "" {
+			slog.Debug("Dropped AAA server record with no address",
+				"name", server.ServerName, "group", server.GroupName)
+			continue
+		}
+
+		labels := [5]string{
+			server.ServerName, server.ServerIP, server.GroupName,
+			strconv.Itoa(server.AuthPort), strconv.Itoa(server.AcctPort),
+		}
+		if seen[labels] {
+			slog.Debug("Dropped repeated AAA server record", "name", server.ServerName,
+				"address", server.ServerIP, "group", server.GroupName,
+				"auth_port", server.AuthPort, "acct_port", server.AcctPort)
+			continue
+		}
+		seen[labels] = true
+
+		for _, counter := range []struct {
+			desc  *prometheus.Desc
+			value wnc.Number
+		}{
+			{d.authRequests, server.AuthRequests},
+			{d.authAccepts, server.AuthAccepts},
+			{d.authRejects, server.AuthRejects},
+			{d.authTimeouts, server.AuthTimeouts},
+			{d.authRetransmits, server.AuthRetransmits},
+			{d.acctRequests, server.AcctRequests},
+			{d.acctResponses, server.AcctResponses},
+			{d.acctTimeouts, server.AcctTimeouts},
+			{d.acctRetransmits, server.AcctRetransmits},
+		} {
+			value, err := counter.value.Float64()
+			if err != nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(counter.desc, prometheus.CounterValue, value, labels[:]...)
+		}
+
+		if rtt, err := server.ResponseTime.Float64(); err == nil && rtt > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.responseTime, prometheus.GaugeValue, rtt/millisecondsPerSecond, labels[:]...,
+			)
+		}
+
+		if server.State != "" {
+			ch <- prometheus.MustNewConstMetric(
+				d.up, prometheus.GaugeValue, boolToFloat64(server.State == AAAServerStateUp), labels[:]...,
+			)
+		}
+	}
+}
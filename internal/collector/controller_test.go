@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -58,6 +59,8 @@ func TestControllerCollector_Describe(t *testing.T) {
 	}{
 		{"No modules enabled", ControllerMetrics{}, 0},
 		{"General module only", ControllerMetrics{General: true}, 5},
+		{"AAA module only", ControllerMetrics{AAA: true}, 11},
+		{"All modules enabled", ControllerMetrics{General: true, AAA: true}, 16},
 	}
 
 	for _, tt := range tests {
@@ -66,7 +69,7 @@ func TestControllerCollector_Describe(t *testing.T) {
 
 			collector := NewControllerCollector(nil, tt.metrics)
 
-			ch := make(chan *prometheus.Desc, 10)
+			ch := make(chan *prometheus.Desc, 20)
 			collector.Describe(ch)
 			close(ch)
 
@@ -208,3 +211,123 @@ func TestControllerCollector_RoamCounterWithheldWhenLeafAbsent(t *testing.T) {
 		}
 	}
 }
+
+// TestControllerAAAModule_WithholdsWhatTheRecordOmits pins the guards of the AAA module.
+// A record with no address is dropped, a repeated label set is emitted once, a dead
+// server reads 0 rather than disappearing, and a counter or round-trip time the record
+// does not carry is withheld rather than published as 0.
+func TestControllerAAAModule_WithholdsWhatTheRecordOmits(t *testing.T) {
+	t.Parallel()
+
+	const address = "192.168.255.51"
+
+	data := fullFixtureSnapshot()
+	data.AAAServers = []wnc.AAARadiusServer{
+		{
+			GroupName: "radius-group", ServerIP: address, ServerName: "radius02", State: "dead",
+			AuthRequests: "10", ResponseTime: "0",
+		},
+		{
+			GroupName: "radius-group", ServerIP: address, ServerName: "radius02", State: "up",
+			AuthRequests: "20",
+		},
+		{GroupName: "radius-group", ServerName: "radius03", State: "up", AuthRequests: "30"},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewControllerCollector(
+		wnc.NewControllerSource(src), ControllerMetrics{AAA: true},
+	))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	series := make(map[string]float64, len(families))
+	for _, family := range families {
+		if len(family.GetMetric()) != 1 {
+			t.Errorf("%s has %d series, want 1", family.GetName(), len(family.GetMetric()))
+		}
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == labelAddress && pair.GetValue() != address {
+					t.Errorf("%s carries the address %q, want only %q",
+						family.GetName(), pair.GetValue(), address)
+				}
+			}
+			switch {
+			case metric.GetGauge() != nil:
+				series[family.GetName()] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				series[family.GetName()] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	if got, ok := series["wnc_controller_aaa_server_up"]; !ok || got != 0 {
+		t.Errorf("wnc_controller_aaa_server_up = %v (present %v), want 0 from the first record", got, ok)
+	}
+	if got := series["wnc_controller_aaa_auth_requests_total"]; got != 10 {
+		t.Errorf("wnc_controller_aaa_auth_requests_total = %v, want 10 from the first record", got)
+	}
+	for _, name := range []string{
+		"wnc_controller_aaa_auth_timeouts_total",
+		"wnc_controller_aaa_acct_requests_total",
+		"wnc_controller_aaa_response_time_seconds",
+	} {
+		if _, ok := series[name]; ok {
+			t.Errorf("%s is present for a leaf the record does not carry", name)
+		}
+	}
+}
+
+// TestControllerAAAModule_KeepsServersApartByPort pins the ports into the key. Two
+// servers on one address and under one name and group are two entries of the list, and
+// the second must not be dropped as a repeat of the first.
+func TestControllerAAAModule_KeepsServersApartByPort(t *testing.T) {
+	t.Parallel()
+
+	data := fullFixtureSnapshot()
+	data.AAAServers = []wnc.AAARadiusServer{
+		{
+			GroupName: "radius-group", ServerIP: "192.168.255.51", ServerName: "radius02",
+			AuthPort: 1812, AcctPort: 1813, AuthRequests: "10",
+		},
+		{
+			GroupName: "radius-group", ServerIP: "192.168.255.51", ServerName: "radius02",
+			AuthPort: 1645, AcctPort: 1646, AuthRequests: "20",
+		},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewControllerCollector(
+		wnc.NewControllerSource(src), ControllerMetrics{AAA: true},
+	))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "wnc_controller_aaa_auth_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			got[labels[labelAuthPort]+"/"+labels[labelAcctPort]] = metric.GetCounter().GetValue()
+		}
+	}
+
+	want := map[string]float64{"1812/1813": 10, "1645/1646": 20}
+	if !maps.Equal(got, want) {
+		t.Errorf("wnc_controller_aaa_auth_requests_total by port = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the family declarations every descriptor is built from.
+package collector
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric types as the catalog names them.
+const (
+	metricTypeCounter   = "counter"
+	metricTypeGauge     = "gauge"
+	metricTypeHistogram = "histogram"
+)
+
+// family declares one metric family: what it is published as and the data types its
+// series are read from, as the data label of the refresh series names them. The
+// descriptor is built from the declaration, so the catalog, the metric filter and the
+// info cache read the declaration rather than the descriptor.
+type family struct {
+	name      string
+	kind      string
+	help      string
+	labels    []string
+	dataTypes []string
+}
+
+// familySet holds the families a collector declares, in declaration order, with the
+// descriptors built for them. The zero value is an empty set ready to use.
+type familySet struct {
+	families []family
+	byDesc   map[*prometheus.Desc]int
+}
+
+// familyDeclarer is implemented by a collector that declares the families it
+// describes, and by the wrappers that pass a declaration through.
+type familyDeclarer interface {
+	declaredFamilies() *familySet
+}
+
+// declaredFamilies returns the families c declares, or nil when it declares none.
+func declaredFamilies(c prometheus.Collector) *familySet {
+	if declarer, ok := c.(familyDeclarer); ok {
+		return declarer.declaredFamilies()
+	}
+	return nil
+}
+
+// gauge declares a gauge family and returns its descriptor.
+func (s *familySet) gauge(name, help string, labels []string, dataTypes ...string) *prometheus.Desc {
+	return s.declare(family{name, metricTypeGauge, help, labels, dataTypes})
+}
+
+// counter declares a counter family and returns its descriptor.
+func (s *familySet) counter(name, help string, labels []string, dataTypes ...string) *prometheus.Desc {
+	return s.declare(family{name, metricTypeCounter, help, labels, dataTypes})
+}
+
+// histogram declares a histogram family and returns its descriptor.
+func (s *familySet) histogram(name, help string, labels []string, dataTypes ...string) *prometheus.Desc {
+	return s.declare(family{name, metricTypeHistogram, help, labels, dataTypes})
+}
+
+// gaugeVec declares a gauge family the collector keeps the series of itself.
+func (s *familySet) gaugeVec(name, help string, labels []string, dataTypes ...string) *prometheus.GaugeVec {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	s.record(family{name, metricTypeGauge, help, labels, dataTypes}, vec)
+	return vec
+}
+
+// counterVec declares a counter family the collector keeps the series of itself.
+func (s *familySet) counterVec(name, help string, labels []string, dataTypes ...string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	s.record(family{name, metricTypeCounter, help, labels, dataTypes}, vec)
+	return vec
+}
+
+// histogramVec declares a histogram family the collector keeps the series of itself.
+func (s *familySet) histogramVec(
+	name, help string, buckets []float64, labels []string, dataTypes ...string,
+) *prometheus.HistogramVec {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	s.record(family{name, metricTypeHistogram, help, labels, dataTypes}, vec)
+	return vec
+}
+
+// declare builds the descriptor of f and records it.
+func (s *familySet) declare(f family) *prometheus.Desc {
+	desc := prometheus.NewDesc(f.name, f.help, f.labels, nil)
+	s.add(f, desc)
+	return desc
+}
+
+// record records the descriptors a metric vector of f describes.
+func (s *familySet) record(f family, vec prometheus.Collector) {
+	for desc := range describe(vec) {
+		s.add(f, desc)
+	}
+}
+
+// add records desc under f. A family declared twice, as wnc_ap_joined is by the two
+// modules publishing it, is listed once with the data types of both.
+func (s *familySet) add(f family, desc *prometheus.Desc) {
+	if s.byDesc == nil {
+		s.byDesc = make(map[*prometheus.Desc]int)
+	}
+
+	i := slices.IndexFunc(s.families, func(declared family) bool { return declared.name == f.name })
+	if i < 0 {
+		i = len(s.families)
+		f.labels = slices.Clone(f.labels)
+		f.dataTypes = slices.Clone(f.dataTypes)
+		s.families = append(s.families, f)
+	}
+	for _, dataType := range f.dataTypes {
+		if !slices.Contains(s.families[i].dataTypes, dataType) {
+			s.families[i].dataTypes = append(s.families[i].dataTypes, dataType)
+		}
+	}
+	s.byDesc[desc] = i
+}
+
+// lookup returns the family desc was built for.
+func (s *familySet) lookup(desc *prometheus.Desc) (family, bool) {
+	if s == nil {
+		return family{}, false
+	}
+	i, ok := s.byDesc[desc]
+	if !ok {
+		return family{}, false
+	}
+	return s.families[i], true
+}
+
+// filter returns the families allowed accepts the name of, with their descriptors.
+func (s *familySet) filter(allowed func(name string) bool) *familySet {
+	kept := &familySet{}
+	if s == nil {
+		return kept
+	}
+	for i, f := range s.families {
+		if !allowed(f.name) {
+			continue
+		}
+		for desc, declared := range s.byDesc {
+			if declared == i {
+				kept.add(f, desc)
+			}
+		}
+	}
+	return kept
+}
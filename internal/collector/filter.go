@@ -0,0 +1,83 @@
+// Package collector provides metric family filtering for collectors.
+package collector
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// FilterCollector wraps a collector so that the families a predicate rejects are neither
+// described nor collected. The base collector still builds them; what is saved is the
+// series in the TSDB, and a module whose families are all rejected is not registered.
+type FilterCollector struct {
+	base     prometheus.Collector
+	withheld map[*prometheus.Desc]struct{}
+	families *familySet
+}
+
+// NewFilterCollector creates a collector that withholds the families of base whose fully
+// qualified name allowed rejects. The families are decided once, from the families base
+// declares, since the collectors create every descriptor when they are built.
+func NewFilterCollector(base prometheus.Collector, allowed func(name string) bool) *FilterCollector {
+	families := declaredFamilies(base)
+	withheld := make(map[*prometheus.Desc]struct{})
+	for desc := range describe(base) {
+		f, ok := families.lookup(desc)
+		if !ok {
+			// Publishing a family the filter meant to drop is recoverable, while
+			// dropping one it meant to keep loses the series.
+			slog.Warn("Metric filter skipped an undeclared descriptor", "descriptor", desc.String())
+			continue
+		}
+		if !allowed(f.name) {
+			withheld[desc] = struct{}{}
+		}
+	}
+
+	return &FilterCollector{
+		base:     base,
+		withheld: withheld,
+		families: families.filter(allowed),
+	}
+}
+
+// declaredFamilies returns the families of the base collector the filter keeps.
+func (c *FilterCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
+// Describe implements prometheus.Collector interface and drops the withheld descriptors.
+func (c *FilterCollector) Describe(ch chan<- *prometheus.Desc) {
+	for desc := range describe(c.base) {
+		if _, drop := c.withheld[desc]; !drop {
+			ch <- desc
+		}
+	}
+}
+
+// Collect implements prometheus.Collector interface and drops the metrics of the
+// withheld families.
+func (c *FilterCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		c.base.Collect(metrics)
+		close(metrics)
+	}()
+	for metric := range metrics {
+		if _, drop := c.withheld[metric.Desc()]; !drop {
+			ch <- metric
+		}
+	}
+}
+
+// describe runs the Describe of a collector and returns its descriptors as a channel
+// closed once it returns.
+func describe(c prometheus.Collector) <-chan *prometheus.Desc {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(ch)
+		close(ch)
+	}()
+	return ch
+}
@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// TestFilterCollector keeps three of the AP error families, as an include list would,
+// and withholds the rest from both the descriptors and the scrape.
+func TestFilterCollector(t *testing.T) {
+	t.Parallel()
+
+	kept := []string{"wnc_ap_decryption_errors_total", "wnc_ap_fcs_errors_total", "wnc_ap_mic_errors_total"}
+	allowed := func(name string) bool { return slices.Contains(kept, name) }
+
+	registry := prometheus.NewPedanticRegistry()
+	for _, collector := range fixtureCollectors(t, fullFixtureSnapshot()) {
+		filtered := NewFilterCollector(collector, allowed)
+		for desc := range describe(filtered) {
+			if f, _ := declaredFamilies(collector).lookup(desc); !allowed(f.name) {
+				t.Errorf("%s is described but withheld", f.name)
+			}
+		}
+		registry.MustRegister(filtered)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	if !slices.Equal(names, kept) {
+		t.Errorf("gathered families = %v, want %v", names, kept)
+	}
+}
+
+// TestActiveModules disables the module whose families the filter withholds all of, so
+// the data type only it reads is not fetched, and keeps a module with one family left.
+func TestActiveModules(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Collectors: config.Collectors{
+		AP:             config.APCollectorModules{General: true, Errors: true},
+		MetricsInclude: []*regexp.Regexp{regexp.MustCompile(`^(?:wnc_ap_fcs_errors_total)$`)},
+	}}
+	if !slices.Contains(wnc.DataTypes(cfg.Collectors), "ap_oper_data") {
+		t.Fatal("ap_oper_data is not read by the general module")
+	}
+
+	active := ActiveModules(cfg)
+	if active.AP.General || !active.AP.Errors {
+		t.Errorf("ActiveModules() general = %t, errors = %t, want false, true", active.AP.General, active.AP.Errors)
+	}
+	if slices.Contains(wnc.DataTypes(active), "ap_oper_data") {
+		t.Error("ap_oper_data is read with every general family withheld")
+	}
+
+	entries, err := Catalog(cfg)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+	// The exporter's own families are never filtered.
+	var names []string
+	for _, entry := range entries {
+		if entry.Collector != catalogExporter {
+			names = append(names, entry.Name)
+		} else if entry.Name == "wnc_up" {
+			names = append(names, entry.Name)
+		}
+	}
+	if !slices.Equal(names, []string{"wnc_up", "wnc_ap_fcs_errors_total"}) {
+		t.Errorf("Catalog() families = %v, want wnc_up and wnc_ap_fcs_errors_total", names)
+	}
+}
+
+// TestRequiredDataTypes fetches only what the families left read: the general module
+// keeps its state family, so it stays enabled, but the data type its CPU and memory
+// families read is not fetched.
+func TestRequiredDataTypes(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Collectors: config.Collectors{
+		AP:             config.APCollectorModules{General: true},
+		MetricsInclude: []*regexp.Regexp{regexp.MustCompile(`^(?:wnc_ap_oper_state)$`)},
+	}}
+	if !ActiveModules(cfg).AP.General {
+		t.Fatal("the general module is disabled with one of its families kept")
+	}
+	if got := RequiredDataTypes(cfg); !slices.Equal(got, []string{wnc.DataAPCAPWAPData}) {
+		t.Errorf("RequiredDataTypes() = %v, want [%s]", got, wnc.DataAPCAPWAPData)
+	}
+
+	cfg.Collectors.MetricsInclude = nil
+	if got, want := RequiredDataTypes(cfg), wnc.DataTypes(cfg.Collectors); !slices.Equal(got, want) {
+		t.Errorf("RequiredDataTypes() without a filter = %v, want %v", got, want)
+	}
+}
+
+// TestRequiredDataTypes_KeepsEveryFamilyPublished includes each published family alone
+// and fails every data type RequiredDataTypes leaves out, as a refresh that never
+// requested them does: the family must still carry its series.
+func TestRequiredDataTypes_KeepsEveryFamilyPublished(t *testing.T) {
+	t.Parallel()
+
+	for name, published := range gatherAllCollectors(t, "") {
+		if !published {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{Collectors: config.Collectors{
+				MetricsInclude: []*regexp.Regexp{regexp.MustCompile("^(?:" + regexp.QuoteMeta(name) + ")$")},
+			}}
+			EnableAllModules(&cfg.Collectors)
+			required := RequiredDataTypes(cfg)
+
+			data := fullFixtureSnapshot()
+			for _, dataType := range allDataTypes {
+				if !slices.Contains(required, dataType) {
+					data.FetchErrors[dataType] = errors.New("not requested")
+				}
+			}
+			registry := prometheus.NewRegistry()
+			for _, collector := range fixtureCollectors(t, data) {
+				registry.MustRegister(collector)
+			}
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("Gather() error = %v", err)
+			}
+			if !slices.ContainsFunc(families, func(family *dto.MetricFamily) bool {
+				return family.GetName() == name && len(family.GetMetric()) > 0
+			}) {
+				t.Errorf("%s is withheld reading only %v", name, required)
+			}
+		})
+	}
+}
@@ -8,21 +8,37 @@ const (
 	labelName = "name" // Human-readable name
 
 	// AP-specific labels.
-	labelChannel   = "channel"    // CAPWAP tunnel channel (control, data)
-	labelEthMAC    = "eth_mac"    // AP Ethernet MAC address
-	labelIP        = "ip"         // AP IP address
-	labelModel     = "model"      // AP model number
-	labelProfile   = "profile"    // RRM profile a radio is judged against
-	labelRadio     = "radio"      // Radio slot identifier
-	labelSerial    = "serial"     // AP serial number
-	labelSWVersion = "sw_version" // AP software version
+	labelAccessCategory = "access_category" // WMM access category a radio queue serves
+	labelChannel        = "channel"         // CAPWAP tunnel channel (control, data), or an RF channel number
+	labelEthMAC         = "eth_mac"         // AP Ethernet MAC address
+	labelIP             = "ip"              // AP IP address
+	labelModel          = "model"           // AP model number
+	labelNeighbor       = "neighbor"        // Device a CDP or LLDP neighbor entry names
+	labelNeighborMAC    = "neighbor_mac"    // Radio MAC of an AP an RRM neighbor entry names
+	labelParentMAC      = "parent_mac"      // Radio MAC of the parent a mesh AP backhauls through
+	labelPlatform       = "platform"        // Platform a CDP neighbor reports
+	labelPort           = "port"            // Neighbor port an AP uplink is cabled to
+	labelProfile        = "profile"         // RRM profile a radio is judged against
+	labelRadio          = "radio"           // Radio slot identifier
+	labelSerial         = "serial"          // AP serial number
+	labelSWVersion      = "sw_version"      // AP software version
+	labelType           = "type"            // CleanAir interferer device type, without its si-dev-type- prefix
+
+	// labelLastDisconnectReason and labelLastRebootReason carry the spelling the join
+	// statistics give, the one wnc_ap_last_disconnect_reason and wnc_ap_last_reboot_reason
+	// number.
+	labelLastDisconnectReason = "last_disconnect_reason" // Disconnect reason a rejoin was counted under
+	labelLastRebootReason     = "last_reboot_reason"     // Reboot reason a reboot was counted under
 
 	// Client-specific labels.
-	labelAP       = "ap"       // Access Point name
-	labelIPv4     = "ipv4"     // Client IPv4 address
-	labelIPv6     = "ipv6"     // Client IPv6 address
-	labelUsername = "username" // Client authentication username
-	labelWLAN     = "wlan"     // WLAN SSID name
+	labelAP         = "ap"          // Access Point name
+	labelDeviceType = "device_type" // Device type the controller classifies a client as
+	labelIPv4       = "ipv4"        // Client IPv4 address
+	labelIPv6       = "ipv6"        // Client IPv6 address
+	labelOS         = "os"          // Operating system the controller classifies a client as
+	labelUsername   = "username"    // Client authentication username
+	labelVendor     = "vendor"      // Vendor the controller classifies a client as
+	labelWLAN       = "wlan"        // WLAN SSID name
 
 	// WLAN-specific labels.
 	labelID = "id" // WLAN identifier
@@ -31,10 +47,18 @@ const (
 	labelPhase         = "phase"          // Onboarding phase a client is held in
 	labelPolicyProfile = "policy_profile" // Policy profile a WLAN is bound to
 	labelPolicyTag     = "policy_tag"     // Policy tag carrying the binding
+	labelApplication   = "application"    // Application AVC classifies traffic as
+	labelDirection     = "direction"      // Traffic direction an AVC counter is kept for
+	labelPolicy        = "policy"         // ATF policy a WLAN's airtime is allotted by
 
 	// Controller-specific labels.
-	labelReason = "reason" // Reason a controller-wide counter is keyed by
+	labelAcctPort = "acct_port" // Accounting port of an AAA server
+	labelAddress  = "address"   // AAA server address
+	labelAuthPort = "auth_port" // Authentication port of an AAA server
+	labelGroup    = "group"     // AAA server group a server is counted under
+	labelReason   = "reason"    // Reason a controller-wide counter is keyed by
 
 	// Refresh health labels.
 	labelData = "data" // WNC data type identifier
+	labelCode = "code" // HTTP status a request was answered with, or its outcome
 )
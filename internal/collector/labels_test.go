@@ -0,0 +1,149 @@
+package collector
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// TestReservedLabels binds the labels a constant label may not reuse to the labels the
+// descriptors carry, both ways, so a new label constant cannot be missed there.
+func TestReservedLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	EnableAllModules(&cfg.Collectors)
+	cfg.Collectors.AP.InfoLabels = strings.Split(config.AvailableAPInfoLabels, ",")
+	cfg.Collectors.Client.InfoLabels = strings.Split(config.AvailableClientInfoLabels, ",")
+	cfg.Collectors.WLAN.InfoLabels = strings.Split(config.AvailableWLANInfoLabels, ",")
+	entries, err := Catalog(cfg)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	// The bucket label is the one the descriptors do not carry, and the build
+	// information takes no constant label.
+	used := []string{"le"}
+	for _, entry := range entries {
+		if entry.Name == "wnc_build_info" {
+			continue
+		}
+		for _, label := range entry.Labels {
+			if !slices.Contains(config.ReservedLabels, label) {
+				t.Errorf("%s label %q is not in config.ReservedLabels", entry.Name, label)
+			}
+			used = append(used, label)
+		}
+	}
+	for _, label := range config.ReservedLabels {
+		if !slices.Contains(used, label) {
+			t.Errorf("config.ReservedLabels lists %q, which no descriptor carries", label)
+		}
+	}
+}
+
+// TestReservedLabels_MatchLabelConstants binds the list to the label constants
+// themselves, where TestReservedLabels binds it to the descriptors: a constant added
+// for a family an enabled module does not register yet is still reserved.
+func TestReservedLabels_MatchLabelConstants(t *testing.T) {
+	t.Parallel()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "labels.go", nil, 0)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+
+	// The bucket label is the one no constant names.
+	declared := []string{"le"}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "label") || i >= len(spec.Values) {
+				continue
+			}
+			literal, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				t.Errorf("%s is not a string literal", name.Name)
+				continue
+			}
+			value, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				t.Fatalf("Unquote(%s) error = %v", literal.Value, err)
+			}
+			declared = append(declared, value)
+		}
+		return true
+	})
+
+	slices.Sort(declared)
+	reserved := slices.Sorted(slices.Values(config.ReservedLabels))
+	if !slices.Equal(declared, reserved) {
+		t.Errorf("label constants = %v, want config.ReservedLabels %v", declared, reserved)
+	}
+}
+
+// TestConstLabels adds the constant labels to every series of the modules, and leaves
+// the build information as it is.
+func TestConstLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Collectors: config.Collectors{
+		AP:          config.APCollectorModules{General: true, Radio: true},
+		Client:      config.ClientCollectorModules{General: true},
+		ConstLabels: map[string]string{"site": "tokyo"},
+	}}
+	registry := prometheus.NewPedanticRegistry()
+	c := &Collector{
+		registry:         registry,
+		registerer:       registry,
+		cfg:              cfg,
+		sharedDataSource: fixtureSource{data: fullFixtureSnapshot()},
+	}
+	c.Setup("test")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if len(families) < 2 {
+		t.Fatalf("Gather() = %d families, want module families besides wnc_build_info", len(families))
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var site string
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == "site" {
+					site = pair.GetValue()
+				}
+			}
+			want := "tokyo"
+			if family.GetName() == "wnc_build_info" {
+				want = ""
+			}
+			if site != want {
+				t.Errorf("%s site = %q, want %q", family.GetName(), site, want)
+			}
+		}
+	}
+
+	entries, err := Catalog(cfg)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name == "wnc_up" && !slices.Contains(entry.Labels, "site") {
+			t.Errorf("Catalog() wnc_up labels = %v, want site among them", entry.Labels)
+		}
+	}
+}
@@ -13,70 +13,123 @@ import (
 // a failed refresh yields a successful scrape carrying no series, which no alert
 // can detect.
 type RefreshCollector struct {
-	stats wnc.StatsProvider
+	stats    wnc.StatsProvider
+	families *familySet
 
 	upDesc               *prometheus.Desc
 	durationDesc         *prometheus.Desc
 	timestampDesc        *prometheus.Desc
 	errorsDesc           *prometheus.Desc
+	retriesDesc          *prometheus.Desc
 	itemsDesc            *prometheus.Desc
 	defaultsFallbackDesc *prometheus.Desc
+	restoredDesc         *prometheus.Desc
+	requestsDesc         *prometheus.Desc
+	requestDurationDesc  *prometheus.Desc
+	responseSizeDesc     *prometheus.Desc
 }
 
 // NewRefreshCollector creates a collector reporting WNC data refresh health.
 func NewRefreshCollector(stats wnc.StatsProvider) *RefreshCollector {
 	dataLabels := []string{labelData}
+	families := &familySet{}
 
 	return &RefreshCollector{
-		stats: stats,
-		upDesc: prometheus.NewDesc(
+		stats:    stats,
+		families: families,
+		upDesc: families.gauge(
 			"wnc_up",
 			"Whether the last completed WNC data refresh reached the controller. "+
 				"Not a claim about data completeness or about this scrape",
-			nil, nil,
+			nil,
 		),
-		durationDesc: prometheus.NewDesc(
+		durationDesc: families.gauge(
 			"wnc_refresh_duration_seconds",
 			"Duration of the last WNC data refresh attempt",
-			nil, nil,
+			nil,
 		),
-		timestampDesc: prometheus.NewDesc(
+		timestampDesc: families.gauge(
 			"wnc_refresh_success_timestamp_seconds",
 			"Start time of the refresh that produced the served snapshot. "+
 				"The controller updates the underlying data on its own schedule, "+
 				"so the true datum age is older than this value implies",
-			nil, nil,
+			nil,
 		),
-		errorsDesc: prometheus.NewDesc(
+		errorsDesc: families.counter(
 			"wnc_refresh_errors_total",
 			"WNC data fetch failures per data type since process start, "+
 				"including data types skipped because the refresh deadline expired",
-			dataLabels, nil,
+			dataLabels,
 		),
-		itemsDesc: prometheus.NewDesc(
+		retriesDesc: families.counter(
+			"wnc_refresh_retries_total",
+			"WNC data fetches tried again per data type since process start, after a "+
+				"dropped connection or a busy answer. A retry that succeeds raises this "+
+				"and not wnc_refresh_errors_total",
+			dataLabels,
+		),
+		itemsDesc: families.gauge(
 			"wnc_refresh_items",
 			"Items returned per data type by the last WNC data refresh. "+
 				"Recorded on success only, so an absent series means the fetch failed",
-			dataLabels, nil,
+			dataLabels,
 		),
-		defaultsFallbackDesc: prometheus.NewDesc(
+		defaultsFallbackDesc: families.counter(
 			"wnc_refresh_defaults_fallback_total",
 			"WLAN configuration fetches that fell back to a plain read since "+
 				"process start. The controller rejected the request for the values "+
 				"in force, so a config leaf it omits reads as 0 or is not reported",
-			nil, nil,
+			nil,
+		),
+		restoredDesc: families.gauge(
+			"wnc_snapshot_restored",
+			"Whether the served snapshot was restored from the snapshot file rather than "+
+				"refreshed by this process. It reads 1 from startup until the first "+
+				"successful refresh, and the data series meanwhile describe the controller "+
+				"as wnc_refresh_success_timestamp_seconds dates it",
+			nil,
+		),
+		requestsDesc: families.counter(
+			"wnc_requests_total",
+			"RESTCONF requests made to the controller per data type since process start, "+
+				"by the HTTP status they were answered with, and a failure with no HTTP "+
+				"answer as error. Retries and fallback re-reads are requests of their own",
+			[]string{labelData, labelCode},
+		),
+		requestDurationDesc: families.histogram(
+			"wnc_request_duration_seconds",
+			"Duration of each RESTCONF request made to the controller per data type, "+
+				"from sending it to the end of its answer",
+			dataLabels,
+		),
+		responseSizeDesc: families.histogram(
+			"wnc_response_size_bytes",
+			"Body size of each RESTCONF answer per data type, whatever its status. An "+
+				"answer cut short is not sized",
+			dataLabels,
 		),
 	}
 }
 
+// declaredFamilies returns the refresh health families. They describe the exporter's
+// own requests rather than a data type, so they declare none.
+func (c *RefreshCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
 // Describe implements prometheus.Collector.
 func (c *RefreshCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.upDesc
 	ch <- c.durationDesc
 	ch <- c.timestampDesc
 	ch <- c.errorsDesc
+	ch <- c.retriesDesc
 	ch <- c.itemsDesc
 	ch <- c.defaultsFallbackDesc
+	ch <- c.restoredDesc
+	ch <- c.requestsDesc
+	ch <- c.requestDurationDesc
+	ch <- c.responseSizeDesc
 }
 
 // Collect implements prometheus.Collector by reporting the refresh outcome.
@@ -101,13 +154,31 @@ func (c *RefreshCollector) Collect(ch chan<- prometheus.Metric) {
 		)
 	}
 
+	ch <- prometheus.MustNewConstMetric(
+		c.restoredDesc, prometheus.GaugeValue, boolToFloat64(stats.Restored))
 	ch <- prometheus.MustNewConstMetric(
 		c.defaultsFallbackDesc, prometheus.CounterValue, float64(stats.DefaultsFallbacks))
 
 	for name, count := range stats.Errors {
 		ch <- prometheus.MustNewConstMetric(c.errorsDesc, prometheus.CounterValue, float64(count), name)
 	}
+	for name, count := range stats.Retries {
+		ch <- prometheus.MustNewConstMetric(c.retriesDesc, prometheus.CounterValue, float64(count), name)
+	}
 	for name, count := range stats.Items {
 		ch <- prometheus.MustNewConstMetric(c.itemsDesc, prometheus.GaugeValue, float64(count), name)
 	}
+
+	for name, codes := range stats.Requests {
+		for code, count := range codes {
+			ch <- prometheus.MustNewConstMetric(
+				c.requestsDesc, prometheus.CounterValue, float64(count), name, code)
+		}
+	}
+	for name, h := range stats.RequestDurations {
+		ch <- prometheus.MustNewConstHistogram(c.requestDurationDesc, h.Count, h.Sum, h.Buckets, name)
+	}
+	for name, h := range stats.ResponseSizes {
+		ch <- prometheus.MustNewConstHistogram(c.responseSizeDesc, h.Count, h.Sum, h.Buckets, name)
+	}
 }
@@ -21,6 +21,8 @@ type refreshSample struct {
 	labels    map[string]string
 	value     float64
 	isCounter bool
+	// count is the sample count of a histogram, zero for any other type.
+	count uint64
 }
 
 // gatherRefresh registers the collector and indexes the samples by metric name.
@@ -54,6 +56,7 @@ func gatherRefresh(t *testing.T, stats wnc.RefreshStats) map[string][]refreshSam
 				labels:    labels,
 				value:     value,
 				isCounter: metric.Counter != nil,
+				count:     metric.GetHistogram().GetSampleCount(),
 			})
 		}
 		byName[family.GetName()] = samples
@@ -64,7 +67,7 @@ func gatherRefresh(t *testing.T, stats wnc.RefreshStats) map[string][]refreshSam
 func TestRefreshCollector_Describe(t *testing.T) {
 	t.Parallel()
 
-	ch := make(chan *prometheus.Desc, 10)
+	ch := make(chan *prometheus.Desc, 20)
 	NewRefreshCollector(stubStatsProvider{}).Describe(ch)
 	close(ch)
 
@@ -73,8 +76,8 @@ func TestRefreshCollector_Describe(t *testing.T) {
 		count++
 	}
 
-	if count != 6 {
-		t.Errorf("Describe() sent %d descriptors, want 6", count)
+	if count != 11 {
+		t.Errorf("Describe() sent %d descriptors, want 11", count)
 	}
 }
 
@@ -85,7 +88,8 @@ func TestRefreshCollector_ColdStart(t *testing.T) {
 	t.Parallel()
 
 	samples := gatherRefresh(t, wnc.RefreshStats{
-		Errors: map[string]int{"ap_capwap_data": 0, "client_traffic_stats": 0},
+		Errors:  map[string]int{"ap_capwap_data": 0, "client_traffic_stats": 0},
+		Retries: map[string]int{"ap_capwap_data": 0, "client_traffic_stats": 0},
 	})
 
 	up, ok := samples["wnc_up"]
@@ -112,6 +116,10 @@ func TestRefreshCollector_ColdStart(t *testing.T) {
 		t.Errorf("wnc_refresh_errors_total has %d series, want 2", len(errorSeries))
 	}
 
+	if retrySeries := samples["wnc_refresh_retries_total"]; len(retrySeries) != 2 {
+		t.Errorf("wnc_refresh_retries_total has %d series, want the 2 seeded", len(retrySeries))
+	}
+
 	if _, ok := samples["wnc_refresh_items"]; ok {
 		t.Error("wnc_refresh_items is present with nothing fetched, want it absent")
 	}
@@ -124,6 +132,13 @@ func TestRefreshCollector_ColdStart(t *testing.T) {
 	if fallback[0].value != 0 {
 		t.Errorf("wnc_refresh_defaults_fallback_total = %v, want 0", fallback[0].value)
 	}
+	restored, ok := samples["wnc_snapshot_restored"]
+	if !ok {
+		t.Fatal("wnc_snapshot_restored is absent on the first scrape, want it present at 0")
+	}
+	if restored[0].value != 0 {
+		t.Errorf("wnc_snapshot_restored = %v, want 0 with no snapshot restored", restored[0].value)
+	}
 }
 
 func TestRefreshCollector_AfterSuccessfulRefresh(t *testing.T) {
@@ -136,6 +151,7 @@ func TestRefreshCollector_AfterSuccessfulRefresh(t *testing.T) {
 		RefreshedAt: refreshedAt,
 		Duration:    1500 * time.Millisecond,
 		Errors:      map[string]int{"ap_capwap_data": 0},
+		Retries:     map[string]int{"ap_capwap_data": 3},
 		Items:       map[string]int{"ap_capwap_data": 4},
 		// Two, so the assertion fails for a descriptor wired to the wrong counter.
 		DefaultsFallbacks: 2,
@@ -173,6 +189,15 @@ func TestRefreshCollector_AfterSuccessfulRefresh(t *testing.T) {
 		t.Errorf("wnc_refresh_items{%s} = %q, want ap_capwap_data", labelData, got)
 	}
 
+	retries, ok := samples["wnc_refresh_retries_total"]
+	if !ok {
+		t.Fatal("wnc_refresh_retries_total is absent after a completed refresh")
+	}
+	if retries[0].value != 3 || !retries[0].isCounter {
+		t.Errorf("wnc_refresh_retries_total = %v (counter %v), want the counter at 3",
+			retries[0].value, retries[0].isCounter)
+	}
+
 	fallback, ok := samples["wnc_refresh_defaults_fallback_total"]
 	if !ok {
 		t.Fatal("wnc_refresh_defaults_fallback_total is absent after a completed refresh")
@@ -246,3 +271,83 @@ func TestRefreshCollector_TotalFailureKeepsFreshnessObservable(t *testing.T) {
 		t.Error("wnc_refresh_duration_seconds is absent after a failed attempt, want the attempt duration")
 	}
 }
+
+// TestRefreshCollector_RestoredSnapshot covers the scrapes between a warm restart and
+// its first refresh: the restored snapshot carries its own refresh time, and the
+// restored gauge is what tells that time from one this process produced.
+func TestRefreshCollector_RestoredSnapshot(t *testing.T) {
+	t.Parallel()
+
+	refreshedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	samples := gatherRefresh(t, wnc.RefreshStats{
+		RefreshedAt: refreshedAt,
+		Restored:    true,
+		Errors:      map[string]int{"ap_capwap_data": 0},
+	})
+
+	restored, ok := samples["wnc_snapshot_restored"]
+	if !ok {
+		t.Fatal("wnc_snapshot_restored is absent while a restored snapshot is served")
+	}
+	if restored[0].value != 1 {
+		t.Errorf("wnc_snapshot_restored = %v, want 1", restored[0].value)
+	}
+
+	timestamp, ok := samples["wnc_refresh_success_timestamp_seconds"]
+	if !ok {
+		t.Fatal("wnc_refresh_success_timestamp_seconds is absent, want the restored snapshot's")
+	}
+	if want := float64(refreshedAt.UnixNano()) / float64(time.Second); timestamp[0].value != want {
+		t.Errorf("wnc_refresh_success_timestamp_seconds = %v, want %v", timestamp[0].value, want)
+	}
+	if _, ok := samples["wnc_refresh_duration_seconds"]; ok {
+		t.Error("wnc_refresh_duration_seconds is present before this process attempted a refresh")
+	}
+}
+
+// TestRefreshCollector_RequestSeries pins the per-request families to the statistics
+// they are built from, including a data type the exporter never sized.
+func TestRefreshCollector_RequestSeries(t *testing.T) {
+	t.Parallel()
+
+	samples := gatherRefresh(t, wnc.RefreshStats{
+		Requests: map[string]map[string]int{
+			"client_common_oper_data": {"200": 4, "503": 2},
+			"aaa_radius_stats":        {"200": 1},
+		},
+		RequestDurations: map[string]wnc.Histogram{
+			"client_common_oper_data": {Count: 6, Sum: 3, Buckets: map[float64]uint64{1: 6}},
+			"aaa_radius_stats":        {Count: 1, Sum: 0.1, Buckets: map[float64]uint64{1: 1}},
+		},
+		ResponseSizes: map[string]wnc.Histogram{
+			"aaa_radius_stats": {Count: 1, Sum: 2048, Buckets: map[float64]uint64{4096: 1}},
+		},
+	})
+
+	requests := samples["wnc_requests_total"]
+	if len(requests) != 3 {
+		t.Fatalf("wnc_requests_total has %d series, want 3", len(requests))
+	}
+	for _, sample := range requests {
+		if sample.labels[labelData] == "client_common_oper_data" && sample.labels[labelCode] == "503" &&
+			sample.value != 2 {
+			t.Errorf("wnc_requests_total{code=503} = %v, want 2", sample.value)
+		}
+		if !sample.isCounter {
+			t.Error("wnc_requests_total is not a counter, want one")
+		}
+	}
+
+	if got := len(samples["wnc_request_duration_seconds"]); got != 2 {
+		t.Errorf("wnc_request_duration_seconds has %d series, want 2", got)
+	}
+
+	sizes := samples["wnc_response_size_bytes"]
+	if len(sizes) != 1 {
+		t.Fatalf("wnc_response_size_bytes has %d series, want 1: an unsized data type must not "+
+			"report an empty histogram", len(sizes))
+	}
+	if sizes[0].labels[labelData] != "aaa_radius_stats" || sizes[0].count != 1 {
+		t.Errorf("wnc_response_size_bytes = %+v, want one observation for aaa_radius_stats", sizes[0])
+	}
+}
@@ -0,0 +1,267 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the RRM collector, whose series summarize the radios sharing a channel.
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/rrm"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// RRMMetrics represents which RRM metrics are enabled.
+type RRMMetrics struct {
+	Channels bool
+}
+
+// RRMCollector implements prometheus.Collector for series keyed by band and channel
+// rather than by radio. It reads the same lists the AP radio module does and folds them
+// in the exporter, so the contention on a channel is one series instead of a join over
+// every radio of the controller.
+type RRMCollector struct {
+	metrics   RRMMetrics
+	families  *familySet
+	src       wnc.APSource
+	rrmSrc    wnc.RRMSource
+	clientSrc wnc.ClientSource
+
+	channelAPsDesc            *prometheus.Desc
+	channelClientsDesc        *prometheus.Desc
+	channelUtilizationDesc    *prometheus.Desc
+	channelUtilizationMaxDesc *prometheus.Desc
+	channelNoiseFloorDesc     *prometheus.Desc
+}
+
+// NewRRMCollector creates a new RRM collector.
+func NewRRMCollector(
+	src wnc.APSource, rrmSrc wnc.RRMSource, clientSrc wnc.ClientSource, metrics RRMMetrics,
+) *RRMCollector {
+	families := &familySet{}
+	collector := &RRMCollector{
+		metrics:   metrics,
+		families:  families,
+		src:       src,
+		rrmSrc:    rrmSrc,
+		clientSrc: clientSrc,
+	}
+
+	// The channel is the primary one and is keyed together with the band, because 6 GHz
+	// channel numbers restart at 1 and collide with 2.4 GHz.
+	channelLabels := []string{labelBand, labelChannel}
+
+	if metrics.Channels {
+		collector.channelAPsDesc = families.gauge(
+			"wnc_rrm_channel_aps",
+			"APs with a radio operating on this primary channel of this band. A wide channel "+
+				"is counted on its primary channel only, so two radios whose channels overlap "+
+				"can be counted on different channels",
+			channelLabels, wnc.DataAPRadioOperData,
+		)
+		collector.channelClientsDesc = families.gauge(
+			"wnc_rrm_channel_clients",
+			"Clients in the run state associated to a radio operating on this channel, the "+
+				"sum of wnc_ap_clients over those radios. Withheld on every channel when the "+
+				"client list or the AP name map cannot be read, rather than reported as 0",
+			channelLabels, wnc.DataAPRadioOperData, wnc.DataClientCommonOperData, wnc.DataAPNameMACMap,
+		)
+		collector.channelUtilizationDesc = families.gauge(
+			"wnc_rrm_channel_utilization_ratio_avg",
+			"Mean of the channel utilization (0-1) RRM measures on each radio operating on "+
+				"this channel. Radios RRM has no load reading for are left out of the mean, and "+
+				"a channel with none is withheld",
+			channelLabels, wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
+		)
+		collector.channelUtilizationMaxDesc = families.gauge(
+			"wnc_rrm_channel_utilization_ratio_max",
+			"Highest channel utilization (0-1) RRM measures on a radio operating on this "+
+				"channel, over the same radios as the mean",
+			channelLabels, wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
+		)
+		collector.channelNoiseFloorDesc = families.gauge(
+			"wnc_rrm_channel_noise_floor_dbm_avg",
+			"Mean of the noise in dBm RRM measures on this channel from each radio operating "+
+				"on it. Radios whose noise list carries no entry for the channel are left out "+
+				"of the mean, and a channel with none is withheld",
+			channelLabels, wnc.DataAPRadioOperData, wnc.DataRRMMeasurement,
+		)
+	}
+
+	return collector
+}
+
+// declaredFamilies returns the families of the enabled modules.
+func (c *RRMCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
+// Describe implements prometheus.Collector.
+func (c *RRMCollector) Describe(ch chan<- *prometheus.Desc) {
+	if c.metrics.Channels {
+		ch <- c.channelAPsDesc
+		ch <- c.channelClientsDesc
+		ch <- c.channelUtilizationDesc
+		ch <- c.channelUtilizationMaxDesc
+		ch <- c.channelNoiseFloorDesc
+	}
+}
+
+// Collect implements prometheus.Collector by retrieving RRM data from WNC.
+func (c *RRMCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	if !c.metrics.Channels {
+		return
+	}
+
+	// The radio list is what every channel is found from, so nothing is published
+	// without it: an empty summary would read as a controller with every channel idle.
+	radios, err := c.src.GetRadioData(ctx)
+	if err != nil {
+		slog.Debug("Failed to get radio data for RRM channel metrics", "error", err)
+		return
+	}
+
+	measurements, err := c.rrmSrc.GetRRMMeasurements(ctx)
+	if err != nil {
+		slog.Debug("Failed to get RRM measurements for RRM channel metrics", "error", err)
+	}
+
+	for key, summary := range summarizeChannels(
+		radios, buildRRMMeasurementsMap(measurements), c.readClientCounts(ctx),
+	) {
+		c.collectChannel(ch, key, summary)
+	}
+}
+
+// readClientCounts returns the run-state client count per radio, or nil when either of
+// the two lists it is built from cannot be read. A partial count reads as a channel
+// nobody is associated on, so it is withheld instead, as the AP radio module does.
+func (c *RRMCollector) readClientCounts(ctx context.Context) map[string]map[int]int {
+	clientData, err := c.clientSrc.GetClientData(ctx)
+	if err != nil {
+		slog.Debug("Failed to get client data for RRM channel client counts", "error", err)
+		return nil
+	}
+
+	nameMACMaps, err := c.src.ListNameMACMaps(ctx)
+	if err != nil {
+		slog.Debug("Failed to get AP name to MAC mapping for RRM channel client counts", "error", err)
+		return nil
+	}
+
+	return buildRadioClientCountsMap(clientData, nameMACMaps)
+}
+
+// collectChannel publishes the summary of one band and channel. The three readings
+// taken from RRM are each withheld when no radio on the channel carried one.
+func (c *RRMCollector) collectChannel(ch chan<- prometheus.Metric, key channelKey, summary *channelSummary) {
+	labels := []string{key.band, strconv.Itoa(key.channel)}
+
+	metrics := []Float64Metric{{c.channelAPsDesc, float64(len(summary.aps))}}
+
+	if summary.clientsKnown {
+		metrics = append(metrics, Float64Metric{c.channelClientsDesc, float64(summary.clients)})
+	}
+
+	if summary.utilizationCount > 0 {
+		metrics = append(metrics,
+			Float64Metric{
+				c.channelUtilizationDesc,
+				summary.utilizationSum / float64(summary.utilizationCount),
+			},
+			Float64Metric{c.channelUtilizationMaxDesc, summary.utilizationMax},
+		)
+	}
+
+	if summary.noiseCount > 0 {
+		metrics = append(metrics, Float64Metric{
+			c.channelNoiseFloorDesc,
+			summary.noiseSum / float64(summary.noiseCount),
+		})
+	}
+
+	for _, metric := range metrics {
+		ch <- prometheus.MustNewConstMetric(metric.Desc, prometheus.GaugeValue, metric.Value, labels...)
+	}
+}
+
+// channelKey identifies a channel across the controller.
+type channelKey struct {
+	band    string
+	channel int
+}
+
+// channelSummary folds the radios operating on one channel. aps is a set because an AP
+// with two radios in one band, such as a dual 5 GHz model, is one AP contending there.
+type channelSummary struct {
+	aps map[string]bool
+
+	clients      int
+	clientsKnown bool
+
+	utilizationSum   float64
+	utilizationMax   float64
+	utilizationCount int
+
+	noiseSum   float64
+	noiseCount int
+}
+
+// summarizeChannels groups the radios by the band and primary channel they operate on.
+//
+// A slot that is not a radio, a radio reporting no channel, which is how one in monitor
+// or sniffer mode arrives, and a radio whose band cannot be named are left out: the
+// last would fold channels of different bands into one series.
+func summarizeChannels(
+	radios []ap.RadioOperData,
+	measurements map[string]*rrm.RRMMeasurement,
+	clientCounts map[string]map[int]int,
+) map[channelKey]*channelSummary {
+	summaries := make(map[channelKey]*channelSummary)
+
+	for i := range radios {
+		radio := &radios[i]
+		if !isRadio(radio) || radio.PhyHtCfg == nil || radio.PhyHtCfg.CfgData.CurrFreq <= 0 {
+			continue
+		}
+
+		band := APRadioBand(radio)
+		if band == BandUnknown {
+			continue
+		}
+
+		key := channelKey{band: band, channel: radio.PhyHtCfg.CfgData.CurrFreq}
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &channelSummary{aps: make(map[string]bool), clientsKnown: clientCounts != nil}
+			summaries[key] = summary
+		}
+
+		summary.aps[radio.WtpMAC] = true
+		summary.clients += clientCounts[radio.WtpMAC][radio.RadioSlotID]
+
+		rrmData, found := measurements[radio.WtpMAC+":"+strconv.Itoa(radio.RadioSlotID)]
+		if !found {
+			continue
+		}
+
+		if rrmData.Load != nil {
+			utilization := float64(rrmData.Load.CcaUtilPercentage) / 100
+			summary.utilizationSum += utilization
+			summary.utilizationMax = max(summary.utilizationMax, utilization)
+			summary.utilizationCount++
+		}
+
+		if noise, found := noiseOnCurrentChannel(rrmData, radio); found {
+			summary.noiseSum += float64(noise)
+			summary.noiseCount++
+		}
+	}
+
+	return summaries
+}
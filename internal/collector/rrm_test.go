@@ -0,0 +1,223 @@
+package collector
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/rrm"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// gatherChannels collects the RRM collector over the given snapshot and indexes every
+// sample by metric name, then by "band|channel".
+func gatherChannels(t *testing.T, data *wnc.WNCDataCache) map[string]map[string]float64 {
+	t.Helper()
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewRRMCollector(
+		wnc.NewAPSource(src), wnc.NewRRMSource(src), wnc.NewClientSource(src),
+		RRMMetrics{Channels: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion that
+	// every channel is summarized once however many radios it carries.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	values := make(map[string]map[string]float64, len(families))
+	for _, family := range families {
+		byChannel := make(map[string]float64, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			byChannel[labels[labelBand]+"|"+labels[labelChannel]] = metric.GetGauge().GetValue()
+		}
+		values[family.GetName()] = byChannel
+	}
+	return values
+}
+
+// TestRRMCollector_Describe pins the descriptor count per module, so a series added to
+// the collector without a module guard shows up here.
+func TestRRMCollector_Describe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		metrics     RRMMetrics
+		expectDescs int
+	}{
+		{"No modules enabled", RRMMetrics{}, 0},
+		// aps, clients, utilization avg and max, noise avg
+		{"Channels module only", RRMMetrics{Channels: true}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector := NewRRMCollector(nil, nil, nil, tt.metrics)
+
+			ch := make(chan *prometheus.Desc, 10)
+			collector.Describe(ch)
+			close(ch)
+
+			count := 0
+			for range ch {
+				count++
+			}
+			if count != tt.expectDescs {
+				t.Errorf("Describe() sent %d descriptors, want %d", count, tt.expectDescs)
+			}
+		})
+	}
+}
+
+// channelFixture returns a snapshot of four APs whose radios exercise every grouping
+// rule of the channels module: two APs sharing a 5 GHz channel, one of them twice, a
+// 6 GHz radio on a channel number 2.4 GHz also uses, and the three radios the summary
+// leaves out.
+func channelFixture() *wnc.WNCDataCache {
+	const (
+		apA, apB, apC, apD = "aa:00:00:00:00:01", "aa:00:00:00:00:02", "aa:00:00:00:00:03", "aa:00:00:00:00:04"
+	)
+
+	radio := func(mac string, slot int, band string, channel int) ap.RadioOperData {
+		return ap.RadioOperData{
+			WtpMAC: mac, RadioSlotID: slot, CurrentActiveBand: band, OperState: APRadioStateUp,
+			PhyHtCfg: &ap.PhyHtCfg{CfgData: ap.PhyHtCfgData{CurrFreq: channel}},
+		}
+	}
+	measurement := func(mac string, slot, util, channel, noise int) rrm.RRMMeasurement {
+		return rrm.RRMMeasurement{
+			WtpMAC: mac, RadioSlotID: slot,
+			Load: &rrm.Load{CcaUtilPercentage: util},
+			Noise: &rrm.Noise{Noise: rrm.NoiseData{NoiseData: []rrm.NoiseDataItem{
+				{Chan: channel, Noise: noise},
+			}}},
+		}
+	}
+
+	data := fullFixtureSnapshot()
+	data.RadioOperData = []ap.RadioOperData{
+		radio(apA, 0, "dot11-2-dot-4-ghz-band", 1),
+		radio(apA, 1, "dot11-5-ghz-band", 36),
+		// A dual 5 GHz AP with both radios on one channel is one AP there.
+		radio(apA, 2, "dot11-5-ghz-band", 36),
+		radio(apB, 1, "dot11-5-ghz-band", 36),
+		radio(apC, 2, "dot11-6-ghz-band", 1),
+		// A radio in monitor mode, one whose band cannot be named, and a slot that is
+		// not a radio.
+		radio(apD, 0, "dot11-2-dot-4-ghz-band", 0),
+		radio(apD, 1, "dot11-invalid-band", 36),
+		{WtpMAC: apD, RadioSlotID: 3, RadioType: "radio-remote-lan"},
+	}
+	data.RRMMeasurements = []rrm.RRMMeasurement{
+		measurement(apA, 1, 20, 36, -90),
+		measurement(apA, 2, 60, 36, -80),
+		// apB's radio is left unmeasured, so it must not pull the mean down.
+		measurement(apC, 2, 10, 1, -95),
+		// The monitor and unnamed-band radios carry readings that must not be counted.
+		measurement(apD, 0, 99, 1, -10),
+		measurement(apD, 1, 99, 36, -10),
+	}
+	data.NameMACMaps = []ap.ApNameMACMap{
+		{WtpName: "ap-a", WtpMAC: apA}, {WtpName: "ap-b", WtpMAC: apB},
+		{WtpName: "ap-c", WtpMAC: apC}, {WtpName: "ap-d", WtpMAC: apD},
+	}
+	data.CommonOperData = []client.CommonOperData{
+		{ClientMAC: "00:00:00:00:00:01", ApName: "ap-a", MsApSlotID: 1, CoState: ClientStatusRun},
+		{ClientMAC: "00:00:00:00:00:02", ApName: "ap-a", MsApSlotID: 2, CoState: ClientStatusRun},
+		{ClientMAC: "00:00:00:00:00:03", ApName: "ap-b", MsApSlotID: 1, CoState: ClientStatusRun},
+		{ClientMAC: "00:00:00:00:00:04", ApName: "ap-d", MsApSlotID: 1, CoState: ClientStatusRun},
+	}
+	return data
+}
+
+// TestRRMChannelsModule_GroupsByBandAndChannel pins the fold: the band keeps 2.4 GHz and
+// 6 GHz channel 1 apart, an AP counts once per channel, and the means cover only the
+// radios that carried a reading.
+func TestRRMChannelsModule_GroupsByBandAndChannel(t *testing.T) {
+	t.Parallel()
+
+	got := gatherChannels(t, channelFixture())
+
+	want := map[string]map[string]float64{
+		"wnc_rrm_channel_aps": {
+			Band24GHz + "|1": 1, Band5GHz + "|36": 2, Band6GHz + "|1": 1,
+		},
+		"wnc_rrm_channel_clients": {
+			Band24GHz + "|1": 0, Band5GHz + "|36": 3, Band6GHz + "|1": 0,
+		},
+		"wnc_rrm_channel_utilization_ratio_avg": {
+			Band5GHz + "|36": 0.40, Band6GHz + "|1": 0.10,
+		},
+		"wnc_rrm_channel_utilization_ratio_max": {
+			Band5GHz + "|36": 0.60, Band6GHz + "|1": 0.10,
+		},
+		"wnc_rrm_channel_noise_floor_dbm_avg": {
+			Band5GHz + "|36": -85, Band6GHz + "|1": -95,
+		},
+	}
+
+	for name, wantValues := range want {
+		if !maps.Equal(got[name], wantValues) {
+			t.Errorf("%s = %v, want %v", name, got[name], wantValues)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("gathered %d families, want %d", len(got), len(want))
+	}
+}
+
+// TestRRMChannelsModule_WithholdsWhatItCannotRead covers each read failing alone. The
+// radio list is what every channel is found from, so losing it withholds everything;
+// losing either client count input withholds the client series and nothing else.
+func TestRRMChannelsModule_WithholdsWhatItCannotRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failed string
+		want   []string
+	}{
+		{typeAPRadioOperData, nil},
+		{typeRRMMeasurement, []string{"wnc_rrm_channel_aps", "wnc_rrm_channel_clients"}},
+		{typeClientCommonOperData, []string{
+			"wnc_rrm_channel_aps", "wnc_rrm_channel_utilization_ratio_avg",
+			"wnc_rrm_channel_utilization_ratio_max", "wnc_rrm_channel_noise_floor_dbm_avg",
+		}},
+		{typeAPNameMACMap, []string{
+			"wnc_rrm_channel_aps", "wnc_rrm_channel_utilization_ratio_avg",
+			"wnc_rrm_channel_utilization_ratio_max", "wnc_rrm_channel_noise_floor_dbm_avg",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.failed, func(t *testing.T) {
+			t.Parallel()
+
+			data := channelFixture()
+			data.FetchErrors[tt.failed] = errors.New("fetch failed")
+
+			got := gatherChannels(t, data)
+			if len(got) != len(tt.want) {
+				t.Errorf("gathered %d families, want %d: %v", len(got), len(tt.want), got)
+			}
+			for _, name := range tt.want {
+				if _, ok := got[name]; !ok {
+					t.Errorf("%s is withheld, want it published", name)
+				}
+			}
+		})
+	}
+}
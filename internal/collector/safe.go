@@ -24,6 +24,11 @@ func NewSafeCollector(base prometheus.Collector, name string) *SafeCollector {
 	}
 }
 
+// declaredFamilies passes the declaration of the base collector through.
+func (c *SafeCollector) declaredFamilies() *familySet {
+	return declaredFamilies(c.base)
+}
+
 // Describe implements prometheus.Collector interface by delegating to base collector.
 func (c *SafeCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.base.Describe(ch)
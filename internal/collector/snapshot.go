@@ -0,0 +1,24 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds what lets a module that compares snapshots compare each one once.
+package collector
+
+import "time"
+
+// snapshotClock remembers the refresh a stateful module last compared. A scrape reads
+// whatever snapshot is being served, so two scrapes can read the same one, and a module
+// that compared per scrape would count a snapshot twice or against itself. Keyed on the
+// refresh time, each snapshot is compared once, and in the order the refreshes ran.
+type snapshotClock struct {
+	last time.Time
+}
+
+// advance reports whether the snapshot refreshed at at is newer than the last one
+// compared, and records it as compared when it is. A snapshot no newer, such as a
+// restored one older than what was already compared, is left alone.
+func (c *snapshotClock) advance(at time.Time) bool {
+	if !at.After(c.last) {
+		return false
+	}
+	c.last = at
+	return true
+}
@@ -16,6 +16,13 @@ const (
 const (
 	APRadioStateUp      = "radio-up"
 	APAdminStateEnabled = "enabled"
+	APPowerStatusFull   = "full-power"
+	APMeshRoleRoot      = "root-ap"
+)
+
+// AAA server state constants.
+const (
+	AAAServerStateUp = "up"
 )
 
 type WirelessProtocol int
@@ -75,6 +75,29 @@ func TestAllCollectors_GaugeValuesMatchLeaves(t *testing.T) {
 		{"wnc_ap_channel_number", fixtureChannel},
 		{"wnc_ap_channel_width_mhz", 40},
 
+		// The AP's second CDP entry carries other link settings, so reading past the
+		// first one changes both values.
+		{"wnc_ap_uplink_speed_mbps", 2500},
+		{"wnc_ap_uplink_full_duplex", 1},
+		{"wnc_ap_uplink_power_full", 0},
+
+		// The mesh module. The root AP sorts first, so its role and hop count are the
+		// first samples; the link readings come from the mesh AP alone.
+		{"wnc_ap_mesh_info", 1},
+		{"wnc_ap_mesh_root", 1},
+		{"wnc_ap_mesh_hops", 0},
+		{"wnc_ap_mesh_backhaul_channel", 36},
+		{"wnc_ap_mesh_link_snr_db", 41},
+		{"wnc_ap_mesh_link_rate_mbps", 866},
+
+		// The qos module, one queue with a distinct value per counter.
+		{"wnc_ap_qos_transmitted_frames_total", 8401},
+		{"wnc_ap_qos_queue_drops_total", 8402},
+
+		// The fixture radio's neighbors carry channels sorting against their strength,
+		// so the first sample is the strongest one only if the ranking keeps it.
+		{"wnc_ap_neighbor_rssi_dbm", -52},
+
 		// The four load leaves are whole numbers the collector divides by one
 		// hundred, and each is distinct so that reading a sibling changes the ratio.
 		{"wnc_ap_channel_utilization_ratio", 0.30},
@@ -87,6 +110,13 @@ func TestAllCollectors_GaugeValuesMatchLeaves(t *testing.T) {
 		{"wnc_ap_noise_floor_dbm", -90},
 
 		{"wnc_ap_clients", 1},
+
+		// The fixture's one radio is the whole of its channel, so each summary carries
+		// that radio's own reading, and the noise one must be the operating channel's.
+		{"wnc_rrm_channel_aps", 1},
+		{"wnc_rrm_channel_clients", 1},
+		{"wnc_rrm_channel_utilization_ratio_avg", 0.30},
+		{"wnc_rrm_channel_noise_floor_dbm_avg", -90},
 		{"wnc_ap_coverage_failed_clients", 7},
 		// The operating channel's row, not the padding row and not the neighboring
 		// channel the fixture table also carries.
@@ -97,6 +127,11 @@ func TestAllCollectors_GaugeValuesMatchLeaves(t *testing.T) {
 		// The instant of that same row. The neighboring row's is a day later and the
 		// padding row's is the epoch sentinel, so reading either lands elsewhere.
 		{"wnc_ap_last_air_quality_timestamp_seconds", 1768953600},
+		// The two leaves of one CleanAir device carry distinct numbers, so reading the
+		// sibling changes the value, and the fixture's one device is a count of one.
+		{"wnc_ap_interferer_devices", 1},
+		{"wnc_ap_interferer_severity_max", 46},
+		{"wnc_ap_interferer_duty_cycle_ratio_max", 0.35},
 		// The band-keyed rows are sorted by label value, so these read the 2.4 GHz row.
 		// Each leaf of that row carries a distinct number, so a descriptor reading its
 		// neighbor reports a value these pins do not expect.
@@ -119,6 +154,8 @@ func TestAllCollectors_GaugeValuesMatchLeaves(t *testing.T) {
 		{"wnc_rrm_last_dca_run_timestamp_seconds", 1768435200},
 
 		{"wnc_wlan_clients", 1},
+		// The same run-state client, folded by its classification.
+		{"wnc_client_devices", 1},
 		{"wnc_wlan_session_timeout_seconds", 1800},
 		{"wnc_wlan_policy_binding", 1},
 
@@ -142,8 +179,8 @@ func TestAllCollectors_GaugeValuesMatchLeaves(t *testing.T) {
 		{"wnc_ap_last_discovery_success_timestamp_seconds", 1767744000},
 		{"wnc_ap_last_discovery_failure_timestamp_seconds", 1767830400},
 
-		// The twelve enumeration families. Each value is the number the controller's own
-		// enumeration assigns the spelling the fixture carries, and the twelve are
+		// The thirteen enumeration families. Each value is the number the controller's own
+		// enumeration assigns the spelling the fixture carries, and the thirteen are
 		// distinct wherever two of them could be exchanged, so a descriptor or a table
 		// wired to a neighbor reports a number no row here expects.
 		{"wnc_ap_last_discovery_failure_reason", 14},
@@ -249,12 +286,37 @@ func TestAllCollectors_CounterValuesMatchLeaves(t *testing.T) {
 		{"wnc_controller_client_ap_auth_dot11i_fast_roams_total", 6202},
 		{"wnc_controller_client_ap_auth_dot11i_slow_roams_total", 6203},
 
+		// The nine AAA counters, each a distinct leaf of one record, and the round-trip
+		// time, which the controller reports in milliseconds.
+		{"wnc_controller_aaa_auth_requests_total", 6301},
+		{"wnc_controller_aaa_auth_accepts_total", 6302},
+		{"wnc_controller_aaa_auth_rejects_total", 6303},
+		{"wnc_controller_aaa_auth_timeouts_total", 6304},
+		{"wnc_controller_aaa_auth_retransmits_total", 6305},
+		{"wnc_controller_aaa_acct_requests_total", 6311},
+		{"wnc_controller_aaa_acct_responses_total", 6312},
+		{"wnc_controller_aaa_acct_timeouts_total", 6313},
+		{"wnc_controller_aaa_acct_retransmits_total", 6314},
+		{"wnc_controller_aaa_response_time_seconds", 0.025},
+		{"wnc_controller_aaa_server_up", 1},
+
 		// Bytes in both directions, from the leaf the controller reports as a string.
 		// The record's other counts each carry their own value, so reading one of them
 		// lands elsewhere. Four are the onboarding phases, pinned per phase in
 		// TestWLANCollector_OnboardingPhasesMatchLeaves; the run count and the
 		// random-MAC count remain unpublished.
 		{"wnc_wlan_data_usage_bytes_total", 7101},
+
+		// The applications of the WLAN, ranked by bytes. The heavier one is kept and sorts
+		// first; the folded one shows up only in the other gauges.
+		{"wnc_wlan_application_bytes_total", 7203},
+		{"wnc_wlan_application_packets_total", 7204},
+		{"wnc_wlan_application_other_bytes", 7201},
+		{"wnc_wlan_application_other_packets", 7202},
+
+		// The atf module, converted from percent and from microseconds.
+		{"wnc_wlan_atf_airtime_allocation_ratio", 0.4},
+		{"wnc_wlan_atf_airtime_seconds_total", 7.301},
 	}
 
 	assertValues(t, values, tests)
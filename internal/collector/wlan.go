@@ -16,20 +16,28 @@ import (
 
 // WLANMetrics represents the configuration for WLAN metrics.
 type WLANMetrics struct {
-	General    bool
-	Traffic    bool
-	Config     bool
-	Info       bool
-	InfoLabels []string
+	General      bool
+	Traffic      bool
+	Config       bool
+	Applications bool
+	ATF          bool
+	Info         bool
+	InfoLabels   []string
+
+	// ApplicationsTopN bounds the applications the applications module publishes per WLAN.
+	ApplicationsTopN int
 }
 
 // WLANCollector implements prometheus.Collector for WLAN metrics.
 type WLANCollector struct {
 	metrics        WLANMetrics
+	families       *familySet
 	infoDesc       *prometheus.Desc
 	infoLabelNames []string
 	src            wnc.WLANSource
 	clientSrc      wnc.ClientSource
+	applications   *wlanApplicationDescs
+	atf            *wlanATFDescs
 
 	enabledDesc               *prometheus.Desc
 	clientCountDesc           *prometheus.Desc
@@ -56,141 +64,151 @@ type WLANCollector struct {
 
 // NewWLANCollector creates a new WLAN collector.
 func NewWLANCollector(src wnc.WLANSource, clientSrc wnc.ClientSource, metrics WLANMetrics) *WLANCollector {
+	families := &familySet{}
 	collector := &WLANCollector{
 		src:       src,
 		clientSrc: clientSrc,
 		metrics:   metrics,
+		families:  families,
 	}
 
 	labels := []string{labelID}
 
+	if metrics.Applications {
+		collector.applications = newWLANApplicationDescs(families, metrics.ApplicationsTopN)
+	}
+
+	if metrics.ATF {
+		collector.atf = newWLANATFDescs(families)
+	}
+
 	if metrics.General {
-		collector.enabledDesc = prometheus.NewDesc(
+		collector.enabledDesc = families.gauge(
 			"wnc_wlan_enabled",
 			"WLAN status (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
 	}
 
 	if metrics.Traffic {
-		collector.clientCountDesc = prometheus.NewDesc(
+		collector.clientCountDesc = families.gauge(
 			"wnc_wlan_clients",
 			"Number of clients in the run state on this WLAN",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataClientCommonOperData,
 		)
-		collector.onboardingDesc = prometheus.NewDesc(
+		collector.onboardingDesc = families.gauge(
 			"wnc_wlan_onboarding_clients",
 			"Number of clients on this WLAN currently held in one onboarding phase, "+
 				"short of the run state wnc_wlan_clients counts",
-			[]string{labelID, labelPhase}, nil,
+			[]string{labelID, labelPhase}, wnc.DataWLANCfgEntries, wnc.DataWLANClientStats,
 		)
-		collector.dataUsageDesc = prometheus.NewDesc(
+		collector.dataUsageDesc = families.counter(
 			"wnc_wlan_data_usage_bytes_total",
 			"Bytes transferred on this WLAN in both directions, as the controller totals "+
 				"them. It keeps the bytes of clients that have since disconnected, so it is "+
 				"not the sum of the per-client byte counters",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANClientStats,
 		)
 	}
 
 	if metrics.Config {
-		collector.authPskDesc = prometheus.NewDesc(
+		collector.authPskDesc = families.gauge(
 			"wnc_wlan_auth_psk_enabled",
 			"PSK authentication enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.authDot1xDesc = prometheus.NewDesc(
+		collector.authDot1xDesc = families.gauge(
 			"wnc_wlan_auth_dot1x_enabled",
 			"802.1x authentication enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.authDot1xSha256Desc = prometheus.NewDesc(
+		collector.authDot1xSha256Desc = families.gauge(
 			"wnc_wlan_auth_dot1x_sha256_enabled",
 			"802.1x SHA256 authentication enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.wpa2EnabledDesc = prometheus.NewDesc(
+		collector.wpa2EnabledDesc = families.gauge(
 			"wnc_wlan_wpa2_enabled",
 			"WPA2 support enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.wpa3EnabledDesc = prometheus.NewDesc(
+		collector.wpa3EnabledDesc = families.gauge(
 			"wnc_wlan_wpa3_enabled",
 			"WPA3 support enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.sessionTimeoutDesc = prometheus.NewDesc(
+		collector.sessionTimeoutDesc = families.gauge(
 			"wnc_wlan_session_timeout_seconds",
 			"Session timeout duration in seconds, 0 when the controller omits the leaf",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
-		collector.loadBalanceDesc = prometheus.NewDesc(
+		collector.loadBalanceDesc = families.gauge(
 			"wnc_wlan_load_balance_enabled",
 			"Load balancing enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.wlan11kNeighDesc = prometheus.NewDesc(
+		collector.wlan11kNeighDesc = families.gauge(
 			"wnc_wlan_11k_neighbor_list_enabled",
 			"802.11k neighbor list enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.clientSteeringDesc = prometheus.NewDesc(
+		collector.clientSteeringDesc = families.gauge(
 			"wnc_wlan_client_steering_enabled",
 			"6GHz client steering enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.centralSwitchingDesc = prometheus.NewDesc(
+		collector.centralSwitchingDesc = families.gauge(
 			"wnc_wlan_central_switching_enabled",
 			"Central switching enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
-		collector.centralAuthenticationDesc = prometheus.NewDesc(
+		collector.centralAuthenticationDesc = families.gauge(
 			"wnc_wlan_central_authentication_enabled",
 			"Central authentication enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
-		collector.centralDHCPDesc = prometheus.NewDesc(
+		collector.centralDHCPDesc = families.gauge(
 			"wnc_wlan_central_dhcp_enabled",
 			"Central DHCP enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
-		collector.centralAssocEnableDesc = prometheus.NewDesc(
+		collector.centralAssocEnableDesc = families.gauge(
 			"wnc_wlan_central_association_enabled",
 			"Central association enabled (0=disabled or not reported, 1=enabled)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
-		collector.policyEnabledDesc = prometheus.NewDesc(
+		collector.policyEnabledDesc = families.gauge(
 			"wnc_wlan_policy_enabled",
 			"Policy profile bound to this WLAN is active (0=shut down or not reported, 1=active)",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
 		// Protected management frames has three configurations, and the middle one
 		// admits an unprotected association, so the setting is published as the value
 		// the controller assigns it rather than collapsed to a boolean.
-		collector.pmfStateDesc = prometheus.NewDesc(
+		collector.pmfStateDesc = families.gauge(
 			"wnc_wlan_pmf_state",
 			"Protected management frames setting, as the value the controller's own "+
 				"enumeration assigns its spelling (0=apf-vap-pmf-disabled, "+
 				"1=apf-vap-pmf-optional, 2=apf-vap-pmf-required). It covers 2.4GHz and 5GHz "+
 				"— a 6GHz BSS requires PMF whatever this reports",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
-		collector.ftStateDesc = prometheus.NewDesc(
+		collector.ftStateDesc = families.gauge(
 			"wnc_wlan_ft_state",
 			"802.11r fast transition mode, as the value the controller's own enumeration "+
 				"assigns its spelling (0=dot11r-disabled, 1=dot11r-enabled, "+
 				"2=dot11r-adaptive-enabled). Match by equality: 2 is a third mode for clients "+
 				"that cannot use the FT AKM, not a stronger form of 1",
-			labels, nil,
+			labels, wnc.DataWLANCfgEntries,
 		)
 		// The six policy series above name neither the tag nor the profile they read, so
 		// this is what makes a WLAN bound through several tags observable.
-		collector.policyBindingDesc = prometheus.NewDesc(
+		collector.policyBindingDesc = families.gauge(
 			"wnc_wlan_policy_binding",
 			"Policy tag binding for this WLAN, always 1. One series per binding the "+
 				"exporter can resolve, so more than one policy_profile for an id means the "+
 				"six policy series report only one of the bound profiles",
-			[]string{labelID, labelPolicyProfile, labelPolicyTag}, nil,
+			[]string{labelID, labelPolicyProfile, labelPolicyTag}, wnc.DataWLANCfgEntries, wnc.DataWLANPolicies, wnc.DataWLANPolicyListEntries,
 		)
 	}
 
@@ -198,10 +216,10 @@ func NewWLANCollector(src wnc.WLANSource, clientSrc wnc.ClientSource, metrics WL
 		requiredLabels := []string{"id"}
 		availableLabels := []string{"name"}
 		infoLabels := buildInfoLabels(requiredLabels, metrics.InfoLabels, availableLabels)
-		collector.infoDesc = prometheus.NewDesc(
+		collector.infoDesc = families.gauge(
 			"wnc_wlan_info",
 			"WLAN information labels for joining with other metrics",
-			infoLabels, nil,
+			infoLabels, wnc.DataWLANCfgEntries,
 		)
 		collector.infoLabelNames = infoLabels
 	}
@@ -209,6 +227,11 @@ func NewWLANCollector(src wnc.WLANSource, clientSrc wnc.ClientSource, metrics WL
 	return collector
 }
 
+// declaredFamilies returns the families of the enabled modules.
+func (c *WLANCollector) declaredFamilies() *familySet {
+	return c.families
+}
+
 // Describe sends the descriptors of all metrics to the provided channel.
 func (c *WLANCollector) Describe(ch chan<- *prometheus.Desc) {
 	if c.metrics.General {
@@ -238,6 +261,12 @@ func (c *WLANCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- c.ftStateDesc
 		ch <- c.policyBindingDesc
 	}
+	if c.metrics.Applications {
+		c.applications.describe(ch)
+	}
+	if c.metrics.ATF {
+		c.atf.describe(ch)
+	}
 	if c.metrics.Info {
 		ch <- c.infoDesc
 	}
@@ -251,6 +280,31 @@ func (c *WLANCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	if c.metrics.Applications {
+		appStats, err := c.src.ListAppStats(ctx)
+		if err != nil {
+			slog.Debug("Failed to get AVC statistics for WLAN application metrics", "error", err)
+		} else {
+			c.applications.collect(ch, appStats)
+		}
+	}
+
+	if c.metrics.ATF {
+		atfStats, err := c.src.ListATFStats(ctx)
+		if err != nil {
+			slog.Debug("Failed to get ATF statistics for WLAN airtime metrics", "error", err)
+		} else {
+			c.atf.collect(ch, atfStats)
+		}
+	}
+
+	// Every module below reads the configuration entries. The applications and atf
+	// modules are keyed by their statistics alone, so a deployment enabling only those
+	// must not go on to ask for a data type no enabled module declared.
+	if !c.isAnyConfigKeyedFlagEnabled() {
+		return
+	}
+
 	wlanConfigEntries, err := c.src.ListConfigEntries(ctx)
 	if err != nil {
 		slog.Debug("Failed to retrieve WLAN configuration entries", "error", err)
@@ -700,5 +754,11 @@ func determineCentralAssocEnableValue(policy *wlan.WlanPolicy) float64 {
 }
 
 func (c *WLANCollector) isAnyMetricFlagEnabled() bool {
+	return c.isAnyConfigKeyedFlagEnabled() || c.metrics.Applications || c.metrics.ATF
+}
+
+// isAnyConfigKeyedFlagEnabled reports whether a module that walks the WLAN
+// configuration entries is enabled.
+func (c *WLANCollector) isAnyConfigKeyedFlagEnabled() bool {
 	return IsEnabled(c.metrics.General, c.metrics.Traffic, c.metrics.Config, c.metrics.Info)
 }
@@ -0,0 +1,189 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the AVC applications module of the WLAN collector.
+package collector
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// wlanApplicationDescs holds the descriptors of the applications module. A nil value
+// means the module is disabled, which is what keeps every series of it out of a default
+// scrape.
+type wlanApplicationDescs struct {
+	bytes        *prometheus.Desc
+	packets      *prometheus.Desc
+	otherBytes   *prometheus.Desc
+	otherPackets *prometheus.Desc
+
+	// topN bounds the applications published per WLAN. NBAR classifies hundreds of
+	// applications, and the tail of a WLAN's traffic carries little worth a series each.
+	topN int
+}
+
+// newWLANApplicationDescs builds the descriptors of the applications module.
+//
+// The series are keyed by the WLAN identifier, the key of every wnc_wlan_* series, so
+// wnc_wlan_info names the WLAN with a plain join on id rather than this module carrying
+// a name that a rename would fork.
+//
+// The applications outside the top N are summed into gauges of their own rather than
+// into an application of the counters. The set that sum covers changes as applications
+// enter and leave the top N, so it falls as well as rises, which a counter must not.
+func newWLANApplicationDescs(families *familySet, topN int) *wlanApplicationDescs {
+	labels := []string{labelID, labelApplication, labelDirection}
+	otherLabels := []string{labelID, labelDirection}
+
+	return &wlanApplicationDescs{
+		bytes: families.counter(
+			"wnc_wlan_application_bytes_total",
+			"Bytes AVC classified as this application on this WLAN, per direction as the "+
+				"controller spells it. Only the heaviest applications of each WLAN are "+
+				"published, up to --collector.wlan.applications-top-n, so an application's "+
+				"series is absent while it ranks outside that set",
+			labels, wnc.DataWLANAVCStats,
+		),
+		packets: families.counter(
+			"wnc_wlan_application_packets_total",
+			"Packets AVC classified as this application on this WLAN, for the same "+
+				"applications as the byte counter",
+			labels, wnc.DataWLANAVCStats,
+		),
+		otherBytes: families.gauge(
+			"wnc_wlan_application_other_bytes",
+			"Bytes AVC classified on this WLAN as applications outside the ones the byte "+
+				"counter publishes, summed. A gauge, because it falls when an application "+
+				"joins the published set",
+			otherLabels, wnc.DataWLANAVCStats,
+		),
+		otherPackets: families.gauge(
+			"wnc_wlan_application_other_packets",
+			"Packets AVC classified on this WLAN as applications outside the ones the "+
+				"packet counter publishes, summed. A gauge for the same reason as the bytes",
+			otherLabels, wnc.DataWLANAVCStats,
+		),
+		topN: topN,
+	}
+}
+
+// describe sends every descriptor of the applications module.
+func (d *wlanApplicationDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.bytes
+	ch <- d.packets
+	ch <- d.otherBytes
+	ch <- d.otherPackets
+}
+
+// wlanApplicationKey identifies one series of the applications module.
+type wlanApplicationKey struct {
+	wlanID      int
+	application string
+	direction   string
+}
+
+// wlanApplicationOtherKey identifies one series of the other gauges.
+type wlanApplicationOtherKey struct {
+	wlanID    int
+	direction string
+}
+
+// wlanApplicationCounts carries the two counters of one series.
+type wlanApplicationCounts struct {
+	bytes   float64
+	packets float64
+}
+
+// collect publishes the heaviest applications of every WLAN the AVC list carries, and
+// sums the rest of each WLAN per direction into the other gauges.
+//
+// Applications are ranked within a WLAN by their bytes in both directions together, so
+// one application is either published in every direction or folded in every direction,
+// and two applications with equal bytes are ordered by name so the set holds still
+// across scrapes. A record with no application or no direction cannot be told apart from
+// another, and one whose counters do not parse would fold a fabricated zero into the
+// ranking, so both are dropped.
+func (d *wlanApplicationDescs) collect(ch chan<- prometheus.Metric, stats []wnc.WLANAppStats) {
+	counts := make(map[wlanApplicationKey]wlanApplicationCounts, len(stats))
+	totals := make(map[int]map[string]float64)
+
+	for i := range stats {
+		entry := &stats[i]
+		if entry.AppName == "" || entry.Direction == "" {
+			continue
+		}
+
+		bytes, bytesErr := entry.Bytes.Float64()
+		packets, packetsErr := entry.Packets.Float64()
+		if bytesErr != nil || packetsErr != nil {
+			slog.Debug("skipped an AVC record whose counters are unreadable",
+				"id", entry.WlanID, "application", entry.AppName)
+			continue
+		}
+
+		key := wlanApplicationKey{entry.WlanID, entry.AppName, entry.Direction}
+		if _, seen := counts[key]; seen {
+			continue
+		}
+		counts[key] = wlanApplicationCounts{bytes: bytes, packets: packets}
+
+		if totals[entry.WlanID] == nil {
+			totals[entry.WlanID] = make(map[string]float64)
+		}
+		totals[entry.WlanID][entry.AppName] += bytes
+	}
+
+	kept := make(map[int]map[string]bool, len(totals))
+	for wlanID, byApplication := range totals {
+		kept[wlanID] = d.heaviest(byApplication)
+	}
+
+	other := make(map[wlanApplicationOtherKey]wlanApplicationCounts)
+	for key, value := range counts {
+		if !kept[key.wlanID][key.application] {
+			otherKey := wlanApplicationOtherKey{key.wlanID, key.direction}
+			sum := other[otherKey]
+			sum.bytes += value.bytes
+			sum.packets += value.packets
+			other[otherKey] = sum
+			continue
+		}
+
+		labels := []string{strconv.Itoa(key.wlanID), key.application, key.direction}
+		ch <- prometheus.MustNewConstMetric(d.bytes, prometheus.CounterValue, value.bytes, labels...)
+		ch <- prometheus.MustNewConstMetric(d.packets, prometheus.CounterValue, value.packets, labels...)
+	}
+
+	for key, value := range other {
+		labels := []string{strconv.Itoa(key.wlanID), key.direction}
+		ch <- prometheus.MustNewConstMetric(d.otherBytes, prometheus.GaugeValue, value.bytes, labels...)
+		ch <- prometheus.MustNewConstMetric(d.otherPackets, prometheus.GaugeValue, value.packets, labels...)
+	}
+}
+
+// heaviest returns the topN applications of one WLAN by bytes, ties ordered by name.
+func (d *wlanApplicationDescs) heaviest(byApplication map[string]float64) map[string]bool {
+	names := make([]string, 0, len(byApplication))
+	for name := range byApplication {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(byApplication[b], byApplication[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	kept := make(map[string]bool, d.topN)
+	for _, name := range names[:min(d.topN, len(names))] {
+		kept[name] = true
+	}
+
+	return kept
+}
@@ -0,0 +1,91 @@
+// Package collector provides collectors for cisco-wnc-exporter.
+// This file holds the Air Time Fairness module of the WLAN collector.
+package collector
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// microsecondsPerSecond converts the airtime the controller reports.
+const microsecondsPerSecond = 1e6
+
+// wlanATFDescs holds the descriptors of the atf module. A nil value means the module is
+// disabled, which is what keeps every series of it out of a default scrape.
+type wlanATFDescs struct {
+	allocation *prometheus.Desc
+	airtime    *prometheus.Desc
+}
+
+// newWLANATFDescs builds the descriptors of the atf module.
+//
+// A policy is enforced per radio, so the series are keyed by the WLAN identifier, the
+// policy and the AP radio the policy is enforced on. The allocation is a ratio and the
+// airtime a counter in seconds, so the rate of the counter reads in the unit of the
+// allocation and the two compare without a conversion.
+func newWLANATFDescs(families *familySet) *wlanATFDescs {
+	labels := []string{labelID, labelPolicy, labelMAC, labelRadio}
+
+	return &wlanATFDescs{
+		allocation: families.gauge(
+			"wnc_wlan_atf_airtime_allocation_ratio",
+			"Share of this radio's airtime (0-1) the ATF policy allots this WLAN. Absent "+
+				"while the controller reports none",
+			labels, wnc.DataAPRadioATFStats,
+		),
+		airtime: families.counter(
+			"wnc_wlan_atf_airtime_seconds_total",
+			"Airtime the clients of this WLAN under the ATF policy consumed on this radio. "+
+				"Its rate is the share of the radio's airtime they used, comparable with "+
+				"wnc_wlan_atf_airtime_allocation_ratio",
+			labels, wnc.DataAPRadioATFStats,
+		),
+	}
+}
+
+// describe sends every descriptor of the atf module.
+func (d *wlanATFDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.allocation
+	ch <- d.airtime
+}
+
+// collect publishes every policy the ATF statistics list carries.
+//
+// A record with no AP, no WLAN or no policy cannot be told apart from another, so it is
+// dropped, and the same label set is emitted once, since Gather rejects a duplicate by
+// failing the entire endpoint. The allocation is withheld at zero, since a policy the
+// controller enforces allots some airtime, and the airtime counter is withheld when the
+// record omits or garbles it rather than published as 0, which would read as a reset.
+func (d *wlanATFDescs) collect(ch chan<- prometheus.Metric, stats []wnc.RadioATFStats) {
+	seen := make(map[[4]string]bool, len(stats))
+
+	for i := range stats {
+		entry := &stats[i]
+		if entry.WtpMAC == "" || entry.WlanID <= 0 || entry.PolicyName == "" {
+			continue
+		}
+
+		labels := [4]string{
+			strconv.Itoa(entry.WlanID), entry.PolicyName, entry.WtpMAC, strconv.Itoa(entry.RadioSlotID),
+		}
+		if seen[labels] {
+			continue
+		}
+		seen[labels] = true
+
+		if allocation, err := entry.AirtimeAllocation.Float64(); err == nil && allocation > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				d.allocation, prometheus.GaugeValue, allocation/100, labels[:]...,
+			)
+		}
+
+		if used, err := entry.AirtimeUsed.Float64(); err == nil {
+			ch <- prometheus.MustNewConstMetric(
+				d.airtime, prometheus.CounterValue, used/microsecondsPerSecond, labels[:]...,
+			)
+		}
+	}
+}
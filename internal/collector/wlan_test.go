@@ -1,11 +1,13 @@
 package collector
 
 import (
+	"maps"
 	"slices"
 	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
@@ -121,6 +123,16 @@ func TestWLANCollector_isAnyMetricFlagEnabled(t *testing.T) {
 			WLANMetrics{Config: true},
 			true,
 		},
+		{
+			"Applications enabled",
+			WLANMetrics{Applications: true, ApplicationsTopN: 10},
+			true,
+		},
+		{
+			"ATF enabled",
+			WLANMetrics{ATF: true},
+			true,
+		},
 		{
 			"Info enabled",
 			WLANMetrics{Info: true},
@@ -175,6 +187,16 @@ func TestWLANCollector_Describe(t *testing.T) {
 			// policy_binding
 			17,
 		},
+		{
+			"Applications module only",
+			WLANMetrics{Applications: true, ApplicationsTopN: 10},
+			4, // application_bytes, application_packets, and their other gauges
+		},
+		{
+			"ATF module only",
+			WLANMetrics{ATF: true},
+			2, // atf_airtime_allocation, atf_airtime_seconds
+		},
 		{
 			"Info module only",
 			WLANMetrics{Info: true},
@@ -183,12 +205,15 @@ func TestWLANCollector_Describe(t *testing.T) {
 		{
 			"All modules enabled",
 			WLANMetrics{
-				General: true,
-				Traffic: true,
-				Config:  true,
-				Info:    true,
+				General:          true,
+				Traffic:          true,
+				Config:           true,
+				Applications:     true,
+				ApplicationsTopN: 10,
+				ATF:              true,
+				Info:             true,
 			},
-			22, // 1+3+17+1
+			28, // 1+3+17+4+2+1
 		},
 	}
 
@@ -1846,3 +1871,138 @@ func TestWLANCollector_OnboardingPhasesMatchLeaves(t *testing.T) {
 		}
 	}
 }
+
+// TestWLANApplicationsModule_TopNAndOtherGauges pins the ranking of the applications
+// module: the heaviest applications of each WLAN are kept in every direction as
+// counters, the rest are summed into the other gauges, an application NBAR itself names
+// "other" is ranked like any other, and a duplicate or unreadable record never reaches
+// the sums.
+func TestWLANApplicationsModule_TopNAndOtherGauges(t *testing.T) {
+	t.Parallel()
+
+	data := fullFixtureSnapshot()
+	data.WLANAppStats = []wnc.WLANAppStats{
+		{WlanID: 1, AppName: "ms-teams", Direction: "ingress", Bytes: "300", Packets: "3"},
+		{WlanID: 1, AppName: "ms-teams", Direction: "egress", Bytes: "100", Packets: "1"},
+		{WlanID: 1, AppName: "youtube", Direction: "ingress", Bytes: "250", Packets: "5"},
+		{WlanID: 1, AppName: "youtube", Direction: "ingress", Bytes: "9999", Packets: "99"},
+		{WlanID: 1, AppName: "dropbox", Direction: "ingress", Bytes: "40", Packets: "4"},
+		{WlanID: 1, AppName: "other", Direction: "ingress", Bytes: "10", Packets: "1"},
+		{WlanID: 1, AppName: "webex", Direction: "egress", Bytes: "20", Packets: "2"},
+		{WlanID: 1, AppName: "zoom", Direction: "ingress", Bytes: "garbled", Packets: "7"},
+		{WlanID: 1, AppName: "", Direction: "ingress", Bytes: "70", Packets: "7"},
+		{WlanID: 4, AppName: "dropbox", Direction: "ingress", Bytes: "5", Packets: "1"},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewWLANCollector(
+		wnc.NewWLANSource(src), wnc.NewClientSource(src),
+		WLANMetrics{Applications: true, ApplicationsTopN: 2},
+	))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	bytes := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			key := labels[labelID] + "/" + labels[labelApplication] + "/" + labels[labelDirection]
+			switch family.GetName() {
+			case "wnc_wlan_application_bytes_total":
+				if family.GetType() != dto.MetricType_COUNTER {
+					t.Errorf("%s is a %v, want a counter", family.GetName(), family.GetType())
+				}
+				bytes[key] = metric.GetCounter().GetValue()
+			case "wnc_wlan_application_other_bytes":
+				if family.GetType() != dto.MetricType_GAUGE {
+					t.Errorf("%s is a %v, want a gauge", family.GetName(), family.GetType())
+				}
+				bytes["other:"+key] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"1/ms-teams/ingress": 300,
+		"1/ms-teams/egress":  100,
+		"1/youtube/ingress":  250,
+		"other:1//ingress":   50, // dropbox and NBAR's own "other"
+		"other:1//egress":    20, // webex
+		"4/dropbox/ingress":  5,
+	}
+	if !maps.Equal(bytes, want) {
+		t.Errorf("application bytes = %v, want %v", bytes, want)
+	}
+}
+
+// TestWLANATFModule_WithholdsWhatTheRecordOmits pins the atf module: the allocation is
+// withheld at zero and the airtime counter when the record omits it, a record naming no
+// policy or no WLAN is dropped, and a repeated record is published once.
+func TestWLANATFModule_WithholdsWhatTheRecordOmits(t *testing.T) {
+	t.Parallel()
+
+	const apMAC = "aa:bb:cc:dd:ee:00"
+
+	data := fullFixtureSnapshot()
+	data.RadioATFStats = []wnc.RadioATFStats{
+		{
+			WtpMAC: apMAC, RadioSlotID: 1, WlanID: 1, PolicyName: "voice-first",
+			AirtimeAllocation: "25", AirtimeUsed: "2500000",
+		},
+		{
+			WtpMAC: apMAC, RadioSlotID: 1, WlanID: 1, PolicyName: "voice-first",
+			AirtimeAllocation: "90", AirtimeUsed: "9000000",
+		},
+		{WtpMAC: apMAC, RadioSlotID: 1, WlanID: 4, PolicyName: "guest", AirtimeAllocation: "0"},
+		{WtpMAC: apMAC, RadioSlotID: 1, WlanID: 4, AirtimeAllocation: "50", AirtimeUsed: "1"},
+		{WtpMAC: apMAC, RadioSlotID: 1, PolicyName: "guest", AirtimeAllocation: "50", AirtimeUsed: "1"},
+	}
+
+	src := fixtureSource{data: data}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewWLANCollector(
+		wnc.NewWLANSource(src), wnc.NewClientSource(src), WLANMetrics{ATF: true},
+	))
+
+	// Gather fails on a duplicate label set, so a nil error is itself the assertion
+	// that the repeated record is published once.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v, want nil", err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string, len(metric.GetLabel()))
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			value := metric.GetGauge().GetValue()
+			if metric.GetCounter() != nil {
+				value = metric.GetCounter().GetValue()
+			}
+			got[family.GetName()+"|"+labels[labelID]+"|"+labels[labelPolicy]] = value
+		}
+	}
+
+	want := map[string]float64{
+		"wnc_wlan_atf_airtime_allocation_ratio|1|voice-first": 0.25,
+		"wnc_wlan_atf_airtime_seconds_total|1|voice-first":    2.5,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("atf module published %v, want only %v", got, want)
+	}
+}
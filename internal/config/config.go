@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net/url"
 	"path"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -17,15 +20,44 @@ const (
 	DefaultListenAddress = "0.0.0.0"
 	DefaultListenPort    = 10039
 	DefaultTelemetryPath = "/metrics"
-	// HealthPath lives here so Validate can reject a telemetry path that takes it.
+	// HealthPath and CatalogPath live here so Validate can reject a telemetry path that
+	// takes one.
 	// The server package already depends on this one, so the reverse would cycle.
 	HealthPath                   = "/healthz"
+	CatalogPath                  = "/metrics/catalog"
 	DefaultWNCTimeout            = 55 * time.Second
 	DefaultWNCCacheTTL           = 55 * time.Second
 	DefaultCollectorInfoCacheTTL = 1800 * time.Second
 	DefaultLogLevel              = "info"
 	DefaultLogFormat             = "json"
 
+	// DefaultAPNeighborsTopN keeps the strongest neighbors of each radio, which is what
+	// co-channel contention and a coverage hole are read from.
+	DefaultAPNeighborsTopN = 5
+
+	// DefaultWLANApplicationsTopN keeps the heaviest applications of each WLAN and folds
+	// the long tail NBAR classifies into one bucket.
+	DefaultWLANApplicationsTopN = 10
+
+	// DefaultAPDepartedRetention keeps a departed AP reported for a day, long enough
+	// for an overnight outage to still be on the board in the morning.
+	DefaultAPDepartedRetention = 24 * time.Hour
+
+	// DefaultWNCRetryAttempts gives a data type two retries per refresh, enough to
+	// ride out a dropped connection or a busy controller without spending the refresh
+	// deadline on one that is down.
+	DefaultWNCRetryAttempts = 3
+	// DefaultWNCRetryBackoff is the wait before the first retry, doubled before each next.
+	DefaultWNCRetryBackoff = time.Second
+
+	// DefaultRemoteWriteQueueSize holds ten pushes while the receiver is unreachable,
+	// about nine minutes of refreshes at the default cache TTL.
+	DefaultRemoteWriteQueueSize = 10
+
+	// DefaultWNCSnapshotMaxAge serves a restored snapshot for a quarter of an hour,
+	// which covers a restart or a rollout without passing off an outage's data as current.
+	DefaultWNCSnapshotMaxAge = 15 * time.Minute
+
 	DefaultAPInfoLabels     = "name,ip"
 	DefaultClientInfoLabels = "name,ipv4"
 	DefaultWLANInfoLabels   = "name"
@@ -39,12 +71,36 @@ const (
 	RequiredWLANInfoLabels   = "id"
 )
 
+// labelNamePattern matches a Prometheus label name.
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ReservedLabels lists the label names the collectors key series by, as the collector
+// package declares them, and the bucket label of a histogram. A constant label may not
+// reuse one. The collector package imports this one, so the list cannot be built from
+// its constants; its tests bind the list to the label constants and to the descriptors
+// instead, so a new label constant fails them until it is added here.
+var ReservedLabels = []string{
+	"access_category", "acct_port", "address", "ap", "application", "auth_port", "band",
+	"channel", "code", "data", "device_type", "direction", "eth_mac", "group", "id", "ip",
+	"ipv4", "ipv6", "last_disconnect_reason", "last_reboot_reason", "le", "mac", "model",
+	"name", "neighbor", "neighbor_mac", "os", "parent_mac", "phase", "platform", "policy",
+	"policy_profile", "policy_tag", "port", "profile", "radio", "reason", "serial",
+	"sw_version", "type", "username", "vendor", "wlan",
+}
+
+// buildInfoLabel is the label wnc_build_info and go_info carry. ReservedLabels leaves it
+// out, since a constant label never reaches either series, but a pushed one does.
+const buildInfoLabel = "version"
+
 // Config represents the complete configuration.
 type Config struct {
 	Web               Web               `json:"web"`
 	WNC               WNC               `json:"wnc"`
 	Collectors        Collectors        `json:"collectors"`
 	Log               Log               `json:"log"`
+	Tracing           Tracing           `json:"tracing"`
+	OTLP              OTLP              `json:"otlp"`
+	RemoteWrite       RemoteWrite       `json:"remote_write"`
 	InternalCollector InternalCollector `json:"internal_collector"`
 	DryRun            bool              `json:"dry_run"`
 }
@@ -63,6 +119,14 @@ type WNC struct {
 	Timeout       time.Duration `json:"timeout"`
 	CacheTTL      time.Duration `json:"cache_ttl"`
 	TLSSkipVerify bool          `json:"tls_skip_verify"`
+	// RetryAttempts bounds the fetches of one data type per refresh, the first
+	// included, and RetryBackoff is the wait before the first retry.
+	RetryAttempts int           `json:"retry_attempts"`
+	RetryBackoff  time.Duration `json:"retry_backoff"`
+	// SnapshotFile is where the last snapshot is kept across restarts. Empty keeps
+	// it in memory, so a restart scrapes nothing until its first refresh.
+	SnapshotFile   string        `json:"snapshot_file"`
+	SnapshotMaxAge time.Duration `json:"snapshot_max_age"`
 }
 
 // Collectors holds collector module configuration.
@@ -71,7 +135,38 @@ type Collectors struct {
 	Client       ClientCollectorModules     `json:"client"`
 	WLAN         WLANCollectorModules       `json:"wlan"`
 	Controller   ControllerCollectorModules `json:"controller"`
+	RRM          RRMCollectorModules        `json:"rrm"`
 	InfoCacheTTL time.Duration              `json:"info_cache_ttl"`
+	// MetricsInclude and MetricsExclude select the metric families the modules publish
+	// by fully qualified name. Each pattern is anchored to the whole name. A compiled
+	// pattern has no JSON form, so the patterns as given are kept alongside for it.
+	MetricsInclude         []*regexp.Regexp `json:"-"`
+	MetricsExclude         []*regexp.Regexp `json:"-"`
+	MetricsIncludePatterns []string         `json:"metrics_include"`
+	MetricsExcludePatterns []string         `json:"metrics_exclude"`
+	// ConstLabels are added to every series of the modules and of the refresh health,
+	// so the data of several exporters stays apart once federated or remote-written.
+	ConstLabels map[string]string `json:"const_labels"`
+}
+
+// MetricAllowed reports whether a metric family passes the include and exclude lists: it
+// matches an include pattern, or there is none, and matches no exclude pattern.
+func (c *Collectors) MetricAllowed(name string) bool {
+	if len(c.MetricsInclude) > 0 && !slices.ContainsFunc(c.MetricsInclude, matches(name)) {
+		return false
+	}
+	return !slices.ContainsFunc(c.MetricsExclude, matches(name))
+}
+
+// FiltersMetrics reports whether an include or exclude pattern is set.
+func (c *Collectors) FiltersMetrics() bool {
+	return len(c.MetricsInclude) > 0 || len(c.MetricsExclude) > 0
+}
+
+func matches(name string) func(*regexp.Regexp) bool {
+	return func(pattern *regexp.Regexp) bool {
+		return pattern.MatchString(name)
+	}
 }
 
 // APCollectorModules represents AP collector modules.
@@ -86,8 +181,25 @@ type APCollectorModules struct {
 	Errors bool `json:"errors"`
 	// Join: CAPWAP discovery, join, configuration and DTLS statistics
 	Join bool `json:"join"`
+	// Uplink: CDP/LLDP neighbor, Ethernet link speed and duplex, PoE power status
+	Uplink bool `json:"uplink"`
+	// Mesh: mesh role, parent, hop count and backhaul link
+	Mesh bool `json:"mesh"`
+	// QoS: WMM frames transmitted and queue drops per radio and access category
+	QoS bool `json:"qos"`
+	// Restarts: reboots and CAPWAP rejoins observed across scrapes, by reason
+	Restarts bool `json:"restarts"`
+	// Departed: APs that left the inventory, reported as not joined for a retention
+	Departed          bool          `json:"departed"`
+	DepartedRetention time.Duration `json:"departed_retention"`
+	DepartedStateFile string        `json:"departed_state_file"`
+	// Neighbors: RSSI at which each radio hears its strongest RRM neighbors
+	Neighbors     bool `json:"neighbors"`
+	NeighborsTopN int  `json:"neighbors_top_n"`
 	// Spectrum: CleanAir air quality
 	Spectrum bool `json:"spectrum"`
+	// Interferers: CleanAir interferer devices by type, severity and duty cycle
+	Interferers bool `json:"interferers"`
 	// Info: info metric with labels
 	Info       bool     `json:"info"`
 	InfoLabels []string `json:"info_labels"`
@@ -103,6 +215,12 @@ type ClientCollectorModules struct {
 	Traffic bool `json:"traffic"`
 	// Errors: retries, drops, failures
 	Errors bool `json:"errors"`
+	// Devices: client counts by device type, OS and vendor per WLAN and band
+	Devices bool `json:"devices"`
+	// Onboarding: association-to-run latency and pending-phase histograms per WLAN
+	Onboarding bool `json:"onboarding"`
+	// Sessions: session start, end and roam counters and session durations
+	Sessions bool `json:"sessions"`
 	// Info: info metric with labels
 	Info       bool     `json:"info"`
 	InfoLabels []string `json:"info_labels"`
@@ -116,6 +234,11 @@ type WLANCollectorModules struct {
 	Traffic bool `json:"traffic"`
 	// Config: auth, security, networking settings
 	Config bool `json:"config"`
+	// Applications: AVC bytes and packets per application and direction
+	Applications     bool `json:"applications"`
+	ApplicationsTopN int  `json:"applications_top_n"`
+	// ATF: Air Time Fairness allocation and usage per radio and policy
+	ATF bool `json:"atf"`
 	// Info: info metric with labels
 	Info       bool     `json:"info"`
 	InfoLabels []string `json:"info_labels"`
@@ -125,6 +248,14 @@ type WLANCollectorModules struct {
 type ControllerCollectorModules struct {
 	// General: boot time, client delete reasons, client roaming statistics
 	General bool `json:"general"`
+	// AAA: RADIUS requests, responses, timeouts, round-trip time and state per server
+	AAA bool `json:"aaa"`
+}
+
+// RRMCollectorModules represents RRM collector modules.
+type RRMCollectorModules struct {
+	// Channels: AP and client counts, utilization and noise per band and channel
+	Channels bool `json:"channels"`
 }
 
 // Log holds logging configuration.
@@ -133,6 +264,32 @@ type Log struct {
 	Format string `json:"format"`
 }
 
+// Tracing holds OpenTelemetry tracing configuration.
+type Tracing struct {
+	// Endpoint is the OTLP/HTTP URL spans are exported to. Empty disables tracing.
+	Endpoint string `json:"endpoint"`
+}
+
+// OTLP holds OTLP metrics push configuration.
+type OTLP struct {
+	// Endpoint is the OTLP/HTTP URL metrics are pushed to after each refresh.
+	// Empty disables the push.
+	Endpoint string `json:"endpoint"`
+}
+
+// RemoteWrite holds Prometheus remote-write push configuration.
+type RemoteWrite struct {
+	// URL is the remote-write receiver metrics are pushed to after each refresh.
+	// Empty disables the push.
+	URL            string            `json:"url"`
+	Username       string            `json:"username"`
+	Password       string            `json:"-"` // Never serialize credentials
+	ExternalLabels map[string]string `json:"external_labels"`
+	// QueueSize bounds the pushes held while the receiver is unreachable. The
+	// oldest is dropped to make room for a newer one.
+	QueueSize int `json:"queue_size"`
+}
+
 // InternalCollector holds internal metrics collection configuration.
 type InternalCollector struct {
 	EnableGoCollector      bool `json:"enable_go_collector"`
@@ -141,6 +298,52 @@ type InternalCollector struct {
 
 // Parse parses configuration from CLI command and environment variables.
 func Parse(cmd *cli.Command) (*Config, error) {
+	cfg, err := parseFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// ParseOffline parses configuration for a command that never reaches the controller,
+// such as the metric catalog: the controller and the access token are not required.
+func ParseOffline(cmd *cli.Command) (*Config, error) {
+	cfg, err := parseFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(false); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// parseFlags reads the configuration from the flags without validating it.
+func parseFlags(cmd *cli.Command) (*Config, error) {
+	externalLabels, err := parseLabelPairs(cmd.String("remote-write.external-labels"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote-write external labels: %w", err)
+	}
+	constLabels, err := parseConstLabels(cmd.StringSlice("collector.const-label"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector constant labels: %w", err)
+	}
+	metricsInclude, err := parseMetricPatterns(cmd.StringSlice("collector.metrics.include"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric include pattern: %w", err)
+	}
+	metricsExclude, err := parseMetricPatterns(cmd.StringSlice("collector.metrics.exclude"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric exclude pattern: %w", err)
+	}
+
 	cfg := &Config{
 		Web: Web{
 			ListenAddress: cmd.String("web.listen-address"),
@@ -148,47 +351,89 @@ func Parse(cmd *cli.Command) (*Config, error) {
 			TelemetryPath: cmd.String("web.telemetry-path"),
 		},
 		WNC: WNC{
-			Controller:    cmd.String("wnc.controller"),
-			AccessToken:   cmd.String("wnc.access-token"),
-			Timeout:       cmd.Duration("wnc.timeout"),
-			CacheTTL:      cmd.Duration("wnc.cache-ttl"),
-			TLSSkipVerify: cmd.Bool("wnc.tls-skip-verify"),
+			Controller:     cmd.String("wnc.controller"),
+			AccessToken:    cmd.String("wnc.access-token"),
+			Timeout:        cmd.Duration("wnc.timeout"),
+			CacheTTL:       cmd.Duration("wnc.cache-ttl"),
+			TLSSkipVerify:  cmd.Bool("wnc.tls-skip-verify"),
+			RetryAttempts:  cmd.Int("wnc.retry-attempts"),
+			RetryBackoff:   cmd.Duration("wnc.retry-backoff"),
+			SnapshotFile:   cmd.String("wnc.snapshot-file"),
+			SnapshotMaxAge: cmd.Duration("wnc.snapshot-max-age"),
 		},
 		Collectors: Collectors{
 			AP: APCollectorModules{
-				General:    cmd.Bool("collector.ap.general"),
-				Radio:      cmd.Bool("collector.ap.radio"),
-				Traffic:    cmd.Bool("collector.ap.traffic"),
-				Errors:     cmd.Bool("collector.ap.errors"),
-				Join:       cmd.Bool("collector.ap.join"),
-				Spectrum:   cmd.Bool("collector.ap.spectrum"),
-				Info:       cmd.Bool("collector.ap.info"),
-				InfoLabels: parseAPInfoLabels(cmd.String("collector.ap.info-labels")),
+				General:           cmd.Bool("collector.ap.general"),
+				Radio:             cmd.Bool("collector.ap.radio"),
+				Traffic:           cmd.Bool("collector.ap.traffic"),
+				Errors:            cmd.Bool("collector.ap.errors"),
+				Join:              cmd.Bool("collector.ap.join"),
+				Uplink:            cmd.Bool("collector.ap.uplink"),
+				Mesh:              cmd.Bool("collector.ap.mesh"),
+				QoS:               cmd.Bool("collector.ap.qos"),
+				Restarts:          cmd.Bool("collector.ap.restarts"),
+				Departed:          cmd.Bool("collector.ap.departed"),
+				DepartedRetention: cmd.Duration("collector.ap.departed-retention"),
+				DepartedStateFile: cmd.String("collector.ap.departed-state-file"),
+				Neighbors:         cmd.Bool("collector.ap.neighbors"),
+				NeighborsTopN:     cmd.Int("collector.ap.neighbors-top-n"),
+				Spectrum:          cmd.Bool("collector.ap.spectrum"),
+				Interferers:       cmd.Bool("collector.ap.interferers"),
+				Info:              cmd.Bool("collector.ap.info"),
+				InfoLabels:        parseAPInfoLabels(cmd.String("collector.ap.info-labels")),
 			},
 			Client: ClientCollectorModules{
 				General:    cmd.Bool("collector.client.general"),
 				Radio:      cmd.Bool("collector.client.radio"),
 				Traffic:    cmd.Bool("collector.client.traffic"),
 				Errors:     cmd.Bool("collector.client.errors"),
+				Devices:    cmd.Bool("collector.client.devices"),
+				Onboarding: cmd.Bool("collector.client.onboarding"),
+				Sessions:   cmd.Bool("collector.client.sessions"),
 				Info:       cmd.Bool("collector.client.info"),
 				InfoLabels: parseClientInfoLabels(cmd.String("collector.client.info-labels")),
 			},
 			WLAN: WLANCollectorModules{
-				General:    cmd.Bool("collector.wlan.general"),
-				Traffic:    cmd.Bool("collector.wlan.traffic"),
-				Config:     cmd.Bool("collector.wlan.config"),
-				Info:       cmd.Bool("collector.wlan.info"),
-				InfoLabels: parseWLANInfoLabels(cmd.String("collector.wlan.info-labels")),
+				General:          cmd.Bool("collector.wlan.general"),
+				Traffic:          cmd.Bool("collector.wlan.traffic"),
+				Config:           cmd.Bool("collector.wlan.config"),
+				Applications:     cmd.Bool("collector.wlan.applications"),
+				ApplicationsTopN: cmd.Int("collector.wlan.applications-top-n"),
+				ATF:              cmd.Bool("collector.wlan.atf"),
+				Info:             cmd.Bool("collector.wlan.info"),
+				InfoLabels:       parseWLANInfoLabels(cmd.String("collector.wlan.info-labels")),
 			},
 			Controller: ControllerCollectorModules{
 				General: cmd.Bool("collector.controller.general"),
+				AAA:     cmd.Bool("collector.controller.aaa"),
+			},
+			RRM: RRMCollectorModules{
+				Channels: cmd.Bool("collector.rrm.channels"),
 			},
-			InfoCacheTTL: cmd.Duration("collector.info-cache-ttl"),
+			InfoCacheTTL:           cmd.Duration("collector.info-cache-ttl"),
+			MetricsInclude:         metricsInclude,
+			MetricsExclude:         metricsExclude,
+			MetricsIncludePatterns: metricPatterns(cmd.StringSlice("collector.metrics.include")),
+			MetricsExcludePatterns: metricPatterns(cmd.StringSlice("collector.metrics.exclude")),
+			ConstLabels:            constLabels,
 		},
 		Log: Log{
 			Level:  cmd.String("log.level"),
 			Format: cmd.String("log.format"),
 		},
+		Tracing: Tracing{
+			Endpoint: cmd.String("tracing.endpoint"),
+		},
+		OTLP: OTLP{
+			Endpoint: cmd.String("otlp.endpoint"),
+		},
+		RemoteWrite: RemoteWrite{
+			URL:            cmd.String("remote-write.url"),
+			Username:       cmd.String("remote-write.username"),
+			Password:       cmd.String("remote-write.password"),
+			ExternalLabels: externalLabels,
+			QueueSize:      cmd.Int("remote-write.queue-size"),
+		},
 		InternalCollector: InternalCollector{
 			EnableGoCollector:      cmd.Bool("collector.internal.go-runtime"),
 			EnableProcessCollector: cmd.Bool("collector.internal.process"),
@@ -196,25 +441,27 @@ func Parse(cmd *cli.Command) (*Config, error) {
 		DryRun: cmd.Bool("dry-run"),
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
-	}
-
 	return cfg, nil
 }
 
 // Validate performs configuration validation.
 func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+// validate performs configuration validation, requiring the controller and the access
+// token only when requireWNC is set.
+func (c *Config) validate(requireWNC bool) error {
 	validationRules := []struct {
 		condition bool
 		message   string
 	}{
 		{
-			strings.TrimSpace(c.WNC.Controller) == "",
+			requireWNC && strings.TrimSpace(c.WNC.Controller) == "",
 			"WNC controller is required (--wnc.controller or WNC_CONTROLLER)",
 		},
 		{
-			strings.TrimSpace(c.WNC.AccessToken) == "",
+			requireWNC && strings.TrimSpace(c.WNC.AccessToken) == "",
 			"WNC access token is required (--wnc.access-token or WNC_ACCESS_TOKEN)",
 		},
 		{
@@ -227,10 +474,36 @@ func (c *Config) Validate() error {
 		{
 			c.WNC.CacheTTL <= 0, fmt.Sprintf("WNC cache TTL must be positive, got: %v", c.WNC.CacheTTL),
 		},
+		{
+			c.WNC.RetryAttempts < 1,
+			fmt.Sprintf("WNC retry attempts must be at least 1, got: %d", c.WNC.RetryAttempts),
+		},
+		{
+			c.WNC.RetryBackoff < 0,
+			fmt.Sprintf("WNC retry backoff must not be negative, got: %v", c.WNC.RetryBackoff),
+		},
+		{
+			c.WNC.SnapshotFile != "" && c.WNC.SnapshotMaxAge <= 0,
+			fmt.Sprintf("WNC snapshot max age must be positive, got: %v", c.WNC.SnapshotMaxAge),
+		},
 		{
 			c.Collectors.InfoCacheTTL <= 0,
 			fmt.Sprintf("collector info cache TTL must be positive, got: %v", c.Collectors.InfoCacheTTL),
 		},
+		{
+			c.Collectors.AP.Neighbors && c.Collectors.AP.NeighborsTopN < 1,
+			fmt.Sprintf("AP neighbors top-N must be positive, got: %d", c.Collectors.AP.NeighborsTopN),
+		},
+		{
+			c.Collectors.AP.Departed && c.Collectors.AP.DepartedRetention <= 0,
+			fmt.Sprintf("AP departed retention must be positive, got: %v",
+				c.Collectors.AP.DepartedRetention),
+		},
+		{
+			c.Collectors.WLAN.Applications && c.Collectors.WLAN.ApplicationsTopN < 1,
+			fmt.Sprintf("WLAN applications top-N must be positive, got: %d",
+				c.Collectors.WLAN.ApplicationsTopN),
+		},
 		{
 			c.Web.TelemetryPath == "", "telemetry path cannot be empty",
 		},
@@ -258,6 +531,10 @@ func (c *Config) Validate() error {
 			c.Web.TelemetryPath == HealthPath,
 			"telemetry path must not be " + HealthPath + ", which serves the health check",
 		},
+		{
+			c.Web.TelemetryPath == CatalogPath,
+			"telemetry path must not be " + CatalogPath + ", which serves the metric catalog",
+		},
 		{
 			!isValidLogLevel(c.Log.Level),
 			fmt.Sprintf("invalid log level: %s (must be one of: debug, info, warn, error)", c.Log.Level),
@@ -266,6 +543,26 @@ func (c *Config) Validate() error {
 			!isValidLogFormat(c.Log.Format),
 			fmt.Sprintf("invalid log format: %s (must be one of: json, text)", c.Log.Format),
 		},
+		{
+			c.Tracing.Endpoint != "" && !isHTTPURL(c.Tracing.Endpoint),
+			"tracing endpoint must be an http or https URL with a host: " + c.Tracing.Endpoint,
+		},
+		{
+			c.OTLP.Endpoint != "" && !isHTTPURL(c.OTLP.Endpoint),
+			"OTLP endpoint must be an http or https URL with a host: " + c.OTLP.Endpoint,
+		},
+		{
+			c.RemoteWrite.URL != "" && !isHTTPURL(c.RemoteWrite.URL),
+			"remote-write URL must be an http or https URL with a host: " + c.RemoteWrite.URL,
+		},
+		{
+			c.RemoteWrite.URL != "" && c.RemoteWrite.QueueSize < 1,
+			fmt.Sprintf("remote-write queue size must be at least 1, got: %d", c.RemoteWrite.QueueSize),
+		},
+		{
+			c.RemoteWrite.Password != "" && c.RemoteWrite.Username == "",
+			"remote-write password requires a username (--remote-write.username)",
+		},
 	}
 
 	for _, rule := range validationRules {
@@ -274,6 +571,27 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for _, name := range slices.Sorted(maps.Keys(c.RemoteWrite.ExternalLabels)) {
+		if !isValidLabelName(name) {
+			return fmt.Errorf("invalid remote-write external label name: %q", name)
+		}
+		if slices.Contains(ReservedLabels, name) || name == buildInfoLabel {
+			return fmt.Errorf("remote-write external label %q collides with a label the collectors use", name)
+		}
+		if _, ok := c.Collectors.ConstLabels[name]; ok {
+			return fmt.Errorf("remote-write external label %q collides with a collector constant label", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Collectors.ConstLabels)) {
+		if !isValidLabelName(name) {
+			return fmt.Errorf("invalid collector constant label name: %q", name)
+		}
+		if slices.Contains(ReservedLabels, name) {
+			return fmt.Errorf("collector constant label %q collides with a label the collectors use", name)
+		}
+	}
+
 	// Validate collector info labels
 	if err := c.validateCollectorInfoLabels(); err != nil {
 		return fmt.Errorf("info labels validation failed: %w", err)
@@ -310,6 +628,91 @@ func isValidLogFormat(format string) bool {
 	return contains(validFormats, strings.ToLower(format))
 }
 
+// isHTTPURL checks if raw is an absolute http or https URL naming a host.
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isValidLabelName checks if name is a Prometheus label name a user may set. Names
+// starting with "__" are reserved for Prometheus itself.
+func isValidLabelName(name string) bool {
+	return labelNamePattern.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+// parseLabelPairs parses a comma-separated list of name=value pairs. An empty string
+// yields no labels.
+func parseLabelPairs(pairs string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if strings.TrimSpace(pairs) == "" {
+		return labels, nil
+	}
+
+	for pair := range strings.SplitSeq(pairs, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not a name=value pair", strings.TrimSpace(pair))
+		}
+		if _, dup := labels[name]; dup {
+			return nil, fmt.Errorf("label %q is given twice", name)
+		}
+		labels[name] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// parseConstLabels parses repeated name=value constant labels. A value is taken whole,
+// commas included.
+func parseConstLabels(pairs []string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not a name=value pair", strings.TrimSpace(pair))
+		}
+		if _, dup := labels[name]; dup {
+			return nil, fmt.Errorf("label %q is given twice", name)
+		}
+		// An empty value reads as no label at all, so it would not tell exporters apart.
+		if value = strings.TrimSpace(value); value == "" {
+			return nil, fmt.Errorf("label %q has no value", name)
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
+
+// parseMetricPatterns compiles metric family patterns, each anchored to the whole name
+// as a Prometheus relabeling regex is. Empty patterns are skipped.
+func parseMetricPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range metricPatterns(patterns) {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// metricPatterns returns the metric family patterns as given, trimmed, with the empty
+// ones skipped.
+func metricPatterns(patterns []string) []string {
+	var kept []string
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			kept = append(kept, pattern)
+		}
+	}
+	return kept
+}
+
 // contains checks if a slice contains a specific item.
 func contains(slice []string, item string) bool {
 	return slices.Contains(slice, item)
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"encoding/json"
 	"log/slog"
+	"maps"
 	"slices"
 	"strings"
 	"testing"
@@ -142,6 +144,43 @@ func TestParseInfoLabels(t *testing.T) {
 	}
 }
 
+func TestParseLabelPairs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		pairs     string
+		expected  map[string]string
+		wantError bool
+	}{
+		{"Empty string", "", map[string]string{}, false},
+		{"Single pair", "site=tokyo", map[string]string{"site": "tokyo"}, false},
+		{"Pairs with spaces", " site = tokyo , env=prod ", map[string]string{"site": "tokyo", "env": "prod"}, false},
+		{"Empty value", "site=", map[string]string{"site": ""}, false},
+		{"Missing equals", "site", nil, true},
+		{"Missing name", "=tokyo", nil, true},
+		{"Duplicate name", "site=tokyo,site=osaka", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseLabelPairs(tt.pairs)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("parseLabelPairs(%q) expected error, got %v", tt.pairs, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLabelPairs(%q) unexpected error: %v", tt.pairs, err)
+			}
+			if !maps.Equal(got, tt.expected) {
+				t.Errorf("parseLabelPairs(%q) = %v, want %v", tt.pairs, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseAPInfoLabels(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -283,10 +322,11 @@ func TestConfig_Validate(t *testing.T) {
 			TelemetryPath: "/metrics",
 		},
 		WNC: WNC{
-			Controller:  "controller.example.com",
-			AccessToken: "token123",
-			Timeout:     30 * time.Second,
-			CacheTTL:    60 * time.Second,
+			Controller:    "controller.example.com",
+			AccessToken:   "token123",
+			Timeout:       30 * time.Second,
+			CacheTTL:      60 * time.Second,
+			RetryAttempts: 3,
 		},
 		Collectors: Collectors{
 			InfoCacheTTL: 300 * time.Second,
@@ -413,6 +453,163 @@ func TestConfig_Validate(t *testing.T) {
 			true,
 			"collector info cache TTL must be positive",
 		},
+		{
+			"Invalid AP neighbors top-N",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.AP.Neighbors = true
+				cfg.Collectors.AP.NeighborsTopN = 0
+				return &cfg
+			}(),
+			true,
+			"AP neighbors top-N must be positive",
+		},
+		{
+			"Invalid WNC retry attempts",
+			func() *Config {
+				cfg := *validConfig
+				cfg.WNC.RetryAttempts = 0
+				return &cfg
+			}(),
+			true,
+			"WNC retry attempts must be at least 1",
+		},
+		{
+			"Invalid WNC retry backoff",
+			func() *Config {
+				cfg := *validConfig
+				cfg.WNC.RetryBackoff = -time.Second
+				return &cfg
+			}(),
+			true,
+			"WNC retry backoff must not be negative",
+		},
+		{
+			"Invalid WNC snapshot max age",
+			func() *Config {
+				cfg := *validConfig
+				cfg.WNC.SnapshotFile = "/var/lib/cisco-wnc-exporter/snapshot.json"
+				cfg.WNC.SnapshotMaxAge = 0
+				return &cfg
+			}(),
+			true,
+			"WNC snapshot max age must be positive",
+		},
+		{
+			"Valid tracing endpoint",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Tracing.Endpoint = "http://otel-collector:4318"
+				return &cfg
+			}(),
+			false,
+			"",
+		},
+		{
+			"Tracing endpoint without a scheme",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Tracing.Endpoint = "otel-collector:4318"
+				return &cfg
+			}(),
+			true,
+			"tracing endpoint must be an http or https URL",
+		},
+		{
+			"OTLP endpoint without a host",
+			func() *Config {
+				cfg := *validConfig
+				cfg.OTLP.Endpoint = "http:///v1/metrics"
+				return &cfg
+			}(),
+			true,
+			"OTLP endpoint must be an http or https URL",
+		},
+		{
+			"Remote-write queue size zero",
+			func() *Config {
+				cfg := *validConfig
+				cfg.RemoteWrite.URL = "https://prometheus.example.com/api/v1/write"
+				return &cfg
+			}(),
+			true,
+			"remote-write queue size must be at least 1",
+		},
+		{
+			"Remote-write password without username",
+			func() *Config {
+				cfg := *validConfig
+				cfg.RemoteWrite.URL = "https://prometheus.example.com/api/v1/write"
+				cfg.RemoteWrite.QueueSize = 10
+				cfg.RemoteWrite.Password = "secret"
+				return &cfg
+			}(),
+			true,
+			"remote-write password requires a username",
+		},
+		{
+			"Remote-write reserved external label",
+			func() *Config {
+				cfg := *validConfig
+				cfg.RemoteWrite.ExternalLabels = map[string]string{"__name__": "x"}
+				return &cfg
+			}(),
+			true,
+			"invalid remote-write external label name",
+		},
+		{
+			"Remote-write external label colliding with a collector label",
+			func() *Config {
+				cfg := *validConfig
+				cfg.RemoteWrite.ExternalLabels = map[string]string{"site": "tokyo", "mac": "x"}
+				return &cfg
+			}(),
+			true,
+			`remote-write external label "mac" collides with a label the collectors use`,
+		},
+		{
+			"Remote-write external label colliding with the build information",
+			func() *Config {
+				cfg := *validConfig
+				cfg.RemoteWrite.ExternalLabels = map[string]string{"version": "x"}
+				return &cfg
+			}(),
+			true,
+			`remote-write external label "version" collides with a label the collectors use`,
+		},
+		{
+			"Remote-write external label colliding with a constant label",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.ConstLabels = map[string]string{"site": "tokyo"}
+				cfg.RemoteWrite.ExternalLabels = map[string]string{"site": "osaka"}
+				return &cfg
+			}(),
+			true,
+			`remote-write external label "site" collides with a collector constant label`,
+		},
+		{
+			"Invalid AP departed retention",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.AP.Departed = true
+				cfg.Collectors.AP.DepartedRetention = 0
+				return &cfg
+			}(),
+			true,
+			"AP departed retention must be positive",
+		},
+		{
+			"Invalid WLAN applications top-N",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.WLAN.Applications = true
+				cfg.Collectors.WLAN.ApplicationsTopN = 0
+				return &cfg
+			}(),
+			true,
+			"WLAN applications top-N must be positive",
+		},
 		{
 			"Empty telemetry path",
 			func() *Config {
@@ -501,6 +698,46 @@ func TestConfig_Validate(t *testing.T) {
 			true,
 			"telemetry path must not be " + HealthPath,
 		},
+		{
+			"Constant label",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.ConstLabels = map[string]string{"site": "tokyo", "controller": "wnc1"}
+				return &cfg
+			}(),
+			false,
+			"",
+		},
+		{
+			"Constant label colliding with a collector label",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.ConstLabels = map[string]string{"site": "tokyo", "mac": "x"}
+				return &cfg
+			}(),
+			true,
+			`collector constant label "mac" collides`,
+		},
+		{
+			"Invalid constant label name",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Collectors.ConstLabels = map[string]string{"__site": "tokyo"}
+				return &cfg
+			}(),
+			true,
+			"invalid collector constant label name",
+		},
+		{
+			"Telemetry path taking the catalog path",
+			func() *Config {
+				cfg := *validConfig
+				cfg.Web.TelemetryPath = CatalogPath
+				return &cfg
+			}(),
+			true,
+			"telemetry path must not be " + CatalogPath,
+		},
 		{
 			// The root is accepted: the server drops the landing page instead.
 			"Telemetry path at the root",
@@ -619,6 +856,30 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+// TestConfig_ValidateOffline verifies that a command that never reaches the controller
+// runs without one, but still has the rest of the configuration validated.
+func TestConfig_ValidateOffline(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		Web:        Web{ListenAddress: "0.0.0.0", ListenPort: 8080, TelemetryPath: "/metrics"},
+		WNC:        WNC{Timeout: 30 * time.Second, CacheTTL: 60 * time.Second, RetryAttempts: 3},
+		Collectors: Collectors{InfoCacheTTL: 300 * time.Second},
+		Log:        Log{Level: "info", Format: "json"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() without a controller expected error, got nil")
+	}
+	if err := cfg.validate(false); err != nil {
+		t.Errorf("validate(false) unexpected error: %v", err)
+	}
+
+	cfg.Log.Level = "verbose"
+	if err := cfg.validate(false); err == nil {
+		t.Error("validate(false) with an invalid log level expected error, got nil")
+	}
+}
+
 func TestConfig_ValidateCollectorInfoLabels(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -732,6 +993,108 @@ func (m *mockCommand) Duration(name string) time.Duration {
 	return 0
 }
 
+func TestParseConstLabels(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		pairs     []string
+		expected  map[string]string
+		wantError bool
+	}{
+		{"None", nil, map[string]string{}, false},
+		{"Two labels", []string{"site=tokyo", " region = apac "}, map[string]string{"site": "tokyo", "region": "apac"}, false},
+		{"Comma in value", []string{"site=tokyo,hq"}, map[string]string{"site": "tokyo,hq"}, false},
+		{"Missing equals", []string{"site"}, nil, true},
+		{"Missing name", []string{"=tokyo"}, nil, true},
+		{"Missing value", []string{"site="}, nil, true},
+		{"Duplicate name", []string{"site=tokyo", "site=osaka"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseConstLabels(tt.pairs)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("parseConstLabels(%q) expected error, got %v", tt.pairs, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConstLabels(%q) unexpected error: %v", tt.pairs, err)
+			}
+			if !maps.Equal(got, tt.expected) {
+				t.Errorf("parseConstLabels(%q) = %v, want %v", tt.pairs, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMetricPatterns(t *testing.T) {
+	t.Parallel()
+
+	patterns, err := parseMetricPatterns([]string{"wnc_ap_(fcs|mic)_errors_total", " ", "wnc_client_.{1,3}"})
+	if err != nil {
+		t.Fatalf("parseMetricPatterns() unexpected error: %v", err)
+	}
+	if len(patterns) != 2 {
+		t.Fatalf("parseMetricPatterns() = %v, want 2 patterns", patterns)
+	}
+
+	if _, err := parseMetricPatterns([]string{"wnc_ap_("}); err == nil {
+		t.Error("parseMetricPatterns() with an invalid pattern expected error, got nil")
+	}
+}
+
+// TestCollectors_MetricPatternsJSON keeps the patterns readable in a dump of the
+// configuration, which a compiled pattern would render as an empty object.
+func TestCollectors_MetricPatternsJSON(t *testing.T) {
+	t.Parallel()
+
+	given := []string{" wnc_ap_.*", "", "wnc_client_rssi_dbm"}
+	include, err := parseMetricPatterns(given)
+	if err != nil {
+		t.Fatalf("parseMetricPatterns() unexpected error: %v", err)
+	}
+	dumped, err := json.Marshal(Collectors{MetricsInclude: include, MetricsIncludePatterns: metricPatterns(given)})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	if want := `"metrics_include":["wnc_ap_.*","wnc_client_rssi_dbm"]`; !strings.Contains(string(dumped), want) {
+		t.Errorf("json.Marshal() = %s, want it to contain %s", dumped, want)
+	}
+}
+
+func TestCollectors_MetricAllowed(t *testing.T) {
+	t.Parallel()
+
+	include, _ := parseMetricPatterns([]string{"wnc_ap_.*"})
+	exclude, _ := parseMetricPatterns([]string{"wnc_ap_.*_total"})
+	tests := []struct {
+		name       string
+		collectors Collectors
+		metric     string
+		want       bool
+	}{
+		{"No pattern", Collectors{}, "wnc_ap_fcs_errors_total", true},
+		{"Included", Collectors{MetricsInclude: include}, "wnc_ap_channel", true},
+		{"Not included", Collectors{MetricsInclude: include}, "wnc_client_rssi_dbm", false},
+		{"Anchored", Collectors{MetricsInclude: include}, "x_wnc_ap_channel", false},
+		{"Excluded", Collectors{MetricsExclude: exclude}, "wnc_ap_fcs_errors_total", false},
+		{"Included then excluded", Collectors{MetricsInclude: include, MetricsExclude: exclude},
+			"wnc_ap_fcs_errors_total", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.collectors.MetricAllowed(tt.metric); got != tt.want {
+				t.Errorf("MetricAllowed(%q) = %t, want %t", tt.metric, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParse(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -751,6 +1114,8 @@ func TestParse(t *testing.T) {
 				"wnc.timeout":                   30 * time.Second,
 				"wnc.cache-ttl":                 60 * time.Second,
 				"wnc.tls-skip-verify":           false,
+				"wnc.retry-attempts":            3,
+				"wnc.retry-backoff":             time.Second,
 				"collector.ap.general":          true,
 				"collector.ap.radio":            false,
 				"collector.ap.traffic":          false,
@@ -864,6 +1229,8 @@ func testParse(cmd *mockCommand) (*Config, error) {
 			Timeout:       cmd.Duration("wnc.timeout"),
 			CacheTTL:      cmd.Duration("wnc.cache-ttl"),
 			TLSSkipVerify: cmd.Bool("wnc.tls-skip-verify"),
+			RetryAttempts: cmd.Int("wnc.retry-attempts"),
+			RetryBackoff:  cmd.Duration("wnc.retry-backoff"),
 		},
 		Collectors: Collectors{
 			AP: APCollectorModules{
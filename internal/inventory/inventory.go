@@ -0,0 +1,314 @@
+// Package inventory provides the inventory queries behind the aps, clients and wlans
+// subcommands: one synchronous refresh, read back through the same sources the
+// collectors read, and printed as a table instead of published as series.
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/wlan"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// Query is one inventory a subcommand prints.
+type Query struct {
+	// Name is the subcommand the query runs as.
+	Name string
+	// Usage describes the subcommand.
+	Usage string
+	// DefaultSort is the column the rows are sorted by when no other is asked for.
+	DefaultSort string
+	// modules are the collector modules whose data types the query reads. They are
+	// the query's own, so it reads the same data whichever modules the exporter is
+	// configured with.
+	modules config.Collectors
+	build   func(ctx context.Context, src wnc.DataSource) (*Table, error)
+}
+
+// Queries lists every inventory query, in the order the subcommands are listed.
+var Queries = []Query{
+	{
+		Name:        "aps",
+		Usage:       "List the APs the controller has joined, with model, serial and software version",
+		DefaultSort: "name",
+		modules:     config.Collectors{AP: config.APCollectorModules{Info: true}},
+		build: func(ctx context.Context, src wnc.DataSource) (*Table, error) {
+			return apTable(ctx, wnc.NewAPSource(src))
+		},
+	},
+	{
+		Name:        "clients",
+		Usage:       "List the clients the controller knows, with AP, WLAN, RSSI and SNR",
+		DefaultSort: "mac",
+		modules:     config.Collectors{Client: config.ClientCollectorModules{Radio: true, Info: true}},
+		build: func(ctx context.Context, src wnc.DataSource) (*Table, error) {
+			return clientTable(ctx, wnc.NewClientSource(src))
+		},
+	},
+	{
+		Name:        "wlans",
+		Usage:       "List the configured WLANs, with security, policy profiles and client count",
+		DefaultSort: "id",
+		modules:     config.Collectors{WLAN: config.WLANCollectorModules{Config: true, Traffic: true}},
+		build: func(ctx context.Context, src wnc.DataSource) (*Table, error) {
+			return wlanTable(ctx, wnc.NewWLANSource(src), wnc.NewClientSource(src))
+		},
+	},
+}
+
+// Run refreshes the data types the query reads once, waiting for the refresh, and
+// builds the table from the snapshot it published. The query reads into a data source
+// of its own, which restores nothing from the snapshot file.
+func (q Query) Run(ctx context.Context, cfg config.WNC) (*Table, error) {
+	cfg.SnapshotFile = ""
+	source := wnc.NewDataSource(cfg, q.modules)
+
+	refresher, ok := source.(wnc.SyncRefresher)
+	if !ok {
+		return nil, errors.New("WNC data source cannot be refreshed synchronously")
+	}
+	if err := refresher.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("WNC data refresh failed: %w", err)
+	}
+
+	return q.build(ctx, source)
+}
+
+// apTable lists one row per AP the controller has joined.
+func apTable(ctx context.Context, src wnc.APSource) (*Table, error) {
+	capwapData, err := src.GetCAPWAPData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading APs failed: %w", err)
+	}
+
+	// The radio count is only a convenience, so a failed read leaves it blank
+	// rather than failing the query.
+	var radios map[string]int
+	if radioData, err := src.GetRadioData(ctx); err != nil {
+		slog.Warn("Reading AP radios failed", "error", err)
+	} else {
+		radios = make(map[string]int)
+		for _, radio := range radioData {
+			radios[radio.WtpMAC]++
+		}
+	}
+
+	table := &Table{Columns: []Column{
+		{Name: "name"}, {Name: "mac"}, {Name: "ip"}, {Name: "model"}, {Name: "serial"},
+		{Name: "sw_version"}, {Name: "state"}, {Name: "radios", Numeric: true},
+		{Name: "boot_time"}, {Name: "join_time"},
+	}}
+	for i := range capwapData {
+		data := &capwapData[i]
+		radioCount := ""
+		if radios != nil {
+			radioCount = strconv.Itoa(radios[data.WtpMAC])
+		}
+		table.Rows = append(table.Rows, []string{
+			data.Name,
+			data.WtpMAC,
+			data.IPAddr,
+			data.DeviceDetail.StaticInfo.ApModels.Model,
+			data.DeviceDetail.StaticInfo.BoardData.WtpSerialNum,
+			data.DeviceDetail.WtpVersion.SwVersion,
+			data.ApState.ApOperationState,
+			radioCount,
+			data.ApTimeInfo.BootTime,
+			data.ApTimeInfo.JoinTime,
+		})
+	}
+	return table, nil
+}
+
+// clientTable lists one row per client the controller knows, associated or not.
+func clientTable(ctx context.Context, src wnc.ClientSource) (*Table, error) {
+	clients, err := src.GetClientData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading clients failed: %w", err)
+	}
+
+	// Each of these only fills columns in, so a failed read leaves them blank rather
+	// than failing the query.
+	dot11 := make(map[string]client.Dot11OperData)
+	if data, err := src.GetDot11Data(ctx); err != nil {
+		slog.Warn("Reading client association data failed", "error", err)
+	} else {
+		for _, record := range data {
+			dot11[record.MsMACAddress] = record
+		}
+	}
+	traffic := make(map[string]client.TrafficStats)
+	if data, err := src.GetTrafficStats(ctx); err != nil {
+		slog.Warn("Reading client traffic statistics failed", "error", err)
+	} else {
+		for _, record := range data {
+			traffic[record.MsMACAddress] = record
+		}
+	}
+	devices := make(map[string]client.DcInfo)
+	if data, err := src.GetDeviceData(ctx); err != nil {
+		slog.Warn("Reading client device classification failed", "error", err)
+	} else {
+		for _, record := range data {
+			devices[record.ClientMAC] = record
+		}
+	}
+	sisf := make(map[string]client.SisfDBMac)
+	if data, err := src.GetSISFDBData(ctx); err != nil {
+		slog.Warn("Reading client addresses failed", "error", err)
+	} else {
+		for _, record := range data {
+			sisf[record.MACAddr] = record
+		}
+	}
+
+	table := &Table{Columns: []Column{
+		{Name: "mac"}, {Name: "name"}, {Name: "ipv4"}, {Name: "username"}, {Name: "ap"},
+		{Name: "radio", Numeric: true}, {Name: "band"}, {Name: "channel", Numeric: true},
+		{Name: "wlan"}, {Name: "state"}, {Name: "rssi", Numeric: true}, {Name: "snr", Numeric: true},
+		{Name: "speed", Numeric: true}, {Name: "streams", Numeric: true},
+	}}
+	for _, data := range clients {
+		assoc, associated := dot11[data.ClientMAC]
+		stats, measured := traffic[data.ClientMAC]
+
+		channel, wlanName := "", ""
+		if associated {
+			channel, wlanName = strconv.Itoa(assoc.CurrentChannel), assoc.VapSsid
+		}
+		rssi, snr, speed, streams := "", "", "", ""
+		if measured {
+			rssi, snr = strconv.Itoa(stats.MostRecentRSSI), strconv.Itoa(stats.MostRecentSNR)
+			speed, streams = strconv.Itoa(stats.Speed), strconv.Itoa(stats.SpatialStream)
+		}
+
+		table.Rows = append(table.Rows, []string{
+			data.ClientMAC,
+			devices[data.ClientMAC].DeviceName,
+			sisf[data.ClientMAC].Ipv4Binding.IPKey.IPAddr,
+			data.Username,
+			data.ApName,
+			strconv.Itoa(data.MsApSlotID),
+			bandName(collector.ClientBand(data)),
+			channel,
+			wlanName,
+			data.CoState,
+			rssi,
+			snr,
+			speed,
+			streams,
+		})
+	}
+	return table, nil
+}
+
+// wlanTable lists one row per configured WLAN, with the policy profiles the policy tags
+// bind it to and the clients associated on it.
+func wlanTable(ctx context.Context, src wnc.WLANSource, clientSrc wnc.ClientSource) (*Table, error) {
+	entries, err := src.ListConfigEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading WLANs failed: %w", err)
+	}
+
+	// A WLAN bound by several tags is listed once per distinct policy profile.
+	policies := make(map[string][]string)
+	if tags, err := src.ListPolicyListEntries(ctx); err != nil {
+		slog.Warn("Reading policy tags failed", "error", err)
+	} else {
+		for _, tag := range tags {
+			if tag.WLANPolicies == nil {
+				continue
+			}
+			for _, mapping := range tag.WLANPolicies.WLANPolicy {
+				bound := policies[mapping.WLANProfileName]
+				if !slices.Contains(bound, mapping.PolicyProfileName) {
+					policies[mapping.WLANProfileName] = append(bound, mapping.PolicyProfileName)
+				}
+			}
+		}
+	}
+
+	// The count is of clients in the run state, as wnc_wlan_clients counts them.
+	var clients map[int]int
+	if data, err := clientSrc.GetClientData(ctx); err != nil {
+		slog.Warn("Reading clients failed", "error", err)
+	} else {
+		clients = make(map[int]int)
+		for _, record := range data {
+			if record.CoState == collector.ClientStatusRun {
+				clients[record.WlanID]++
+			}
+		}
+	}
+
+	table := &Table{Columns: []Column{
+		{Name: "id", Numeric: true}, {Name: "profile"}, {Name: "ssid"}, {Name: "enabled"},
+		{Name: "security"}, {Name: "policy_profiles"}, {Name: "clients", Numeric: true},
+	}}
+	for _, entry := range entries {
+		ssid, enabled := "", "false"
+		if entry.APFVapIDData != nil {
+			ssid, enabled = entry.APFVapIDData.SSID, strconv.FormatBool(entry.APFVapIDData.WlanStatus)
+		}
+		clientCount := ""
+		if clients != nil {
+			clientCount = strconv.Itoa(clients[entry.WlanID])
+		}
+
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(entry.WlanID),
+			entry.ProfileName,
+			ssid,
+			enabled,
+			wlanSecurity(entry),
+			strings.Join(policies[entry.ProfileName], " "),
+			clientCount,
+		})
+	}
+	return table, nil
+}
+
+// wlanSecurity summarizes the WPA versions and key management a WLAN allows, such as
+// "wpa2+wpa3/dot1x", or "open" for a WLAN with neither WPA version.
+func wlanSecurity(entry wlan.WlanCfgEntry) string {
+	var versions, akms []string
+	if entry.WPA2Enabled {
+		versions = append(versions, "wpa2")
+	}
+	if entry.WPA3Enabled {
+		versions = append(versions, "wpa3")
+	}
+	if len(versions) == 0 {
+		return "open"
+	}
+	if entry.AuthKeyMgmtPsk {
+		akms = append(akms, "psk")
+	}
+	if entry.AuthKeyMgmtDot1x || entry.AuthKeyMgmtDot1xSha256 {
+		akms = append(akms, "dot1x")
+	}
+	security := strings.Join(versions, "+")
+	if len(akms) > 0 {
+		security += "/" + strings.Join(akms, "+")
+	}
+	return security
+}
+
+// bandName spells a band as the band label of the client series does, so a filter
+// copied from a query selects the same clients, and leaves a client with no band blank.
+func bandName(band string) string {
+	if band == collector.BandUnknown {
+		return ""
+	}
+	return band
+}
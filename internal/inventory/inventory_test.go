@@ -0,0 +1,283 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/wlan"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+type fixtureSource struct {
+	data *wnc.WNCDataCache
+}
+
+func (f fixtureSource) GetCachedData(context.Context) (*wnc.WNCDataCache, error) {
+	return f.data, nil
+}
+
+func capwap(name, mac, model, version string) ap.CAPWAPData {
+	data := ap.CAPWAPData{Name: name, WtpMAC: mac}
+	data.DeviceDetail.StaticInfo.ApModels.Model = model
+	data.DeviceDetail.WtpVersion.SwVersion = version
+	return data
+}
+
+// column returns one column of the table, row by row.
+func column(t *testing.T, table *Table, name string) []string {
+	t.Helper()
+
+	index, err := table.column(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([]string, len(table.Rows))
+	for i, row := range table.Rows {
+		values[i] = row[index]
+	}
+	return values
+}
+
+// TestAPTable_FilterBySoftwareVersion covers the question the subcommand is for: which
+// APs of a model still run an old release.
+func TestAPTable_FilterBySoftwareVersion(t *testing.T) {
+	t.Parallel()
+
+	src := fixtureSource{data: &wnc.WNCDataCache{
+		CAPWAPData: []ap.CAPWAPData{
+			capwap("ap-3", "00:00:00:00:00:03", "C9130AXI-B", "17.9.4"),
+			capwap("ap-1", "00:00:00:00:00:01", "C9130AXI-B", "17.12.3"),
+			capwap("ap-2", "00:00:00:00:00:02", "C9120AXI-B", "17.9.4"),
+			capwap("ap-4", "00:00:00:00:00:04", "C9130AXE-B", "17.9.5"),
+		},
+		RadioOperData: []ap.RadioOperData{
+			{WtpMAC: "00:00:00:00:00:03", RadioSlotID: 0},
+			{WtpMAC: "00:00:00:00:00:03", RadioSlotID: 1},
+		},
+	}}
+
+	table, err := apTable(context.Background(), wnc.NewAPSource(src))
+	if err != nil {
+		t.Fatalf("apTable() error = %v", err)
+	}
+	filters, err := ParseFilters("model=C9130*, sw_version=17.9.*")
+	if err != nil {
+		t.Fatalf("ParseFilters() error = %v", err)
+	}
+	if err := table.Filter(filters); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if err := table.Sort("name"); err != nil {
+		t.Fatalf("Sort() error = %v", err)
+	}
+
+	if got := column(t, table, "name"); !slices.Equal(got, []string{"ap-3", "ap-4"}) {
+		t.Errorf("names = %v, want [ap-3 ap-4]", got)
+	}
+	if got := column(t, table, "radios"); !slices.Equal(got, []string{"2", "0"}) {
+		t.Errorf("radios = %v, want [2 0]", got)
+	}
+}
+
+// TestClientTable_SortBySignal lists the clients of one AP weakest first, and leaves the
+// signal of a client with no traffic statistics blank rather than zero, which would sort
+// it as the strongest.
+func TestClientTable_SortBySignal(t *testing.T) {
+	t.Parallel()
+
+	src := fixtureSource{data: &wnc.WNCDataCache{
+		CommonOperData: []client.CommonOperData{
+			{ClientMAC: "aa:00:00:00:00:01", ApName: "ap-1", MsRadioType: "client-dot11ax-5ghz-prot"},
+			{ClientMAC: "aa:00:00:00:00:02", ApName: "ap-1", MsRadioType: "client-dot11ax-5ghz-prot"},
+			{ClientMAC: "aa:00:00:00:00:03", ApName: "ap-2"},
+			{ClientMAC: "aa:00:00:00:00:04", ApName: "ap-1"},
+		},
+		Dot11OperData: []client.Dot11OperData{
+			{MsMACAddress: "aa:00:00:00:00:01", VapSsid: "corp", CurrentChannel: 36},
+		},
+		TrafficStats: []client.TrafficStats{
+			{MsMACAddress: "aa:00:00:00:00:01", MostRecentRSSI: -50, MostRecentSNR: 40},
+			{MsMACAddress: "aa:00:00:00:00:02", MostRecentRSSI: -72, MostRecentSNR: 18},
+			{MsMACAddress: "aa:00:00:00:00:03", MostRecentRSSI: -80, MostRecentSNR: 10},
+		},
+	}}
+
+	table, err := clientTable(context.Background(), wnc.NewClientSource(src))
+	if err != nil {
+		t.Fatalf("clientTable() error = %v", err)
+	}
+	filters, _ := ParseFilters("ap=ap-1")
+	if err := table.Filter(filters); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if err := table.Sort("rssi"); err != nil {
+		t.Fatalf("Sort() error = %v", err)
+	}
+
+	if got := column(t, table, "rssi"); !slices.Equal(got, []string{"-72", "-50", ""}) {
+		t.Errorf("rssi = %v, want [-72 -50 <blank>]", got)
+	}
+	if got := column(t, table, "band"); !slices.Equal(got, []string{"5", "5", ""}) {
+		t.Errorf("band = %v, want [5 5 <blank>]", got)
+	}
+
+	if err := table.Sort("-snr"); err != nil {
+		t.Fatalf("Sort() error = %v", err)
+	}
+	if got := column(t, table, "snr"); !slices.Equal(got, []string{"40", "18", ""}) {
+		t.Errorf("snr descending = %v, want [40 18 <blank>]", got)
+	}
+}
+
+// TestWLANTable_PolicyProfilesAndClients joins a WLAN to the policy profiles every tag
+// binds it to, and counts only the clients in the run state.
+func TestWLANTable_PolicyProfilesAndClients(t *testing.T) {
+	t.Parallel()
+
+	src := fixtureSource{data: &wnc.WNCDataCache{
+		WLANConfigEntries: []wlan.WlanCfgEntry{
+			{ProfileName: "corp", WlanID: 1, WPA2Enabled: true, WPA3Enabled: true, AuthKeyMgmtDot1x: true,
+				APFVapIDData: &wlan.APFVapIDData{SSID: "Corp", WlanStatus: true}},
+			{ProfileName: "guest", WlanID: 2},
+		},
+		WLANPolicyListEntries: []wlan.PolicyListEntry{
+			{TagName: "site-a", WLANPolicies: &wlan.WLANPolicies{WLANPolicy: []wlan.WLANPolicyMap{
+				{WLANProfileName: "corp", PolicyProfileName: "corp-local"},
+			}}},
+			{TagName: "site-b", WLANPolicies: &wlan.WLANPolicies{WLANPolicy: []wlan.WLANPolicyMap{
+				{WLANProfileName: "corp", PolicyProfileName: "corp-central"},
+				{WLANProfileName: "corp", PolicyProfileName: "corp-local"},
+			}}},
+		},
+		CommonOperData: []client.CommonOperData{
+			{ClientMAC: "aa:00:00:00:00:01", WlanID: 1, CoState: "client-status-run"},
+			{ClientMAC: "aa:00:00:00:00:02", WlanID: 1, CoState: "client-status-authenticating"},
+		},
+	}}
+
+	table, err := wlanTable(context.Background(), wnc.NewWLANSource(src), wnc.NewClientSource(src))
+	if err != nil {
+		t.Fatalf("wlanTable() error = %v", err)
+	}
+
+	want := [][]string{
+		{"1", "corp", "Corp", "true", "wpa2+wpa3/dot1x", "corp-local corp-central", "1"},
+		{"2", "guest", "", "false", "open", "", "0"},
+	}
+	if !slices.EqualFunc(table.Rows, want, slices.Equal) {
+		t.Errorf("rows = %q, want %q", table.Rows, want)
+	}
+}
+
+// TestAPTable_FailedList fails the query when the list it is keyed by failed, instead of
+// printing an empty inventory that reads as no APs.
+func TestAPTable_FailedList(t *testing.T) {
+	t.Parallel()
+
+	failed := errors.New("request failed")
+	src := fixtureSource{data: &wnc.WNCDataCache{
+		FetchErrors: map[string]error{"ap_capwap_data": failed},
+	}}
+	if _, err := apTable(context.Background(), wnc.NewAPSource(src)); !errors.Is(err, failed) {
+		t.Errorf("apTable() error = %v, want %v", err, failed)
+	}
+}
+
+// TestTable_FilterMatchesAcrossSlash pins that a glob is not a path pattern: the WLAN
+// security column spells its values with a "/", and "*" and "?" match it.
+func TestTable_FilterMatchesAcrossSlash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"security=wpa2*", []string{"wpa2/psk", "wpa2/802.1x"}},
+		{"security=wpa?/sae", []string{"wpa3/sae"}},
+		{"security=wpa2/802.1x", []string{"wpa2/802.1x"}},
+		{"security=wpa[^2]*", []string{"wpa3/sae"}},
+		{"security=*.1?", []string{"wpa2/802.1x"}},
+		{`security=\*`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			t.Parallel()
+
+			table := &Table{
+				Columns: []Column{{Name: "security"}},
+				Rows:    [][]string{{"wpa2/psk"}, {"wpa2/802.1x"}, {"wpa3/sae"}, {"open"}},
+			}
+			filters, err := ParseFilters(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilters() error = %v", err)
+			}
+			if err := table.Filter(filters); err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if got := column(t, table, "security"); !slices.Equal(got, tt.want) {
+				t.Errorf("Filter(%s) kept %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilters_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"model", "=C9130", "model=[C9130", "model=[]", `model=C9130\`} {
+		if _, err := ParseFilters(value); !errors.Is(err, errInvalidFilter) {
+			t.Errorf("ParseFilters(%q) error = %v, want %v", value, err, errInvalidFilter)
+		}
+	}
+}
+
+func TestTable_UnknownColumn(t *testing.T) {
+	t.Parallel()
+
+	table := &Table{Columns: []Column{{Name: "name"}}}
+	if err := table.Sort("-model"); !errors.Is(err, errUnknownColumn) {
+		t.Errorf("Sort() error = %v, want %v", err, errUnknownColumn)
+	}
+	if err := table.Filter([]Filter{{Column: "model", Pattern: "*"}}); !errors.Is(err, errUnknownColumn) {
+		t.Errorf("Filter() error = %v, want %v", err, errUnknownColumn)
+	}
+}
+
+func TestTable_Write(t *testing.T) {
+	t.Parallel()
+
+	table := &Table{
+		Columns: []Column{{Name: "name"}, {Name: "rssi", Numeric: true}},
+		Rows:    [][]string{{"phone", "-61"}, {"laptop", ""}},
+	}
+
+	var buf bytes.Buffer
+	if err := table.Write(&buf, FormatTable); err != nil {
+		t.Fatalf("Write(table) error = %v", err)
+	}
+	wantTable := "NAME    RSSI\nphone   -61\nlaptop  -\n"
+	if buf.String() != wantTable {
+		t.Errorf("Write(table) =\n%s\nwant\n%s", buf.String(), wantTable)
+	}
+
+	buf.Reset()
+	if err := table.Write(&buf, FormatJSON); err != nil {
+		t.Fatalf("Write(json) error = %v", err)
+	}
+	wantJSON := "[\n  {\"name\": \"phone\", \"rssi\": -61},\n  {\"name\": \"laptop\", \"rssi\": null}\n]\n"
+	if buf.String() != wantJSON {
+		t.Errorf("Write(json) =\n%s\nwant\n%s", buf.String(), wantJSON)
+	}
+
+	if err := table.Write(&buf, "yaml"); !errors.Is(err, errUnknownFormat) || !strings.Contains(err.Error(), "yaml") {
+		t.Errorf("Write(yaml) error = %v, want %v", err, errUnknownFormat)
+	}
+}
@@ -0,0 +1,331 @@
+// Package inventory provides the inventory queries behind the aps, clients and wlans
+// subcommands: one synchronous refresh, read back through the same sources the
+// collectors read, and printed as a table instead of published as series.
+// This file holds the table the queries produce and its filtering, sorting and output.
+package inventory
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// Output formats.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// Formats lists the output formats Write accepts.
+var Formats = []string{FormatTable, FormatJSON}
+
+var (
+	// errUnknownColumn is returned for a filter or sort naming no column of the table.
+	errUnknownColumn = errors.New("unknown column")
+	// errInvalidFilter is returned for a filter not written as column=pattern.
+	errInvalidFilter = errors.New("invalid filter")
+	// errUnknownFormat is returned by Write for a format not in Formats.
+	errUnknownFormat = errors.New("unknown output format")
+	// errBadPattern is returned for a pattern with an unterminated class or escape.
+	errBadPattern = errors.New("syntax error in pattern")
+)
+
+// Column is one column of a table.
+type Column struct {
+	Name string
+	// Numeric columns sort by value and are numbers in the JSON output.
+	Numeric bool
+}
+
+// Table is the result of a query: one row per AP, client or WLAN, each cell already
+// formatted. An empty cell is a value the controller did not report.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// Filter is one column=pattern condition. The pattern is a glob matched against the
+// whole cell, so "C9130*" selects a model family and a plain value an exact match. "*"
+// and "?" match any character, "/" included, since a cell such as a WLAN's security is
+// not a path: "wpa2*" selects "wpa2/psk".
+type Filter struct {
+	Column  string
+	Pattern string
+}
+
+// ParseFilters parses a --filter value: comma-separated conditions a row has to meet
+// all of.
+func ParseFilters(value string) ([]Filter, error) {
+	var filters []Filter
+	for _, condition := range strings.Split(value, ",") {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+		column, pattern, ok := strings.Cut(condition, "=")
+		if !ok || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("%w %q: want column=pattern", errInvalidFilter, condition)
+		}
+		if _, err := globRegexp(pattern); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", errInvalidFilter, condition, err)
+		}
+		filters = append(filters, Filter{Column: strings.TrimSpace(column), Pattern: pattern})
+	}
+	return filters, nil
+}
+
+// Filter drops the rows that do not meet every filter.
+func (t *Table) Filter(filters []Filter) error {
+	indexes := make([]int, len(filters))
+	patterns := make([]*regexp.Regexp, len(filters))
+	for i, filter := range filters {
+		index, err := t.column(filter.Column)
+		if err != nil {
+			return err
+		}
+		indexes[i] = index
+
+		if patterns[i], err = globRegexp(filter.Pattern); err != nil {
+			return fmt.Errorf("%w %q: %w", errInvalidFilter, filter.Pattern, err)
+		}
+	}
+
+	t.Rows = slices.DeleteFunc(t.Rows, func(row []string) bool {
+		for i, pattern := range patterns {
+			if !pattern.MatchString(row[indexes[i]]) {
+				return true
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// globRegexp translates a glob into a regular expression anchored at both ends. It
+// reads the syntax of path.Match — "*", "?", "[class]" with "^" negating it and "\"
+// escaping the next character — except that no character is a separator.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, errBadPattern
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end, class, err := globClass(pattern, i+1)
+			if err != nil {
+				return nil, err
+			}
+			expr.WriteString(class)
+			i = end
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// globClass translates the class that starts after the "[" at start and returns the
+// index of its closing "]" with the class as a regular expression. Every ASCII
+// character but a letter, a digit and the "-" of a range is escaped, so the class
+// means in the regular expression what it meant in the glob.
+func globClass(pattern string, start int) (int, string, error) {
+	var class strings.Builder
+	class.WriteString("[")
+
+	i := start
+	if i < len(pattern) && pattern[i] == '^' {
+		class.WriteString("^")
+		i++
+	}
+	first := i
+
+	for ; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == ']' && i > first:
+			class.WriteString("]")
+			return i, class.String(), nil
+		case c == ']':
+			return 0, "", errBadPattern
+		case c == '\\':
+			i++
+			if i == len(pattern) {
+				return 0, "", errBadPattern
+			}
+			c = pattern[i]
+		case c == '-':
+			class.WriteByte(c)
+			continue
+		}
+
+		if c < utf8.RuneSelf && !isAlphanumeric(c) {
+			class.WriteByte('\\')
+		}
+		class.WriteByte(c)
+	}
+
+	return 0, "", errBadPattern
+}
+
+// isAlphanumeric reports whether an ASCII byte is a letter or a digit.
+func isAlphanumeric(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// Sort orders the rows by a column, descending when the name is prefixed with "-".
+// Numeric columns sort by value, and a row with no value sorts last either way. Rows
+// equal in the column keep the order the query gave them.
+func (t *Table) Sort(by string) error {
+	name, descending := strings.CutPrefix(by, "-")
+	index, err := t.column(name)
+	if err != nil {
+		return err
+	}
+	numeric := t.Columns[index].Numeric
+
+	slices.SortStableFunc(t.Rows, func(a, b []string) int {
+		x, y := a[index], b[index]
+		switch {
+		case x == "" && y == "":
+			return 0
+		case x == "":
+			return 1
+		case y == "":
+			return -1
+		}
+
+		order := strings.Compare(x, y)
+		if numeric {
+			fx, _ := strconv.ParseFloat(x, 64)
+			fy, _ := strconv.ParseFloat(y, 64)
+			order = cmp.Compare(fx, fy)
+		}
+		if descending {
+			return -order
+		}
+		return order
+	})
+	return nil
+}
+
+// Write writes the table to w in the given format.
+func (t *Table) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatTable:
+		return t.writeTable(w)
+	case FormatJSON:
+		return t.writeJSON(w)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+}
+
+// writeTable prints the rows as aligned columns under an upper-case header, with "-"
+// for a cell the controller did not report.
+func (t *Table) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = strings.ToUpper(column.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	cells := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if cell == "" {
+				cell = "-"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// writeJSON writes the rows as an array of objects keyed by column name, in column
+// order. A cell the controller did not report is null.
+func (t *Table) writeJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range t.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, column := range t.Columns {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			key, err := json.Marshal(column.Name)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteString(": ")
+
+			value, err := jsonCell(row[j], column.Numeric)
+			if err != nil {
+				return err
+			}
+			buf.Write(value)
+		}
+		buf.WriteString("}")
+	}
+	if len(t.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// jsonCell encodes one cell: null when empty, a number in a numeric column, and a
+// string otherwise.
+func jsonCell(cell string, numeric bool) ([]byte, error) {
+	if cell == "" {
+		return []byte("null"), nil
+	}
+	if numeric {
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			return []byte(cell), nil
+		}
+	}
+	return json.Marshal(cell)
+}
+
+// column returns the index of the named column.
+func (t *Table) column(name string) (int, error) {
+	names := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		if column.Name == name {
+			return i, nil
+		}
+		names[i] = column.Name
+	}
+	return 0, fmt.Errorf("%w %q (valid: %s)", errUnknownColumn, name, strings.Join(names, ", "))
+}
@@ -2,6 +2,7 @@
 package log
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -11,6 +12,11 @@ import (
 
 // Setup configures and returns a slog.Logger based on configuration.
 func Setup(cfg config.Log) *slog.Logger {
+	return SetupTo(os.Stdout, cfg)
+}
+
+// SetupTo is Setup writing to w, for a subcommand whose output owns stdout.
+func SetupTo(w io.Writer, cfg config.Log) *slog.Logger {
 	var handler slog.Handler
 
 	opts := &slog.HandlerOptions{
@@ -19,11 +25,11 @@ func Setup(cfg config.Log) *slog.Logger {
 
 	switch strings.ToLower(cfg.Format) {
 	case "json":
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(w, opts)
 	case "text":
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(w, opts)
 	default:
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(w, opts)
 	}
 
 	return slog.New(handler)
@@ -1,8 +1,10 @@
 package log_test
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
@@ -282,3 +284,17 @@ func TestSetupCaseSensitivity(t *testing.T) {
 		})
 	}
 }
+
+// TestSetupTo verifies that the logger writes to the given writer, so a subcommand's
+// stdout carries its output alone.
+func TestSetupTo(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := log.SetupTo(&buf, config.Log{Level: "info", Format: "text"})
+	logger.Info("refreshing WNC data")
+
+	if !strings.Contains(buf.String(), "refreshing WNC data") {
+		t.Errorf("SetupTo() wrote %q, want the message", buf.String())
+	}
+}
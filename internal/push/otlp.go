@@ -0,0 +1,206 @@
+// Package push provides the push mode, which sends the exporter's metrics to a
+// receiver after each refresh instead of waiting for a scrape.
+// This file holds the OTLP exporter and the conversion from Prometheus families.
+package push
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/tracing"
+)
+
+// scopeName is the instrumentation scope every pushed metric is reported under.
+const scopeName = "github.com/umatare5/cisco-wnc-exporter"
+
+// metricsPath is the OTLP/HTTP path metrics are posted to.
+const metricsPath = "/v1/metrics"
+
+// OTLPExporter pushes metric families to an OTLP/HTTP receiver.
+type OTLPExporter struct {
+	exporter *otlpmetrichttp.Exporter
+	resource *resource.Resource
+	scope    instrumentation.Scope
+}
+
+// NewOTLPExporter creates an exporter for the configured endpoint. An endpoint
+// without a path posts to /v1/metrics. It connects lazily, so a receiver that is down
+// fails the pushes rather than startup.
+func NewOTLPExporter(ctx context.Context, cfg config.OTLP, controller, version string) (*OTLPExporter, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = metricsPath
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPExporter{
+		exporter: exporter,
+		resource: tracing.Resource(controller, version),
+		scope:    instrumentation.Scope{Name: scopeName, Version: version},
+	}, nil
+}
+
+// Export implements Exporter.
+func (e *OTLPExporter) Export(ctx context.Context, families []*dto.MetricFamily, at time.Time) error {
+	return e.exporter.Export(ctx, &metricdata.ResourceMetrics{
+		Resource: e.resource,
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope:   e.scope,
+			Metrics: otlpMetrics(families, at),
+		}},
+	})
+}
+
+// Shutdown implements Exporter.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	return e.exporter.Shutdown(ctx)
+}
+
+// otlpMetrics converts gathered families into OTLP metrics, every point stamped at.
+//
+// A counter becomes a cumulative monotonic sum and a gauge or an untyped metric a
+// gauge, each under its Prometheus name, so a receiver that exports to Prometheus
+// again reproduces the scraped series. A counter's start time is left unset: most of
+// them count on the controller, which does not say when it started counting.
+func otlpMetrics(families []*dto.MetricFamily, at time.Time) []metricdata.Metrics {
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		data := otlpData(family, at)
+		if data == nil {
+			continue
+		}
+		metrics = append(metrics, metricdata.Metrics{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+			Data:        data,
+		})
+	}
+	return metrics
+}
+
+// otlpData converts the samples of one family. It returns nil for a type OTLP has no
+// counterpart for, which this exporter does not produce.
+func otlpData(family *dto.MetricFamily, at time.Time) metricdata.Aggregation {
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		return metricdata.Sum[float64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  dataPoints(family, at, func(m *dto.Metric) float64 { return m.GetCounter().GetValue() }),
+		}
+	case dto.MetricType_GAUGE:
+		return metricdata.Gauge[float64]{
+			DataPoints: dataPoints(family, at, func(m *dto.Metric) float64 { return m.GetGauge().GetValue() }),
+		}
+	case dto.MetricType_UNTYPED:
+		return metricdata.Gauge[float64]{
+			DataPoints: dataPoints(family, at, func(m *dto.Metric) float64 { return m.GetUntyped().GetValue() }),
+		}
+	case dto.MetricType_HISTOGRAM:
+		points := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+		for _, m := range family.GetMetric() {
+			points = append(points, histogramPoint(m, at))
+		}
+		return metricdata.Histogram[float64]{
+			Temporality: metricdata.CumulativeTemporality,
+			DataPoints:  points,
+		}
+	case dto.MetricType_SUMMARY:
+		points := make([]metricdata.SummaryDataPoint, 0, len(family.GetMetric()))
+		for _, m := range family.GetMetric() {
+			points = append(points, summaryPoint(m, at))
+		}
+		return metricdata.Summary{DataPoints: points}
+	default:
+		return nil
+	}
+}
+
+// dataPoints converts the samples of a counter, gauge or untyped family.
+func dataPoints(
+	family *dto.MetricFamily, at time.Time, value func(*dto.Metric) float64,
+) []metricdata.DataPoint[float64] {
+	points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		points = append(points, metricdata.DataPoint[float64]{
+			Attributes: attributes(m),
+			Time:       at,
+			Value:      value(m),
+		})
+	}
+	return points
+}
+
+// histogramPoint converts one Prometheus histogram. Prometheus buckets are cumulative
+// and OTLP buckets are not, and OTLP carries the overflow bucket without a bound,
+// where Prometheus names it +Inf.
+func histogramPoint(m *dto.Metric, at time.Time) metricdata.HistogramDataPoint[float64] {
+	h := m.GetHistogram()
+
+	var (
+		bounds   []float64
+		counts   []uint64
+		previous uint64
+	)
+	for _, bucket := range h.GetBucket() {
+		if bound := bucket.GetUpperBound(); !math.IsInf(bound, 1) {
+			bounds = append(bounds, bound)
+			counts = append(counts, bucket.GetCumulativeCount()-previous)
+			previous = bucket.GetCumulativeCount()
+		}
+	}
+	counts = append(counts, h.GetSampleCount()-previous)
+
+	return metricdata.HistogramDataPoint[float64]{
+		Attributes:   attributes(m),
+		Time:         at,
+		Count:        h.GetSampleCount(),
+		Sum:          h.GetSampleSum(),
+		Bounds:       bounds,
+		BucketCounts: counts,
+	}
+}
+
+// summaryPoint converts one Prometheus summary.
+func summaryPoint(m *dto.Metric, at time.Time) metricdata.SummaryDataPoint {
+	s := m.GetSummary()
+
+	quantiles := make([]metricdata.QuantileValue, 0, len(s.GetQuantile()))
+	for _, q := range s.GetQuantile() {
+		quantiles = append(quantiles, metricdata.QuantileValue{Quantile: q.GetQuantile(), Value: q.GetValue()})
+	}
+
+	return metricdata.SummaryDataPoint{
+		Attributes:     attributes(m),
+		Time:           at,
+		Count:          s.GetSampleCount(),
+		Sum:            s.GetSampleSum(),
+		QuantileValues: quantiles,
+	}
+}
+
+// attributes converts the labels of a sample.
+func attributes(m *dto.Metric) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(m.GetLabel()))
+	for _, label := range m.GetLabel() {
+		kvs = append(kvs, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(kvs...)
+}
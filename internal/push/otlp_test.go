@@ -0,0 +1,144 @@
+package push
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// TestOTLPMetrics_ConvertsEachType pins the conversion a receiver re-exporting to
+// Prometheus depends on: a counter stays monotonic and cumulative, labels become
+// attributes, and cumulative Prometheus buckets become per-bucket OTLP counts.
+func TestOTLPMetrics_ConvertsEachType(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	retries := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "wnc_refresh_retries_total", Help: "Retries."},
+		[]string{"data"})
+	retries.WithLabelValues("ap_cap_data").Add(2)
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "wnc_request_duration_seconds", Help: "Duration.", Buckets: []float64{0.1, 1},
+	})
+	for _, v := range []float64{0.05, 0.5, 0.7, 3} {
+		duration.Observe(v)
+	}
+	registry.MustRegister(retries, duration)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	metrics := otlpMetrics(families, at)
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics, want 2", len(metrics))
+	}
+
+	sum, ok := metrics[0].Data.(metricdata.Sum[float64])
+	if !ok {
+		t.Fatalf("%s data = %T, want a sum", metrics[0].Name, metrics[0].Data)
+	}
+	if !sum.IsMonotonic || sum.Temporality != metricdata.CumulativeTemporality {
+		t.Errorf("%s = monotonic %v, %v, want a cumulative monotonic sum", metrics[0].Name,
+			sum.IsMonotonic, sum.Temporality)
+	}
+	point := sum.DataPoints[0]
+	if data, _ := point.Attributes.Value("data"); data.AsString() != "ap_cap_data" {
+		t.Errorf("data attribute = %q, want ap_cap_data", data.AsString())
+	}
+	if point.Value != 2 || !point.Time.Equal(at) {
+		t.Errorf("point = %v at %v, want 2 at %v", point.Value, point.Time, at)
+	}
+
+	histogram, ok := metrics[1].Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("%s data = %T, want a histogram", metrics[1].Name, metrics[1].Data)
+	}
+	h := histogram.DataPoints[0]
+	if h.Count != 4 || h.Sum != 4.25 {
+		t.Errorf("count, sum = %d, %v, want 4, 4.25", h.Count, h.Sum)
+	}
+	wantCounts := []uint64{1, 2, 1}
+	if len(h.Bounds) != 2 || len(h.BucketCounts) != len(wantCounts) {
+		t.Fatalf("bounds, counts = %v, %v, want 2 bounds and 3 counts", h.Bounds, h.BucketCounts)
+	}
+	for i, want := range wantCounts {
+		if h.BucketCounts[i] != want {
+			t.Errorf("BucketCounts = %v, want %v", h.BucketCounts, wantCounts)
+			break
+		}
+	}
+}
+
+// TestOTLPExporter_PushesToEndpoint stands a local collector in for a real one and
+// checks what arrives: the resource names the controller and the exporter version,
+// and an endpoint given without a path is posted to /v1/metrics.
+func TestOTLPExporter_PushesToEndpoint(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan *colmetricpb.ExportMetricsServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metricsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		request := &colmetricpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, request); err == nil {
+			received <- request
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), config.OTLP{Endpoint: collector.URL},
+		"wnc1.example.internal", "v1.2.3")
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	defer func() { _ = exporter.Shutdown(context.Background()) }()
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "wnc_up", Help: "Up."})
+	up.Set(1)
+	registry.MustRegister(up)
+	families, _ := registry.Gather()
+
+	if err := exporter.Export(context.Background(), families, time.Now()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var request *colmetricpb.ExportMetricsServiceRequest
+	select {
+	case request = <-received:
+	default:
+		t.Fatal("collector received no metrics on /v1/metrics")
+	}
+
+	resource := request.GetResourceMetrics()[0]
+	attrs := make(map[string]string)
+	for _, kv := range resource.GetResource().GetAttributes() {
+		attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	if attrs["wnc.controller"] != "wnc1.example.internal" || attrs["service.version"] != "v1.2.3" {
+		t.Errorf("resource attributes = %v, want the controller and the exporter version", attrs)
+	}
+
+	metric := resource.GetScopeMetrics()[0].GetMetrics()[0]
+	if metric.GetName() != "wnc_up" || metric.GetGauge().GetDataPoints()[0].GetAsDouble() != 1 {
+		t.Errorf("metric = %v, want wnc_up 1", metric)
+	}
+}
@@ -0,0 +1,105 @@
+// Package push provides the push mode, which sends the exporter's metrics to a
+// receiver after each refresh instead of waiting for a scrape.
+package push
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+)
+
+// pokeInterval is how often the push loop asks for a refresh. A refresh starts only
+// once the cache TTL has passed since the last one completed, so this bounds how late
+// after that it starts, not how often the controller is read.
+const pokeInterval = time.Second
+
+// shutdownTimeout bounds the flush of an exporter when the push loop stops.
+const shutdownTimeout = 5 * time.Second
+
+// Exporter sends one gathered set of metric families to a receiver.
+type Exporter interface {
+	// Export sends the families, stamped with the time the refresh behind them
+	// started.
+	Export(ctx context.Context, families []*dto.MetricFamily, at time.Time) error
+	// Shutdown flushes and releases the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+// Start runs the push loop in the background and returns the function that stops it
+// and shuts the exporters down.
+func Start(
+	ctx context.Context, gatherer prometheus.Gatherer, watcher wnc.RefreshWatcher, exporters ...Exporter,
+) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		Run(ctx, gatherer, watcher, exporters...)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+		for _, exporter := range exporters {
+			if err := exporter.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Failed to shut down metrics push exporter", "error", err)
+			}
+		}
+	}
+}
+
+// Run drives refreshes with no scrape to start them and exports the gathered families
+// to every exporter after each one that succeeded, until ctx is done. One loop serves
+// them all, since the watcher announces each refresh once.
+//
+// A scrape still serves and still starts refreshes of its own, and every successful
+// refresh is pushed whichever started it. A failed refresh pushes nothing, so the
+// receiver sees the series go stale as a scraper would, rather than the last snapshot
+// repeated.
+func Run(ctx context.Context, gatherer prometheus.Gatherer, watcher wnc.RefreshWatcher, exporters ...Exporter) {
+	ticker := time.NewTicker(pokeInterval)
+	defer ticker.Stop()
+
+	watcher.Poke()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			watcher.Poke()
+		case at := <-watcher.Refreshed():
+			push(ctx, gatherer, exporters, at)
+		}
+	}
+}
+
+// push gathers the registry once and exports what it returned to every exporter. A
+// collector that failed is logged and left out, as a scrape would leave it out, rather
+// than failing the push.
+func push(ctx context.Context, gatherer prometheus.Gatherer, exporters []Exporter, at time.Time) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		slog.Warn("Gathering metrics for push partially failed", "error", err)
+	}
+	if len(families) == 0 {
+		return
+	}
+
+	for _, exporter := range exporters {
+		start := time.Now()
+		if err := exporter.Export(ctx, families, at); err != nil {
+			slog.Warn("Failed to push metrics", "error", err, "duration", time.Since(start))
+			continue
+		}
+		slog.Debug("Pushed metrics", "families", len(families), "duration", time.Since(start))
+	}
+}
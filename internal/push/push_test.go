@@ -0,0 +1,89 @@
+package push
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fakeWatcher announces refreshes on demand and counts the pokes it received.
+type fakeWatcher struct {
+	pokes     atomic.Int32
+	refreshed chan time.Time
+}
+
+func (w *fakeWatcher) Poke()                       { w.pokes.Add(1) }
+func (w *fakeWatcher) Refreshed() <-chan time.Time { return w.refreshed }
+
+// fakeExporter records every export.
+type fakeExporter struct {
+	mu       sync.Mutex
+	exported []time.Time
+	families [][]*dto.MetricFamily
+	shutdown bool
+	done     chan struct{}
+}
+
+func (e *fakeExporter) Export(_ context.Context, families []*dto.MetricFamily, at time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exported = append(e.exported, at)
+	e.families = append(e.families, families)
+	e.done <- struct{}{}
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+// TestStart_PushesAfterEachRefresh drives the loop the way the data source does: it
+// must ask for a refresh with no scrape to do so, push the registry once per
+// announced refresh stamped with its time, and shut the exporter down on stop.
+func TestStart_PushesAfterEachRefresh(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "wnc_up", Help: "Whether the refresh reached the controller."})
+	up.Set(1)
+	registry.MustRegister(up)
+
+	watcher := &fakeWatcher{refreshed: make(chan time.Time, 1)}
+	exporter := &fakeExporter{done: make(chan struct{}, 1)}
+
+	stop := Start(context.Background(), registry, watcher, exporter)
+
+	at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	watcher.refreshed <- at
+	select {
+	case <-exporter.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no export after an announced refresh")
+	}
+
+	stop()
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	if len(exporter.exported) != 1 || !exporter.exported[0].Equal(at) {
+		t.Errorf("exported at %v, want exactly one export at %v", exporter.exported, at)
+	}
+	if got := exporter.families[0]; len(got) != 1 || got[0].GetName() != "wnc_up" {
+		t.Errorf("exported families = %v, want the registry's wnc_up", got)
+	}
+	if !exporter.shutdown {
+		t.Error("exporter not shut down on stop")
+	}
+	if watcher.pokes.Load() == 0 {
+		t.Error("watcher never poked, want a refresh asked for with no scrape")
+	}
+}
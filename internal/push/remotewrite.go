@@ -0,0 +1,220 @@
+// Package push provides the push mode, which sends the exporter's metrics to a
+// receiver after each refresh instead of waiting for a scrape.
+// This file holds the Prometheus remote-write exporter and its send queue.
+package push
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+const (
+	// remoteWriteTimeout bounds one request to the remote-write URL.
+	remoteWriteTimeout = 30 * time.Second
+	// remoteWriteMinBackoff is the wait before the first retry of a request, doubled
+	// before each next one up to remoteWriteMaxBackoff.
+	remoteWriteMinBackoff = time.Second
+	remoteWriteMaxBackoff = 30 * time.Second
+	// remoteWriteMaxAge is how old the samples of a request may grow, from the refresh
+	// behind them, before a recoverable failure drops it instead of retrying. A request
+	// retried longer holds every newer one behind it while the full queue drops those,
+	// and a receiver rejects samples much older than its head anyway.
+	remoteWriteMaxAge = 10 * time.Minute
+	// remoteWriteErrorBodyLimit bounds how much of a rejection is quoted in the log.
+	remoteWriteErrorBodyLimit = 256
+)
+
+// remoteWrite is one encoded request and the refresh time its samples carry.
+type remoteWrite struct {
+	body []byte
+	at   time.Time
+}
+
+// errRecoverable marks a failed request worth retrying: the receiver could not be
+// reached, was overloaded, or failed on its side. A request it rejected is not
+// retried, since it would be rejected again.
+var errRecoverable = errors.New("recoverable remote-write failure")
+
+// RemoteWriteExporter pushes metric families to a Prometheus remote-write receiver.
+//
+// Export encodes and queues a request and returns at once; a sender goroutine sends
+// the queue in order and retries a recoverable failure with backoff, so a receiver
+// behind a flaky uplink delays pushes rather than losing them. A request is retried
+// until its samples are remoteWriteMaxAge old, then dropped for the ones behind it.
+// The queue is bounded: once full, the oldest request is dropped for the newest, since
+// a backlog of stale snapshots is worth less than the current one.
+type RemoteWriteExporter struct {
+	client    *http.Client
+	url       string
+	username  string
+	password  string
+	userAgent string
+	external  map[string]string
+	// backoff is the wait before the first retry of a request, and maxAge the age past
+	// which a request is no longer retried.
+	backoff time.Duration
+	maxAge  time.Duration
+
+	queue    chan remoteWrite
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// NewRemoteWriteExporter creates an exporter for the configured URL and starts its
+// sender.
+func NewRemoteWriteExporter(cfg config.RemoteWrite, version string) *RemoteWriteExporter {
+	e := &RemoteWriteExporter{
+		client:    &http.Client{Timeout: remoteWriteTimeout},
+		url:       cfg.URL,
+		username:  cfg.Username,
+		password:  cfg.Password,
+		userAgent: "cisco-wnc-exporter/" + version,
+		external:  cfg.ExternalLabels,
+		backoff:   remoteWriteMinBackoff,
+		maxAge:    remoteWriteMaxAge,
+		queue:     make(chan remoteWrite, cfg.QueueSize),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go e.run()
+	return e
+}
+
+// Export implements Exporter. It only queues the request; a failed send is logged by
+// the sender.
+func (e *RemoteWriteExporter) Export(_ context.Context, families []*dto.MetricFamily, at time.Time) error {
+	write := remoteWrite{body: snappy.Encode(nil, encodeWriteRequest(families, e.external, at)), at: at}
+
+	// The push loop is the only producer, so once one request has been dropped
+	// the queue has room for this one.
+	select {
+	case e.queue <- write:
+		return nil
+	default:
+	}
+	select {
+	case <-e.queue:
+		slog.Warn("Remote-write queue full, dropped the oldest push", "queue_size", cap(e.queue))
+	default:
+	}
+	select {
+	case e.queue <- write:
+	default:
+	}
+	return nil
+}
+
+// Shutdown implements Exporter. It stops the sender, then tries each request still
+// queued once, so the last refresh is not lost to a restart, within ctx.
+func (e *RemoteWriteExporter) Shutdown(ctx context.Context) error {
+	close(e.stopping)
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for {
+		select {
+		case write := <-e.queue:
+			if err := e.send(ctx, write.body); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// run sends the queue in order until Shutdown.
+func (e *RemoteWriteExporter) run() {
+	defer close(e.done)
+
+	for {
+		select {
+		case <-e.stopping:
+			return
+		case write := <-e.queue:
+			if !e.sendWithRetry(write) {
+				return
+			}
+		}
+	}
+}
+
+// sendWithRetry sends one request, retrying a recoverable failure until it succeeds or
+// its samples would be past maxAge at the next try. It reports false when Shutdown
+// interrupted a retry, dropping the request.
+func (e *RemoteWriteExporter) sendWithRetry(write remoteWrite) bool {
+	backoff := e.backoff
+	for {
+		err := e.send(context.Background(), write.body)
+		switch {
+		case err == nil:
+			return true
+		case !errors.Is(err, errRecoverable):
+			slog.Warn("Remote-write receiver rejected a push, dropping it", "error", err)
+			return true
+		case time.Since(write.at)+backoff > e.maxAge:
+			slog.Warn("Remote-write push failed past its retry age, dropping it",
+				"error", err, "refreshed_at", write.at, "max_age", e.maxAge)
+			return true
+		}
+
+		slog.Warn("Remote-write push failed, retrying", "error", err, "backoff", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-e.stopping:
+			timer.Stop()
+			return false
+		}
+		backoff = min(2*backoff, remoteWriteMaxBackoff)
+	}
+}
+
+// send makes one remote-write request.
+func (e *RemoteWriteExporter) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", e.userAgent)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errRecoverable, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	quoted, _ := io.ReadAll(io.LimitReader(resp.Body, remoteWriteErrorBodyLimit))
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(quoted))
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", errRecoverable, err)
+	}
+	return err
+}
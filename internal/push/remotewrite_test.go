@@ -0,0 +1,331 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// decodedSeries is one series of a decoded remote-write request.
+type decodedSeries struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// String spells the series the way a query names it, for comparisons.
+func (s decodedSeries) String() string {
+	parts := make([]string, 0, len(s.labels))
+	for _, l := range s.labels {
+		parts = append(parts, l.name+"="+l.value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// TestEncodeWriteRequest_SeriesAsScraped pins the series a receiver stores: what a
+// scrape of the same registry would have stored, histogram buckets and the implicit
+// +Inf bucket included, each stamped with the refresh time, and an external label
+// never overriding a label the sample carries.
+func TestEncodeWriteRequest_SeriesAsScraped(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "wnc_up", Help: "Up."}, []string{"site"})
+	up.WithLabelValues("osaka").Set(1)
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "wnc_request_duration_seconds", Help: "Duration.", Buckets: []float64{0.5},
+	})
+	duration.Observe(0.2)
+	duration.Observe(2)
+	registry.MustRegister(up, duration)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	series := decodeWriteRequest(t, encodeWriteRequest(families,
+		map[string]string{"site": "tokyo", "env": "prod"}, at))
+
+	want := map[string]float64{
+		"__name__=wnc_request_duration_seconds_bucket,env=prod,le=0.5,site=tokyo":  1,
+		"__name__=wnc_request_duration_seconds_bucket,env=prod,le=+Inf,site=tokyo": 2,
+		"__name__=wnc_request_duration_seconds_sum,env=prod,site=tokyo":            2.2,
+		"__name__=wnc_request_duration_seconds_count,env=prod,site=tokyo":          2,
+		"__name__=wnc_up,env=prod,site=osaka":                                      1,
+	}
+	if len(series) != len(want) {
+		t.Errorf("got %d series, want %d: %v", len(series), len(want), series)
+	}
+	for _, s := range series {
+		value, ok := want[s.String()]
+		if !ok {
+			t.Errorf("unexpected series %s", s)
+			continue
+		}
+		if s.value != value {
+			t.Errorf("%s = %v, want %v", s, s.value, value)
+		}
+		if s.timestamp != at.UnixMilli() {
+			t.Errorf("%s timestamp = %d, want the refresh time %d", s, s.timestamp, at.UnixMilli())
+		}
+		if !slices.IsSortedFunc(s.labels, func(a, b label) int { return strings.Compare(a.name, b.name) }) {
+			t.Errorf("%s labels are not sorted by name", s)
+		}
+	}
+}
+
+// TestRemoteWriteExporter_RetriesRecoverableFailure drives the sender against a local
+// receiver that is overloaded once: the push must arrive on the retry, authenticated
+// and in the protocol's encoding.
+func TestRemoteWriteExporter_RetriesRecoverableFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	arrived := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "edge" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "snappy" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+		arrived <- body
+	}))
+	defer receiver.Close()
+
+	exporter := NewRemoteWriteExporter(config.RemoteWrite{
+		URL: receiver.URL, Username: "edge", Password: "secret", QueueSize: 1,
+	}, "test")
+	exporter.backoff = time.Millisecond
+
+	if err := exporter.Export(context.Background(), upFamilies(t), time.Now()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	select {
+	case compressed := <-arrived:
+		raw, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			t.Fatalf("snappy.Decode() error = %v", err)
+		}
+		series := decodeWriteRequest(t, raw)
+		if len(series) != 1 || series[0].String() != "__name__=wnc_up" || series[0].value != 1 {
+			t.Errorf("series = %v, want wnc_up 1", series)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("receiver got no push after the recoverable failure")
+	}
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 2 {
+		t.Errorf("receiver got %d requests, want 2: one refused and its retry", requests)
+	}
+}
+
+// TestRemoteWriteExporter_DropsPushPastMaxAge gives up on a push the receiver keeps
+// refusing once its samples are past the retry age, so the push behind it is sent
+// rather than held until shutdown.
+func TestRemoteWriteExporter_DropsPushPastMaxAge(t *testing.T) {
+	t.Parallel()
+
+	stale := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	fresh := time.Now().Truncate(time.Millisecond)
+
+	arrived := make(chan int64, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		compressed, _ := io.ReadAll(r.Body)
+		raw, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		at := decodeWriteRequest(t, raw)[0].timestamp
+		if at == stale.UnixMilli() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		arrived <- at
+	}))
+	defer receiver.Close()
+
+	exporter := NewRemoteWriteExporter(config.RemoteWrite{URL: receiver.URL, QueueSize: 2}, "test")
+	exporter.backoff = time.Millisecond
+	exporter.maxAge = time.Minute
+	defer func() { _ = exporter.Shutdown(context.Background()) }()
+
+	for _, at := range []time.Time{stale, fresh} {
+		if err := exporter.Export(context.Background(), upFamilies(t), at); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+
+	select {
+	case at := <-arrived:
+		if at != fresh.UnixMilli() {
+			t.Errorf("receiver got the push at %d, want the fresh one at %d", at, fresh.UnixMilli())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("receiver got no push: the stale one is still being retried")
+	}
+}
+
+// TestRemoteWriteExporter_SendRejected leaves a request the receiver rejected out of
+// the retries: sent again, it would only be rejected again.
+func TestRemoteWriteExporter_SendRejected(t *testing.T) {
+	t.Parallel()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	exporter := &RemoteWriteExporter{client: receiver.Client(), url: receiver.URL}
+	err := exporter.send(context.Background(), nil)
+	if err == nil || errors.Is(err, errRecoverable) {
+		t.Errorf("send() error = %v, want a rejection that is not retried", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "out of order sample") {
+		t.Errorf("send() error = %v, want the receiver's reason quoted", err)
+	}
+}
+
+// TestRemoteWriteExporter_DropsOldestWhenFull keeps the queue bounded and current: a
+// receiver that stays away costs the oldest pushes, not the newest.
+func TestRemoteWriteExporter_DropsOldestWhenFull(t *testing.T) {
+	t.Parallel()
+
+	// No sender runs, so the queue only fills.
+	exporter := &RemoteWriteExporter{queue: make(chan remoteWrite, 2)}
+	families := upFamilies(t)
+
+	var times []time.Time
+	for i := range 3 {
+		at := time.Date(2026, 10, 18, 9, i, 0, 0, time.UTC)
+		times = append(times, at)
+		if err := exporter.Export(context.Background(), families, at); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+
+	if got := len(exporter.queue); got != 2 {
+		t.Fatalf("queue holds %d pushes, want 2", got)
+	}
+	for _, want := range times[1:] {
+		raw, err := snappy.Decode(nil, (<-exporter.queue).body)
+		if err != nil {
+			t.Fatalf("snappy.Decode() error = %v", err)
+		}
+		if got := decodeWriteRequest(t, raw)[0].timestamp; got != want.UnixMilli() {
+			t.Errorf("queued push at %d, want %d: the oldest should have been dropped", got, want.UnixMilli())
+		}
+	}
+}
+
+func upFamilies(t *testing.T) []*dto.MetricFamily {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "wnc_up", Help: "Up."})
+	up.Set(1)
+	registry.MustRegister(up)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	return families
+}
+
+// decodeWriteRequest decodes an uncompressed remote-write request.
+func decodeWriteRequest(t *testing.T, buf []byte) []decodedSeries {
+	t.Helper()
+
+	var series []decodedSeries
+	for _, ts := range decodeFields(t, buf)[fieldWriteRequestTimeseries] {
+		var s decodedSeries
+		fields := decodeFields(t, ts)
+		for _, encoded := range fields[fieldTimeSeriesLabels] {
+			l := decodeFields(t, encoded)
+			s.labels = append(s.labels, label{
+				name:  string(l[fieldLabelName][0]),
+				value: string(l[fieldLabelValue][0]),
+			})
+		}
+		sample := fields[fieldTimeSeriesSamples][0]
+		for len(sample) > 0 {
+			num, typ, n := protowire.ConsumeTag(sample)
+			sample = sample[n:]
+			switch {
+			case num == fieldSampleValue && typ == protowire.Fixed64Type:
+				v, m := protowire.ConsumeFixed64(sample)
+				s.value, sample = math.Float64frombits(v), sample[m:]
+			case num == fieldSampleTimestamp && typ == protowire.VarintType:
+				v, m := protowire.ConsumeVarint(sample)
+				s.timestamp, sample = int64(v), sample[m:] //nolint:gosec // Wire format of an int64.
+			default:
+				t.Fatalf("unexpected sample field %d", num)
+			}
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+// decodeFields splits a message into its length-delimited fields by number.
+func decodeFields(t *testing.T, buf []byte) map[protowire.Number][][]byte {
+	t.Helper()
+
+	fields := make(map[protowire.Number][][]byte)
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 || typ != protowire.BytesType {
+			t.Fatalf("unexpected field %d of type %d", num, typ)
+		}
+		buf = buf[n:]
+		value, m := protowire.ConsumeBytes(buf)
+		if m < 0 {
+			t.Fatalf("truncated field %d", num)
+		}
+		fields[num] = append(fields[num], value)
+		buf = buf[m:]
+	}
+	return fields
+}
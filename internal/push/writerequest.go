@@ -0,0 +1,169 @@
+// Package push provides the push mode, which sends the exporter's metrics to a
+// receiver after each refresh instead of waiting for a scrape.
+// This file holds the encoding of gathered families as a remote-write request.
+package push
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the remote-write 1.0 protobuf messages. The messages are four
+// flat ones, so they are encoded here rather than pulling in the Prometheus server
+// module for its generated types.
+const (
+	fieldWriteRequestTimeseries = 1
+	fieldTimeSeriesLabels       = 1
+	fieldTimeSeriesSamples      = 2
+	fieldLabelName              = 1
+	fieldLabelValue             = 2
+	fieldSampleValue            = 1
+	fieldSampleTimestamp        = 2
+)
+
+// label is one label of an encoded series.
+type label struct {
+	name, value string
+}
+
+// seriesSample is one sample of a gathered metric in the form a scrape stores it. A
+// histogram or a summary is several, told apart by name suffix and by le or quantile.
+type seriesSample struct {
+	name  string
+	extra []label
+	value float64
+}
+
+// encodeWriteRequest encodes the families as an uncompressed remote-write request,
+// every sample stamped at.
+//
+// Each series carries what a scrape of this exporter would have stored: the metric
+// name, the sample's labels with empty ones left out, and the external labels a
+// sample does not already carry, sorted by name as the protocol requires.
+func encodeWriteRequest(families []*dto.MetricFamily, external map[string]string, at time.Time) []byte {
+	timestamp := at.UnixMilli()
+
+	var buf []byte
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			for _, s := range samples(family, m) {
+				series := encodeTimeSeries(seriesLabels(s, m, external), s.value, timestamp)
+				buf = protowire.AppendTag(buf, fieldWriteRequestTimeseries, protowire.BytesType)
+				buf = protowire.AppendBytes(buf, series)
+			}
+		}
+	}
+	return buf
+}
+
+// samples expands one gathered metric into the samples a scrape would store.
+func samples(family *dto.MetricFamily, m *dto.Metric) []seriesSample {
+	name := family.GetName()
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		return []seriesSample{{name: name, value: m.GetCounter().GetValue()}}
+	case dto.MetricType_GAUGE:
+		return []seriesSample{{name: name, value: m.GetGauge().GetValue()}}
+	case dto.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		out := make([]seriesSample, 0, len(h.GetBucket())+3)
+		sawInf := false
+		for _, bucket := range h.GetBucket() {
+			sawInf = sawInf || math.IsInf(bucket.GetUpperBound(), 1)
+			out = append(out, seriesSample{
+				name:  name + "_bucket",
+				extra: []label{{"le", formatFloat(bucket.GetUpperBound())}},
+				value: float64(bucket.GetCumulativeCount()),
+			})
+		}
+		// The client library leaves the +Inf bucket implicit, as the exposition
+		// formats write it out.
+		if !sawInf {
+			out = append(out, seriesSample{
+				name:  name + "_bucket",
+				extra: []label{{"le", formatFloat(math.Inf(1))}},
+				value: float64(h.GetSampleCount()),
+			})
+		}
+		return append(out,
+			seriesSample{name: name + "_sum", value: h.GetSampleSum()},
+			seriesSample{name: name + "_count", value: float64(h.GetSampleCount())})
+	case dto.MetricType_SUMMARY:
+		s := m.GetSummary()
+		out := make([]seriesSample, 0, len(s.GetQuantile())+2)
+		for _, q := range s.GetQuantile() {
+			out = append(out, seriesSample{
+				name:  name,
+				extra: []label{{"quantile", formatFloat(q.GetQuantile())}},
+				value: q.GetValue(),
+			})
+		}
+		return append(out,
+			seriesSample{name: name + "_sum", value: s.GetSampleSum()},
+			seriesSample{name: name + "_count", value: float64(s.GetSampleCount())})
+	default:
+		return []seriesSample{{name: name, value: m.GetUntyped().GetValue()}}
+	}
+}
+
+// seriesLabels returns the sorted labels of one sample's series.
+func seriesLabels(s seriesSample, m *dto.Metric, external map[string]string) []label {
+	labels := make([]label, 0, len(m.GetLabel())+len(s.extra)+len(external)+1)
+	labels = append(labels, label{"__name__", s.name})
+	labels = append(labels, s.extra...)
+
+	own := make(map[string]bool, len(m.GetLabel()))
+	for _, pair := range m.GetLabel() {
+		own[pair.GetName()] = true
+		if pair.GetValue() != "" {
+			labels = append(labels, label{pair.GetName(), pair.GetValue()})
+		}
+	}
+	for name, value := range external {
+		if !own[name] && value != "" {
+			labels = append(labels, label{name, value})
+		}
+	}
+
+	slices.SortFunc(labels, func(a, b label) int { return cmp.Compare(a.name, b.name) })
+	return labels
+}
+
+// encodeTimeSeries encodes one series with a single sample.
+func encodeTimeSeries(labels []label, value float64, timestamp int64) []byte {
+	var buf []byte
+	for _, l := range labels {
+		var encoded []byte
+		encoded = protowire.AppendTag(encoded, fieldLabelName, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, l.name)
+		encoded = protowire.AppendTag(encoded, fieldLabelValue, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, l.value)
+
+		buf = protowire.AppendTag(buf, fieldTimeSeriesLabels, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, encoded)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, fieldSampleValue, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, fieldSampleTimestamp, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(timestamp)) //nolint:gosec // Wire format of an int64.
+
+	buf = protowire.AppendTag(buf, fieldTimeSeriesSamples, protowire.BytesType)
+	return protowire.AppendBytes(buf, sample)
+}
+
+// formatFloat spells an le or quantile value the way the exposition formats do.
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -3,10 +3,12 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os/signal"
 	"strconv"
 	"syscall"
@@ -16,6 +18,8 @@ import (
 
 	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+	"github.com/umatare5/cisco-wnc-exporter/internal/push"
+	"github.com/umatare5/cisco-wnc-exporter/internal/wnc"
 )
 
 // LifecycleManager manages HTTP server startup and graceful shutdown.
@@ -25,9 +29,11 @@ type LifecycleManager struct {
 }
 
 // NewLifecycleManager creates a new server lifecycle manager.
-func NewLifecycleManager(registry *prometheus.Registry, cfg *config.Config) *LifecycleManager {
+func NewLifecycleManager(
+	registry *prometheus.Registry, catalog []collector.CatalogEntry, cfg *config.Config,
+) *LifecycleManager {
 	addr := net.JoinHostPort(cfg.Web.ListenAddress, strconv.Itoa(cfg.Web.ListenPort))
-	server := New(registry, addr, cfg.Web.TelemetryPath)
+	server := New(registry, addr, cfg.Web.TelemetryPath, catalog)
 
 	return &LifecycleManager{
 		server: server,
@@ -48,11 +54,54 @@ func StartAndServe(ctx context.Context, cfg *config.Config, version string) erro
 	collectorMgr := collector.NewCollector(cfg)
 	collectorMgr.Setup(version)
 
+	if cfg.OTLP.Endpoint != "" || cfg.RemoteWrite.URL != "" {
+		stop, err := startPush(ctx, cfg, collectorMgr, version)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	catalog, err := collector.Catalog(cfg)
+	if err != nil {
+		return fmt.Errorf("building metric catalog failed: %w", err)
+	}
+
 	// Create and run server lifecycle manager
-	serverMgr := NewLifecycleManager(collectorMgr.Registry(), cfg)
+	serverMgr := NewLifecycleManager(collectorMgr.Registry(), catalog, cfg)
 	return serverMgr.Run(ctx)
 }
 
+// startPush starts pushing the registry to every configured receiver after each
+// refresh. The HTTP server keeps serving alongside it, so /healthz and a scrape still
+// work.
+func startPush(
+	ctx context.Context, cfg *config.Config, collectorMgr *collector.Collector, version string,
+) (func(), error) {
+	watcher, ok := collectorMgr.DataSource().(wnc.RefreshWatcher)
+	if !ok {
+		return nil, errors.New("WNC data source cannot drive a metrics push")
+	}
+
+	var exporters []push.Exporter
+	if cfg.OTLP.Endpoint != "" {
+		exporter, err := push.NewOTLPExporter(ctx, cfg.OTLP, cfg.WNC.Controller, version)
+		if err != nil {
+			return nil, fmt.Errorf("OTLP exporter setup failed: %w", err)
+		}
+		exporters = append(exporters, exporter)
+		slog.Info("Pushing metrics after each refresh", "otlp_endpoint", cfg.OTLP.Endpoint)
+	}
+	if cfg.RemoteWrite.URL != "" {
+		exporters = append(exporters, push.NewRemoteWriteExporter(cfg.RemoteWrite, version))
+		// The URL may carry credentials of its own, which have no place in a log.
+		target, _ := url.Parse(cfg.RemoteWrite.URL)
+		slog.Info("Pushing metrics after each refresh", "remote_write_url", target.Redacted())
+	}
+
+	return push.Start(ctx, collectorMgr.Registry(), watcher, exporters...), nil
+}
+
 // Run starts the HTTP server and handles graceful shutdown.
 // It blocks until the server is shut down or an error occurs.
 func (lm *LifecycleManager) Run(ctx context.Context) error {
@@ -2,20 +2,29 @@
 package server
 
 import (
+	"bytes"
 	"html"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
 )
 
-// New creates a new HTTP server with metrics and health endpoints. Config.Validate
-// rejects every telemetryPath that http.ServeMux would panic on, apart from the root,
-// which is handled below.
-func New(reg *prometheus.Registry, addr, telemetryPath string) *http.Server {
+// catalogContentTypes maps each catalog format to the content type it is served as.
+var catalogContentTypes = map[string]string{
+	collector.CatalogFormatJSON:     "application/json",
+	collector.CatalogFormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// New creates a new HTTP server with metrics, catalog and health endpoints.
+// Config.Validate rejects every telemetryPath that http.ServeMux would panic on, apart
+// from the root, which is handled below.
+func New(reg *prometheus.Registry, addr, telemetryPath string, catalog []collector.CatalogEntry) *http.Server {
 	mux := http.NewServeMux()
 
 	mux.Handle(telemetryPath, promhttp.HandlerFor(reg, promhttp.HandlerOpts{
@@ -23,6 +32,28 @@ func New(reg *prometheus.Registry, addr, telemetryPath string) *http.Server {
 		MaxRequestsInFlight: 10,
 	}))
 
+	// The catalog is built once at startup, as the collectors are, and served as JSON
+	// unless ?format= asks for Markdown.
+	mux.HandleFunc(config.CatalogPath, func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = collector.CatalogFormatJSON
+		}
+		if !slices.Contains(collector.CatalogFormats, format) {
+			http.Error(w, "unknown catalog format: "+format, http.StatusBadRequest)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := collector.WriteCatalog(&buf, catalog, format); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", catalogContentTypes[format])
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+	})
+
 	mux.HandleFunc(config.HealthPath, func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
@@ -37,6 +68,7 @@ func New(reg *prometheus.Registry, addr, telemetryPath string) *http.Server {
 <body>
 <h1>Cisco WNC Exporter</h1>
 <p><a href="` + html.EscapeString(telemetryPath) + `">Metrics</a></p>
+<p><a href="` + config.CatalogPath + `">Metric Catalog</a></p>
 <p><a href="` + config.HealthPath + `">Health Check</a></p>
 </body>
 </html>`)
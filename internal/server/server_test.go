@@ -9,6 +9,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/umatare5/cisco-wnc-exporter/internal/collector"
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
 	"github.com/umatare5/cisco-wnc-exporter/internal/server"
 )
@@ -28,7 +29,7 @@ func TestServer_ServesMetricsAtTheConfiguredPath(t *testing.T) {
 
 	const telemetryPath = "/wnc-metrics"
 
-	srv := server.New(probeRegistry(t), ":8080", telemetryPath)
+	srv := server.New(probeRegistry(t), ":8080", telemetryPath, nil)
 
 	w := httptest.NewRecorder()
 	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, telemetryPath, http.NoBody))
@@ -54,7 +55,7 @@ func TestServer_ServesMetricsAtTheConfiguredPath(t *testing.T) {
 func TestServer_ServesMetricsAtTheRoot(t *testing.T) {
 	t.Parallel()
 
-	srv := server.New(probeRegistry(t), ":8080", "/")
+	srv := server.New(probeRegistry(t), ":8080", "/", nil)
 
 	w := httptest.NewRecorder()
 	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
@@ -85,7 +86,7 @@ func TestNew(t *testing.T) {
 			t.Parallel()
 
 			reg := prometheus.NewRegistry()
-			srv := server.New(reg, tt.addr, config.DefaultTelemetryPath)
+			srv := server.New(reg, tt.addr, config.DefaultTelemetryPath, nil)
 
 			if srv == nil {
 				t.Fatal("New() returned nil server")
@@ -111,7 +112,7 @@ func TestServer_MetricsEndpoint(t *testing.T) {
 	t.Parallel()
 
 	reg := prometheus.NewRegistry()
-	srv := server.New(reg, ":8080", config.DefaultTelemetryPath)
+	srv := server.New(reg, ":8080", config.DefaultTelemetryPath, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
 	w := httptest.NewRecorder()
@@ -129,11 +130,49 @@ func TestServer_MetricsEndpoint(t *testing.T) {
 	}
 }
 
+// TestServer_CatalogEndpoint serves the catalog next to the default telemetry path,
+// which must not shadow it, as JSON unless Markdown is asked for.
+func TestServer_CatalogEndpoint(t *testing.T) {
+	t.Parallel()
+
+	catalog := []collector.CatalogEntry{{
+		Name: "wnc_ap_joined", Type: "gauge", Help: "AP joined", Labels: []string{"mac"},
+		Collector: "ap", Modules: []string{"join", "departed"}, DataTypes: []string{"ap_join_stats"},
+	}}
+	srv := server.New(probeRegistry(t), ":8080", config.DefaultTelemetryPath, catalog)
+
+	tests := []struct {
+		query       string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"", http.StatusOK, "application/json", `"modules": [`},
+		{"?format=markdown", http.StatusOK, "text/markdown; charset=utf-8", "| join, departed | `wnc_ap_joined` |"},
+		{"?format=yaml", http.StatusBadRequest, "", "unknown catalog format"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, config.CatalogPath+tt.query, http.NoBody))
+
+		if w.Code != tt.code {
+			t.Errorf("%s%s status = %d, want %d", config.CatalogPath, tt.query, w.Code, tt.code)
+		}
+		if tt.contentType != "" && w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s%s Content-Type = %q, want %q", config.CatalogPath, tt.query,
+				w.Header().Get("Content-Type"), tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s%s body = %q, want it to contain %q", config.CatalogPath, tt.query, w.Body.String(), tt.body)
+		}
+	}
+}
+
 func TestServer_HealthzEndpoint(t *testing.T) {
 	t.Parallel()
 
 	reg := prometheus.NewRegistry()
-	srv := server.New(reg, ":8080", config.DefaultTelemetryPath)
+	srv := server.New(reg, ":8080", config.DefaultTelemetryPath, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
 	w := httptest.NewRecorder()
@@ -159,7 +198,7 @@ func TestServer_RootEndpoint(t *testing.T) {
 	t.Parallel()
 
 	reg := prometheus.NewRegistry()
-	srv := server.New(reg, ":8080", config.DefaultTelemetryPath)
+	srv := server.New(reg, ":8080", config.DefaultTelemetryPath, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 	w := httptest.NewRecorder()
@@ -194,7 +233,7 @@ func TestServer_NotFoundEndpoint(t *testing.T) {
 	t.Parallel()
 
 	reg := prometheus.NewRegistry()
-	srv := server.New(reg, ":8080", config.DefaultTelemetryPath)
+	srv := server.New(reg, ":8080", config.DefaultTelemetryPath, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/nonexistent", http.NoBody)
 	w := httptest.NewRecorder()
@@ -218,7 +257,7 @@ func TestServer_HTTPMethods(t *testing.T) {
 	t.Parallel()
 
 	reg := prometheus.NewRegistry()
-	srv := server.New(reg, ":8080", config.DefaultTelemetryPath)
+	srv := server.New(reg, ":8080", config.DefaultTelemetryPath, nil)
 
 	methods := []string{http.MethodPost, http.MethodPut, http.MethodDelete}
 
@@ -0,0 +1,74 @@
+// Package tracing provides OpenTelemetry tracing setup.
+package tracing
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// serviceName identifies this exporter's spans at the collector.
+const serviceName = "cisco-wnc-exporter"
+
+// tracesPath is the OTLP/HTTP path spans are posted to.
+const tracesPath = "/v1/traces"
+
+// controllerAttribute carries the controller a process scrapes, so the traces of
+// several exporters in one backend can be told apart without a span attribute each.
+const controllerAttribute = attribute.Key("wnc.controller")
+
+// Setup installs the global tracer provider the configuration asks for and returns
+// the function that flushes and stops it.
+//
+// With no endpoint configured the global provider is left as the no-op one, so every
+// span the exporter starts costs nothing and the returned function does nothing. The
+// exporter connects lazily, so an endpoint that is down does not fail startup; its
+// spans are dropped and the SDK logs the failed export.
+func Setup(
+	ctx context.Context, cfg config.Tracing, controller, version string,
+) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	// The exporter posts to the URL's path as given, so a bare collector address
+	// would post to its root. It gets the path the OTLP/HTTP spec defines instead.
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = tracesPath
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(Resource(controller, version)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Resource describes this exporter process to an OpenTelemetry backend. Traces and
+// pushed metrics carry the same one, so a backend can correlate the two.
+func Resource(controller, version string) *resource.Resource {
+	return resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+		controllerAttribute.String(controller),
+	)
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.Tracing{}, "wnc1.example.internal", "test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v, want nil", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v, want nil", err)
+	}
+}
+
+// TestSetup_ExportsToEndpoint stands a local collector in for a real one and checks
+// that a span started through the global provider reaches it by the time shutdown
+// returns, which is the flush the exporter relies on at exit.
+func TestSetup_ExportsToEndpoint(t *testing.T) {
+	var received atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			received.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	// A bare address, as an operator would give it, must still post to /v1/traces.
+	cfg := config.Tracing{Endpoint: collector.URL}
+	shutdown, err := Setup(context.Background(), cfg, "wnc1.example.internal", "test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v, want nil", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "wnc.refresh")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v, want nil", err)
+	}
+	if received.Load() == 0 {
+		t.Error("collector received no trace export, want the span flushed at shutdown")
+	}
+}
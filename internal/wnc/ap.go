@@ -4,6 +4,7 @@ package wnc
 
 import (
 	"context"
+	"time"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
 )
@@ -17,6 +18,12 @@ type APSource interface {
 	GetRadioResetStats(ctx context.Context) ([]ap.RadioResetStats, error)
 	ListNameMACMaps(ctx context.Context) ([]ap.ApNameMACMap, error)
 	GetAPJoinStats(ctx context.Context) ([]ap.ApJoinStats, error)
+	GetCDPNeighbors(ctx context.Context) ([]CDPNeighbor, error)
+	GetLLDPNeighbors(ctx context.Context) ([]LLDPNeighbor, error)
+	GetPowerInfo(ctx context.Context) ([]APPowerInfo, error)
+	GetMeshAPs(ctx context.Context) ([]MeshAPOperData, error)
+	GetRadioWMMStats(ctx context.Context) ([]RadioWMMStats, error)
+	GetRefreshedAt(ctx context.Context) (time.Time, error)
 }
 
 // apSource implements APSource using SharedDataSource for caching.
@@ -33,7 +40,7 @@ func NewAPSource(sharedDataSource DataSource) APSource {
 
 // GetCAPWAPData returns CAPWAP data from WNC via SharedDataSource (cached).
 func (s *apSource) GetCAPWAPData(ctx context.Context) ([]ap.CAPWAPData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPCAPWAPData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPCAPWAPData)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +49,7 @@ func (s *apSource) GetCAPWAPData(ctx context.Context) ([]ap.CAPWAPData, error) {
 
 // GetAPOperData retrieves AP operational data via SharedDataSource (cached).
 func (s *apSource) GetAPOperData(ctx context.Context) ([]ap.OperData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPOperData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPOperData)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +58,7 @@ func (s *apSource) GetAPOperData(ctx context.Context) ([]ap.OperData, error) {
 
 // GetRadioData returns radio operational data from WNC via SharedDataSource (cached).
 func (s *apSource) GetRadioData(ctx context.Context) ([]ap.RadioOperData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPRadioOperData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPRadioOperData)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +67,7 @@ func (s *apSource) GetRadioData(ctx context.Context) ([]ap.RadioOperData, error)
 
 // GetRadioOperStats returns radio operational statistics from WNC via SharedDataSource (cached).
 func (s *apSource) GetRadioOperStats(ctx context.Context) ([]ap.RadioOperStats, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPRadioOperStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPRadioOperStats)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +76,7 @@ func (s *apSource) GetRadioOperStats(ctx context.Context) ([]ap.RadioOperStats,
 
 // GetRadioResetStats returns radio reset statistics from WNC via SharedDataSource (cached).
 func (s *apSource) GetRadioResetStats(ctx context.Context) ([]ap.RadioResetStats, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPRadioResetStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPRadioResetStats)
 	if err != nil {
 		return nil, err
 	}
@@ -78,18 +85,74 @@ func (s *apSource) GetRadioResetStats(ctx context.Context) ([]ap.RadioResetStats
 
 // GetAPJoinStats returns CAPWAP join statistics from WNC via SharedDataSource (cached).
 func (s *apSource) GetAPJoinStats(ctx context.Context) ([]ap.ApJoinStats, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPJoinStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPJoinStats)
 	if err != nil {
 		return nil, err
 	}
 	return data.JoinStats, nil
 }
 
+// GetCDPNeighbors returns the CDP neighbors of every AP from WNC via SharedDataSource (cached).
+func (s *apSource) GetCDPNeighbors(ctx context.Context) ([]CDPNeighbor, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPCDPCacheData)
+	if err != nil {
+		return nil, err
+	}
+	return data.CDPNeighbors, nil
+}
+
+// GetLLDPNeighbors returns the LLDP neighbors of every AP from WNC via SharedDataSource (cached).
+func (s *apSource) GetLLDPNeighbors(ctx context.Context) ([]LLDPNeighbor, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPLLDPNeigh)
+	if err != nil {
+		return nil, err
+	}
+	return data.LLDPNeighbors, nil
+}
+
+// GetPowerInfo returns the power status of every AP from WNC via SharedDataSource (cached).
+func (s *apSource) GetPowerInfo(ctx context.Context) ([]APPowerInfo, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPPwrInfo)
+	if err != nil {
+		return nil, err
+	}
+	return data.APPowerInfo, nil
+}
+
+// GetMeshAPs returns the mesh state of every mesh AP from WNC via SharedDataSource (cached).
+func (s *apSource) GetMeshAPs(ctx context.Context) ([]MeshAPOperData, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPMeshOperData)
+	if err != nil {
+		return nil, err
+	}
+	return data.MeshAPs, nil
+}
+
+// GetRadioWMMStats returns the per-access-category queue statistics of every radio from
+// WNC via SharedDataSource (cached).
+func (s *apSource) GetRadioWMMStats(ctx context.Context) ([]RadioWMMStats, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPRadioWMMStats)
+	if err != nil {
+		return nil, err
+	}
+	return data.RadioWMMStats, nil
+}
+
 // ListNameMACMaps returns AP name to MAC mapping data from WNC via SharedDataSource (cached).
 func (s *apSource) ListNameMACMaps(ctx context.Context) ([]ap.ApNameMACMap, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataAPNameMACMap)
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPNameMACMap)
 	if err != nil {
 		return nil, err
 	}
 	return data.NameMACMaps, nil
 }
+
+// GetRefreshedAt returns when the refresh behind the snapshot the other methods read
+// started, which tells a module that compares snapshots whether this one is new.
+func (s *apSource) GetRefreshedAt(ctx context.Context) (time.Time, error) {
+	data, err := s.sharedDataSource.GetCachedData(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return data.RefreshedAt, nil
+}
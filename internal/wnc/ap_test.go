@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
 )
@@ -120,6 +121,33 @@ func newMockDataSource() *mockDataSource {
 					},
 				},
 			},
+			CDPNeighbors: []CDPNeighbor{
+				{
+					WtpMAC:         "aa:bb:cc:11:22:80",
+					DeviceID:       "access-sw01",
+					PortID:         "GigabitEthernet1/0/1",
+					InterfaceSpeed: "1000",
+				},
+			},
+			LLDPNeighbors: []LLDPNeighbor{
+				{
+					WtpMAC:     "aa:bb:cc:11:22:90",
+					SystemName: "access-sw02",
+					PortID:     "ge-0/0/7",
+				},
+			},
+			APPowerInfo: []APPowerInfo{
+				{WtpMAC: "aa:bb:cc:11:22:80", Status: "full-power"},
+				{WtpMAC: "aa:bb:cc:11:22:90", Status: "low-power"},
+			},
+			MeshAPs: []MeshAPOperData{
+				{WtpMAC: "aa:bb:cc:11:22:80", Role: "root-ap"},
+				{WtpMAC: "aa:bb:cc:11:22:90", Role: "mesh-ap", ParentMAC: "aa:bb:cc:11:22:80", HopCount: 1},
+			},
+			RadioWMMStats: []RadioWMMStats{
+				{WtpMAC: "aa:bb:cc:11:22:80", RadioSlotID: 1, AccessCategory: "voice", TxFrames: "8120", QueueDrops: "3"},
+				{WtpMAC: "aa:bb:cc:11:22:80", RadioSlotID: 1, AccessCategory: "best-effort", TxFrames: "91230"},
+			},
 		},
 	}
 }
@@ -577,3 +605,130 @@ func TestAPSource_GetAPJoinStats(t *testing.T) {
 		})
 	}
 }
+
+// TestAPSource_UplinkLists covers the three lists the uplink module reads. Each is read
+// through its own data type, so a failure of one must not take the other two with it.
+func TestAPSource_UplinkLists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		read    func(context.Context, APSource) (int, error)
+		failing string
+		wantLen int
+	}{
+		{
+			name: "CDP neighbors",
+			read: func(ctx context.Context, src APSource) (int, error) {
+				data, err := src.GetCDPNeighbors(ctx)
+				return len(data), err
+			},
+			failing: DataAPCDPCacheData,
+			wantLen: 1,
+		},
+		{
+			name: "LLDP neighbors",
+			read: func(ctx context.Context, src APSource) (int, error) {
+				data, err := src.GetLLDPNeighbors(ctx)
+				return len(data), err
+			},
+			failing: DataAPLLDPNeigh,
+			wantLen: 1,
+		},
+		{
+			name: "power status",
+			read: func(ctx context.Context, src APSource) (int, error) {
+				data, err := src.GetPowerInfo(ctx)
+				return len(data), err
+			},
+			failing: DataAPPwrInfo,
+			wantLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			got, err := tt.read(ctx, NewAPSource(newMockDataSource()))
+			if err != nil {
+				t.Fatalf("read error = %v, want nil", err)
+			}
+			if got != tt.wantLen {
+				t.Errorf("read returned %d items, want %d", got, tt.wantLen)
+			}
+
+			mock := newMockDataSource()
+			mock.data.FetchErrors = map[string]error{tt.failing: errors.New("fetch failed")}
+			if _, err := tt.read(ctx, NewAPSource(mock)); err == nil {
+				t.Errorf("read error = nil with %s failed, want the recorded fetch error", tt.failing)
+			}
+		})
+	}
+}
+
+// TestAPSource_GetMeshAPs covers the mesh list, which must pass a mesh AP through with
+// its parent and fail with the fetch error its data type recorded.
+func TestAPSource_GetMeshAPs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	data, err := NewAPSource(newMockDataSource()).GetMeshAPs(ctx)
+	if err != nil {
+		t.Fatalf("GetMeshAPs() error = %v, want nil", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("GetMeshAPs() returned %d items, want 2", len(data))
+	}
+	if data[1].ParentMAC != data[0].WtpMAC {
+		t.Errorf("GetMeshAPs() parent = %q, want %q", data[1].ParentMAC, data[0].WtpMAC)
+	}
+
+	mock := newMockDataSource()
+	mock.data.FetchErrors = map[string]error{DataAPMeshOperData: errors.New("fetch failed")}
+	if _, err := NewAPSource(mock).GetMeshAPs(ctx); err == nil {
+		t.Error("GetMeshAPs() error = nil with the mesh list failed, want the recorded fetch error")
+	}
+}
+
+// TestAPSource_GetRadioWMMStats covers the WMM list, which must pass a queue through with
+// the leaves it carries and fail with the fetch error its data type recorded.
+func TestAPSource_GetRadioWMMStats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	data, err := NewAPSource(newMockDataSource()).GetRadioWMMStats(ctx)
+	if err != nil {
+		t.Fatalf("GetRadioWMMStats() error = %v, want nil", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("GetRadioWMMStats() returned %d items, want 2", len(data))
+	}
+	if data[1].QueueDrops != "" {
+		t.Errorf("GetRadioWMMStats() drops = %q, want the omitted leaf left empty", data[1].QueueDrops)
+	}
+
+	mock := newMockDataSource()
+	mock.data.FetchErrors = map[string]error{DataAPRadioWMMStats: errors.New("fetch failed")}
+	if _, err := NewAPSource(mock).GetRadioWMMStats(ctx); err == nil {
+		t.Error("GetRadioWMMStats() error = nil with the WMM list failed, want the recorded fetch error")
+	}
+}
+
+func TestAPSource_GetRefreshedAt(t *testing.T) {
+	t.Parallel()
+
+	refreshedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	source := NewAPSource(&mockDataSource{data: &WNCDataCache{RefreshedAt: refreshedAt}})
+
+	got, err := source.GetRefreshedAt(context.Background())
+	if err != nil || !got.Equal(refreshedAt) {
+		t.Errorf("GetRefreshedAt() = %v, %v, want %v, nil", got, err, refreshedAt)
+	}
+
+	failing := NewAPSource(&mockDataSource{err: errors.New("cache refresh failed")})
+	if _, err := failing.GetRefreshedAt(context.Background()); err == nil {
+		t.Error("GetRefreshedAt() error = nil, want the data source's error")
+	}
+}
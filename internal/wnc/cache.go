@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"maps"
 	"net/http"
@@ -24,36 +25,47 @@ import (
 // Data type identifiers. The values mirror the RESTCONF container names with
 // hyphens replaced by underscores, prefixed by their subject when the container
 // name does not already start with it. They are the `data` label values, the
-// RefreshStats map keys and the FetchErrors keys, so they must not drift.
+// RefreshStats map keys, the FetchErrors keys and the sources the collectors declare
+// their families with, so they must not drift.
 const (
 	// gosec G101 matches the "pw" inside CAPWAP, not a credential.
-	dataAPCAPWAPData          = "ap_capwap_data" //nolint:gosec
-	dataAPOperData            = "ap_oper_data"
-	dataAPRadioOperData       = "ap_radio_oper_data"
-	dataAPNameMACMap          = "ap_name_mac_map"
-	dataAPRadioOperStats      = "ap_radio_oper_stats"
-	dataAPRadioResetStats     = "ap_radio_reset_stats"
-	dataAPJoinStats           = "ap_join_stats"
-	dataWLANClientStats       = "wlan_client_stats"
-	dataClientCommonOperData  = "client_common_oper_data"
-	dataClientDCInfo          = "client_dc_info"
-	dataClientDot11OperData   = "client_dot11_oper_data"
-	dataClientSISFDBMac       = "client_sisf_db_mac"
-	dataClientTrafficStats    = "client_traffic_stats"
-	dataClientMMIFHistory     = "client_mm_if_client_history"
-	dataRRMMeasurement        = "rrm_measurement"
-	dataRRMCoverage           = "rrm_coverage"
-	dataRRMAPDot11RadarData   = "rrm_ap_dot11_radar_data"
-	dataRRMRadioSlot          = "rrm_radio_slot"
-	dataRRMMainData           = "rrm_main_data"
-	dataRRMSpectrumAqWorst    = "rrm_spectrum_aq_worst_table"
-	dataRRMSpectrumAqTable    = "rrm_spectrum_aq_table"
-	dataControllerBootTime    = "controller_boot_time"
-	dataCoClientDelReason     = "co_client_del_reason"
-	dataClientRoamingStats    = "client_roaming_stats"
-	dataWLANCfgEntries        = "wlan_cfg_entries"
-	dataWLANPolicies          = "wlan_policies"
-	dataWLANPolicyListEntries = "wlan_policy_list_entries"
+	DataAPCAPWAPData          = "ap_capwap_data" //nolint:gosec
+	DataAPOperData            = "ap_oper_data"
+	DataAPRadioOperData       = "ap_radio_oper_data"
+	DataAPNameMACMap          = "ap_name_mac_map"
+	DataAPRadioOperStats      = "ap_radio_oper_stats"
+	DataAPRadioResetStats     = "ap_radio_reset_stats"
+	DataAPJoinStats           = "ap_join_stats"
+	DataAPCDPCacheData        = "ap_cdp_cache_data"
+	DataAPLLDPNeigh           = "ap_lldp_neigh"
+	DataAPPwrInfo             = "ap_pwr_info"
+	DataAPMeshOperData        = "ap_mesh_oper_data"
+	DataAPRadioWMMStats       = "ap_radio_wmm_stats"
+	DataAPRadioATFStats       = "ap_radio_atf_stats"
+	DataWLANClientStats       = "wlan_client_stats"
+	DataWLANAVCStats          = "wlan_avc_stats"
+	DataClientCommonOperData  = "client_common_oper_data"
+	DataClientDCInfo          = "client_dc_info"
+	DataClientDot11OperData   = "client_dot11_oper_data"
+	DataClientSISFDBMac       = "client_sisf_db_mac"
+	DataClientTrafficStats    = "client_traffic_stats"
+	DataClientMMIFHistory     = "client_mm_if_client_history"
+	DataRRMMeasurement        = "rrm_measurement"
+	DataRRMCoverage           = "rrm_coverage"
+	DataRRMAPDot11RadarData   = "rrm_ap_dot11_radar_data"
+	DataRRMAPAutoRFDot11Data  = "rrm_ap_auto_rf_dot11_data"
+	DataRRMRadioSlot          = "rrm_radio_slot"
+	DataRRMMainData           = "rrm_main_data"
+	DataRRMSpectrumDevice     = "rrm_spectrum_device_table"
+	DataRRMSpectrumAqWorst    = "rrm_spectrum_aq_worst_table"
+	DataRRMSpectrumAqTable    = "rrm_spectrum_aq_table"
+	DataControllerBootTime    = "controller_boot_time"
+	DataCoClientDelReason     = "co_client_del_reason"
+	DataClientRoamingStats    = "client_roaming_stats"
+	DataAAARadiusStats        = "aaa_radius_stats"
+	DataWLANCfgEntries        = "wlan_cfg_entries"
+	DataWLANPolicies          = "wlan_policies"
+	DataWLANPolicyListEntries = "wlan_policy_list_entries"
 )
 
 // refreshDeadlineFactor bounds a whole refresh at this multiple of the cache TTL.
@@ -77,6 +89,20 @@ type WNCDataCache struct {
 	NameMACMaps     []ap.ApNameMACMap
 	JoinStats       []ap.ApJoinStats
 
+	// AP uplink data. Each comes from a list the SDK has no route for, so the records
+	// are this package's own.
+	CDPNeighbors  []CDPNeighbor
+	LLDPNeighbors []LLDPNeighbor
+	APPowerInfo   []APPowerInfo
+
+	// AP mesh data, from a list the SDK has no route for either.
+	MeshAPs []MeshAPOperData
+
+	// AP radio QoS data. The WMM queue statistics and the Air Time Fairness statistics
+	// each come from a list the SDK has no route for.
+	RadioWMMStats []RadioWMMStats
+	RadioATFStats []RadioATFStats
+
 	CommonOperData    []client.CommonOperData
 	DCInfo            []client.DcInfo
 	Dot11OperData     []client.Dot11OperData
@@ -92,12 +118,20 @@ type WNCDataCache struct {
 	RRMMainData      []rrm.MainData
 	SpectrumAqTable  []rrm.SpectrumAqTable
 	SpectrumAqWorst  []rrm.SpectrumAqWorstTable
+	// RRMNeighbors comes from a list the SDK has no route for, so its records are this
+	// package's own.
+	RRMNeighbors []RRMNeighborData
+	// SpectrumDevices comes from a list the SDK has no route for either.
+	SpectrumDevices []SpectrumDevice
 
 	// Controller-wide data. Each comes from a container the SDK has no route for, and
 	// each is empty rather than absent when the controller does not carry it.
 	ControllerBootTime  string
 	ClientDeleteReasons map[string]float64
 	ClientRoamingStats  map[string]float64
+	// AAAServers comes from a list the SDK has no route for, so its records are this
+	// package's own.
+	AAAServers []AAARadiusServer
 
 	// WLAN data. The per-WLAN client statistics live in the AP global operational
 	// subtree, so the SDK types them in its ap service package.
@@ -105,10 +139,14 @@ type WNCDataCache struct {
 	WLANConfigEntries     []wlan.WlanCfgEntry
 	WLANPolicies          []wlan.WlanPolicy
 	WLANPolicyListEntries []wlan.PolicyListEntry
+	// WLANAppStats comes from a list the SDK has no route for, so its records are this
+	// package's own.
+	WLANAppStats []WLANAppStats
 
 	// FetchErrors records the failure per data type so callers skip derived
-	// metrics instead of publishing a fabricated zero.
-	FetchErrors map[string]error
+	// metrics instead of publishing a fabricated zero. An error value does not
+	// survive encoding, so the snapshot file carries it as text.
+	FetchErrors map[string]error `json:"-"`
 	// RefreshedAt is when the refresh that produced this snapshot started, which
 	// bounds the age of every datum it carries.
 	RefreshedAt time.Time
@@ -132,11 +170,22 @@ type RefreshStats struct {
 	Duration time.Duration
 	// Errors counts failures per data type since process start.
 	Errors map[string]int
+	// Retries counts fetches tried again per data type since process start.
+	Retries map[string]int
 	// Items counts what each data type returned, recorded on success only.
 	Items map[string]int
 	// DefaultsFallbacks counts WLAN configuration fetches that asked for the
 	// values in force and had to settle for a plain read, since process start.
 	DefaultsFallbacks int64
+	// Requests counts the requests made per data type and outcome code since
+	// process start. RequestDurations and ResponseSizes hold their histograms, the
+	// latter for the requests answered with a whole body.
+	Requests         map[string]map[string]int
+	RequestDurations map[string]Histogram
+	ResponseSizes    map[string]Histogram
+	// Restored reports whether the served snapshot was loaded from the snapshot
+	// file rather than produced by a refresh of this process.
+	Restored bool
 }
 
 // StatsProvider is implemented by data sources that report refresh statistics.
@@ -149,6 +198,19 @@ type dataSource struct {
 	client    *wnc.Client
 	refresher *refresher
 	cacheTTL  time.Duration
+	retry     retryPolicy
+	requests  *requestMeter
+
+	// snapshotFile is where every refreshed snapshot is saved. Empty disables it.
+	snapshotFile   string
+	snapshotMaxAge time.Duration
+	// restored is the snapshot loaded from the file at startup, nil when none was.
+	// It is only written by NewDataSource, so it needs no lock.
+	restored *WNCDataCache
+
+	// refreshed carries the announcement of the last successful refresh until a
+	// push loop reads it. It holds one, so the refresh never waits on the reader.
+	refreshed chan time.Time
 
 	// names lists the data types the enabled modules read, in fetch order. It is
 	// the seeded `data` label set and the denominator every refresh outcome is
@@ -167,6 +229,7 @@ type dataSource struct {
 
 	mu        sync.Mutex
 	errors    map[string]int
+	retries   map[string]int
 	items     map[string]int
 	duration  time.Duration
 	up        bool
@@ -183,25 +246,70 @@ type dataFetcher struct {
 // enabled modules need, so enabling one module does not poll the controller for the
 // data the others would have read.
 func NewDataSource(cfg config.WNC, modules config.Collectors) DataSource {
-	names := requiredDataTypes(modules)
+	return NewDataSourceFor(cfg, requiredDataTypes(modules))
+}
+
+// NewDataSourceFor creates a new shared data source that reads the given data types, in
+// fetch order, for a caller that knows which of them the published families read. A
+// name that is not a data type is ignored.
+func NewDataSourceFor(cfg config.WNC, dataTypes []string) DataSource {
+	names := make([]string, 0, len(dataTypes))
+	for _, name := range dataTypeNames {
+		if slices.Contains(dataTypes, name) {
+			names = append(names, name)
+		}
+	}
 	s := &dataSource{
-		client:   createWNCClient(cfg),
-		cacheTTL: cfg.CacheTTL,
-		names:    names,
-		errors:   make(map[string]int, len(names)),
+		cacheTTL:       cfg.CacheTTL,
+		retry:          newRetryPolicy(cfg),
+		requests:       newRequestMeter(),
+		snapshotFile:   cfg.SnapshotFile,
+		snapshotMaxAge: cfg.SnapshotMaxAge,
+		refreshed:      make(chan time.Time, 1),
+		names:          names,
+		errors:         make(map[string]int, len(names)),
+		retries:        make(map[string]int, len(names)),
 	}
 
-	// Seed every data type so the error series exist on the first scrape, which
-	// is the scrape most likely to be reporting a failure. Seeding the required
-	// set is what lets an operator tell a failed fetch from one never requested.
+	// Seed every data type so the error and retry series exist on the first
+	// scrape, which is the scrape most likely to be reporting a failure. Seeding
+	// the required set is what lets an operator tell a failed fetch from one never
+	// requested.
 	for _, name := range names {
 		s.errors[name] = 0
+		s.retries[name] = 0
 	}
 
+	s.client = createWNCClient(cfg, s.requests)
 	s.refresher = newRefresher(cfg.CacheTTL, s.fetchAllData, s.onRefreshDone)
+	if s.snapshotFile != "" {
+		s.restore()
+	}
 	return s
 }
 
+// restore publishes the snapshot the snapshot file carries, so the scrapes before the
+// first refresh completes serve it rather than nothing.
+//
+// A file that is missing, unreadable or too old starts without one rather than failing
+// the exporter, since what it loses is the data of the scrapes before the first refresh.
+func (s *dataSource) restore() {
+	data, err := loadSnapshot(s.snapshotFile, s.snapshotMaxAge, s.names)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return
+	case err != nil:
+		slog.Warn("failed to restore WNC data snapshot, starting without one",
+			"file", s.snapshotFile, "error", err)
+		return
+	}
+
+	s.restored = data
+	s.refresher.cur.Store(data)
+	slog.Info("restored WNC data snapshot", "file", s.snapshotFile,
+		"refreshed_at", data.RefreshedAt)
+}
+
 // snapshot returns the cached data unless the given data type failed to fetch.
 // Reading FetchErrors from a nil map yields nil, so snapshots built in tests
 // need no guard.
@@ -230,6 +338,13 @@ func (s *dataSource) GetCachedData(_ context.Context) (*WNCDataCache, error) {
 	if data == nil {
 		return nil, errNoSnapshot
 	}
+	// A restored snapshot is served for the configured age only, counted from the
+	// refresh that produced it, so a controller that stays unreachable after a restart
+	// does not pass the data of the previous process off as current.
+	if data == s.restored && time.Since(data.RefreshedAt) > s.snapshotMaxAge {
+		return nil, fmt.Errorf("%w: restored snapshot is older than %s",
+			errSnapshotWithheld, s.snapshotMaxAge)
+	}
 	if withheld {
 		return nil, fmt.Errorf("%w after %d consecutive failed refreshes",
 			errSnapshotWithheld, maxConsecutiveRefreshFailures)
@@ -257,11 +372,14 @@ func (s *dataSource) Stats() RefreshStats {
 		Attempted: s.attempted,
 		Duration:  s.duration,
 		Errors:    maps.Clone(s.errors),
+		Retries:   maps.Clone(s.retries),
 		Items:     maps.Clone(s.items),
 	}
 	st.DefaultsFallbacks = s.defaultsFallbacks.Load()
+	st.Requests, st.RequestDurations, st.ResponseSizes = s.requests.snapshot()
 	if snap != nil {
 		st.RefreshedAt = snap.RefreshedAt
+		st.Restored = snap == s.restored
 	}
 	return st
 }
@@ -271,6 +389,10 @@ func (s *dataSource) Stats() RefreshStats {
 func (s *dataSource) onRefreshDone(err error, elapsed time.Duration) {
 	if err == nil {
 		s.failures.Store(0)
+		if s.snapshotFile != "" {
+			s.persist()
+		}
+		s.announce()
 		return
 	}
 
@@ -280,6 +402,14 @@ func (s *dataSource) onRefreshDone(err error, elapsed time.Duration) {
 	}
 }
 
+// persist saves the published snapshot to the snapshot file. A failure is logged and
+// not retried: the next successful refresh writes a newer one anyway.
+func (s *dataSource) persist() {
+	if err := saveSnapshot(s.snapshotFile, s.refresher.cur.Load(), s.names); err != nil {
+		slog.Warn("failed to save WNC data snapshot", "file", s.snapshotFile, "error", err)
+	}
+}
+
 // recordRefresh publishes the outcome of a refresh attempt for the collector.
 func (s *dataSource) recordRefresh(items map[string]int, failures []string, duration time.Duration) {
 	s.mu.Lock()
@@ -333,7 +463,7 @@ func (s *dataSource) fetchAllData(ctx context.Context) (*WNCDataCache, error) {
 		}
 
 		fetchStart := time.Now()
-		count, err := f.fetch(ctx, data)
+		count, err := s.fetchWithRetry(ctx, f, data)
 		if err != nil {
 			failures = append(failures, f.name)
 			data.FetchErrors[f.name] = err
@@ -370,7 +500,8 @@ func (s *dataSource) fetchAllData(ctx context.Context) (*WNCDataCache, error) {
 // wnc_wlan_ft_state that is an empty spelling, which withholds the series
 // instead.
 // A controller that accepts the parameter and ignores it answers 200, so the
-// fallback counter cannot report that case.
+// fallback counter cannot report that case. The re-read is traced as a span of
+// its own, carrying the status the controller rejected the first read with.
 func readEffective[T any](
 	ctx context.Context,
 	fallbacks *atomic.Int64,
@@ -390,5 +521,9 @@ func readEffective[T any](
 	slog.Warn("controller rejected the request for values in force, re-reading without it",
 		"status", apiErr.StatusCode)
 
-	return list(ctx)
+	ctx, span := startSpan(ctx, spanFallback, attrStatus.Int(apiErr.StatusCode))
+	data, err = list(ctx)
+	endSpan(span, err)
+
+	return data, err
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,6 +52,29 @@ func TestNewDataSource(t *testing.T) {
 	}
 }
 
+// TestNewDataSourceFor reads the data types it is given in fetch order, whatever order
+// they are given in, and ignores a name that is not one.
+func TestNewDataSourceFor(t *testing.T) {
+	t.Parallel()
+
+	ds, ok := NewDataSourceFor(config.WNC{
+		Controller:  "wnc1.example.internal",
+		AccessToken: "test-token",
+		CacheTTL:    55 * time.Second,
+	}, []string{DataRRMMeasurement, "not_a_data_type", DataAPCAPWAPData}).(*dataSource)
+	if !ok {
+		t.Fatal("NewDataSourceFor did not return *dataSource")
+	}
+	suppressBackgroundRefresh(ds)
+
+	if want := []string{DataAPCAPWAPData, DataRRMMeasurement}; !slices.Equal(ds.names, want) {
+		t.Errorf("names = %v, want %v", ds.names, want)
+	}
+	if stats := ds.Stats(); len(stats.Errors) != 2 {
+		t.Errorf("Stats().Errors = %v, want the two data types seeded at zero", stats.Errors)
+	}
+}
+
 func TestDataSource_GetCachedData_MockSuccess(t *testing.T) {
 	t.Parallel()
 
@@ -101,18 +127,18 @@ func TestSnapshot_SkipsFailedDataType(t *testing.T) {
 
 	src := &mockDataSource{data: &WNCDataCache{
 		CAPWAPData:  []ap.CAPWAPData{{WtpMAC: mockAPMAC}},
-		FetchErrors: map[string]error{dataAPCAPWAPData: errors.New("fetch failed")},
+		FetchErrors: map[string]error{DataAPCAPWAPData: errors.New("fetch failed")},
 	}}
 	ctx := context.Background()
 
-	if _, err := snapshot(ctx, src, dataAPCAPWAPData); err == nil {
-		t.Errorf("snapshot(%s) error = nil, want the recorded fetch error", dataAPCAPWAPData)
+	if _, err := snapshot(ctx, src, DataAPCAPWAPData); err == nil {
+		t.Errorf("snapshot(%s) error = nil, want the recorded fetch error", DataAPCAPWAPData)
 	}
 
-	data, err := snapshot(ctx, src, dataAPOperData)
+	data, err := snapshot(ctx, src, DataAPOperData)
 	if err != nil {
 		t.Fatalf("snapshot(%s) error = %v, want nil for a data type that did not fail",
-			dataAPOperData, err)
+			DataAPOperData, err)
 	}
 	if len(data.CAPWAPData) != 1 {
 		t.Errorf("snapshot() CAPWAPData length = %d, want 1", len(data.CAPWAPData))
@@ -355,14 +381,14 @@ func TestFetchers_MatchDataTypeNames(t *testing.T) {
 			t.Errorf("fetchers()[%d].name = %s, want %s", i, f.name, dataTypeNames[i])
 		}
 	}
-	if dataTypeNames[0] != dataAPCAPWAPData {
+	if dataTypeNames[0] != DataAPCAPWAPData {
 		t.Errorf("dataTypeNames[0] = %s, want %s: the AP inventory labels every other AP series",
-			dataTypeNames[0], dataAPCAPWAPData)
+			dataTypeNames[0], DataAPCAPWAPData)
 	}
-	if last := dataTypeNames[len(dataTypeNames)-1]; last != dataRRMSpectrumAqTable {
+	if last := dataTypeNames[len(dataTypeNames)-1]; last != DataRRMSpectrumAqTable {
 		t.Errorf("dataTypeNames ends with %s, want %s: the air quality table is the largest RRM "+
 			"read, and docs/collector.ap.md ships that a truncated refresh drops it first",
-			last, dataRRMSpectrumAqTable)
+			last, DataRRMSpectrumAqTable)
 	}
 }
 
@@ -431,7 +457,7 @@ func TestDataSource_FetchAllData_Success(t *testing.T) {
 func TestDataSource_FetchAllData_PartialFailure(t *testing.T) {
 	t.Parallel()
 
-	server := newMockWNCServer(failing(dataAPCAPWAPData))
+	server := newMockWNCServer(failing(DataAPCAPWAPData))
 	defer server.Close()
 
 	ds := newTestDataSource(t, server.URL, 55*time.Second)
@@ -440,11 +466,11 @@ func TestDataSource_FetchAllData_PartialFailure(t *testing.T) {
 	if err != nil {
 		t.Fatalf("fetchAllData() error = %v, want nil: a partial failure must not discard the snapshot", err)
 	}
-	if data.FetchErrors[dataAPCAPWAPData] == nil {
-		t.Errorf("FetchErrors[%s] = nil, want the fetch error so collectors skip its series", dataAPCAPWAPData)
+	if data.FetchErrors[DataAPCAPWAPData] == nil {
+		t.Errorf("FetchErrors[%s] = nil, want the fetch error so collectors skip its series", DataAPCAPWAPData)
 	}
-	if err := data.FetchErrors[dataAPRadioOperData]; err != nil {
-		t.Errorf("FetchErrors[%s] = %v, want nil", dataAPRadioOperData, err)
+	if err := data.FetchErrors[DataAPRadioOperData]; err != nil {
+		t.Errorf("FetchErrors[%s] = %v, want nil", DataAPRadioOperData, err)
 	}
 	if len(data.CAPWAPData) != 0 {
 		t.Errorf("CAPWAPData length = %d, want 0 because the fetch failed", len(data.CAPWAPData))
@@ -458,15 +484,15 @@ func TestDataSource_FetchAllData_PartialFailure(t *testing.T) {
 	if !stats.Up {
 		t.Error("Stats().Up = false, want true while at least one data type succeeds")
 	}
-	if stats.Errors[dataAPCAPWAPData] != 1 {
-		t.Errorf("Stats().Errors[%s] = %d, want 1", dataAPCAPWAPData, stats.Errors[dataAPCAPWAPData])
+	if stats.Errors[DataAPCAPWAPData] != 1 {
+		t.Errorf("Stats().Errors[%s] = %d, want 1", DataAPCAPWAPData, stats.Errors[DataAPCAPWAPData])
 	}
-	if _, ok := stats.Items[dataAPCAPWAPData]; ok {
+	if _, ok := stats.Items[DataAPCAPWAPData]; ok {
 		t.Errorf("Stats().Items records %s, want it absent so wnc_refresh_items reports no zero",
-			dataAPCAPWAPData)
+			DataAPCAPWAPData)
 	}
-	if stats.Items[dataAPRadioOperData] != 1 {
-		t.Errorf("Stats().Items[%s] = %d, want 1", dataAPRadioOperData, stats.Items[dataAPRadioOperData])
+	if stats.Items[DataAPRadioOperData] != 1 {
+		t.Errorf("Stats().Items[%s] = %d, want 1", DataAPRadioOperData, stats.Items[DataAPRadioOperData])
 	}
 }
 
@@ -523,7 +549,7 @@ func TestDataSource_FetchAllData_TruncatedByDeadline(t *testing.T) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		if ep.dataType != dataAPCAPWAPData {
+		if ep.dataType != DataAPCAPWAPData {
 			<-r.Context().Done()
 			return
 		}
@@ -538,8 +564,8 @@ func TestDataSource_FetchAllData_TruncatedByDeadline(t *testing.T) {
 	if err != nil {
 		t.Fatalf("fetchAllData() error = %v, want nil while the first data type succeeds", err)
 	}
-	if err := data.FetchErrors[dataAPCAPWAPData]; err != nil {
-		t.Errorf("FetchErrors[%s] = %v, want nil", dataAPCAPWAPData, err)
+	if err := data.FetchErrors[DataAPCAPWAPData]; err != nil {
+		t.Errorf("FetchErrors[%s] = %v, want nil", DataAPCAPWAPData, err)
 	}
 	if len(data.CAPWAPData) != 1 {
 		t.Errorf("CAPWAPData length = %d, want 1", len(data.CAPWAPData))
@@ -725,14 +751,17 @@ func TestConfig_RefreshDeadlineExceedsPerRequestTimeout(t *testing.T) {
 
 // RESTCONF module names the mock replies are keyed by.
 const (
+	mockAAAOperModule        = "Cisco-IOS-XE-aaa-oper"
 	mockAPGlobalOperModule   = "Cisco-IOS-XE-wireless-ap-global-oper"
 	mockClientGlobalModule   = "Cisco-IOS-XE-wireless-client-global-oper"
 	mockDeviceHardwareModule = "Cisco-IOS-XE-device-hardware-oper"
+	mockMeshOperModule       = "Cisco-IOS-XE-wireless-mesh-oper"
 	mockAPOperModule         = "Cisco-IOS-XE-wireless-access-point-oper"
 	mockClientOperModule     = "Cisco-IOS-XE-wireless-client-oper"
 	mockRRMOperModule        = "Cisco-IOS-XE-wireless-rrm-oper"
 	mockRRMGlobalOperModule  = "Cisco-IOS-XE-wireless-rrm-global-oper"
 	mockWLANCfgModule        = "Cisco-IOS-XE-wireless-wlan-cfg"
+	mockAVCOperModule        = "Cisco-IOS-XE-wireless-avc-oper"
 )
 
 const (
@@ -751,63 +780,87 @@ type mockEndpoint struct {
 // them exactly: substring matching routed every client and RRM request into the AP
 // branch, which left most of the per-data-type failure switches unexercised.
 var mockEndpoints = map[string]mockEndpoint{
-	"capwap-data": {dataAPCAPWAPData, mockList(mockAPOperModule, "capwap-data",
+	"capwap-data": {DataAPCAPWAPData, mockList(mockAPOperModule, "capwap-data",
 		`{"wtp-mac":"`+mockAPMAC+`","ip-addr":"192.168.255.11","name":"TEST-AP01"}`)},
-	"oper-data": {dataAPOperData, mockList(mockAPOperModule, "oper-data",
+	"oper-data": {DataAPOperData, mockList(mockAPOperModule, "oper-data",
 		`{"wtp-mac":"`+mockAPMAC+`","radio-id":0}`)},
-	"radio-oper-data": {dataAPRadioOperData, mockList(mockAPOperModule, "radio-oper-data",
+	"radio-oper-data": {DataAPRadioOperData, mockList(mockAPOperModule, "radio-oper-data",
 		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":0}`)},
-	"ap-name-mac-map": {dataAPNameMACMap, mockList(mockAPOperModule, "ap-name-mac-map",
+	"ap-name-mac-map": {DataAPNameMACMap, mockList(mockAPOperModule, "ap-name-mac-map",
 		`{"wtp-name":"TEST-AP01","eth-mac":"`+mockAPMAC+`"}`)},
-	"radio-oper-stats": {dataAPRadioOperStats, mockList(mockAPOperModule, "radio-oper-stats",
+	"radio-oper-stats": {DataAPRadioOperStats, mockList(mockAPOperModule, "radio-oper-stats",
 		`{"ap-mac":"`+mockAPMAC+`","slot-id":0}`)},
-	"radio-reset-stats": {dataAPRadioResetStats, mockList(mockAPOperModule, "radio-reset-stats",
+	"radio-reset-stats": {DataAPRadioResetStats, mockList(mockAPOperModule, "radio-reset-stats",
 		`{"ap-mac":"`+mockAPMAC+`","radio-id":0}`)},
-	"ap-join-stats": {dataAPJoinStats, mockList(mockAPGlobalOperModule, "ap-join-stats",
+	"ap-join-stats": {DataAPJoinStats, mockList(mockAPGlobalOperModule, "ap-join-stats",
 		`{"wtp-mac":"`+mockAPMAC+`","ap-join-info":{"ap-name":"TEST-AP01","is-joined":true}}`)},
-	"wlan-client-stats": {dataWLANClientStats, mockList(mockAPGlobalOperModule, "wlan-client-stats",
+	"cdp-cache-data": {DataAPCDPCacheData, mockList(mockAPOperModule, "cdp-cache-data",
+		`{"mac-addr":"`+mockAPMAC+`","cdp-cache-device-id":"access-sw01",`+
+			`"cdp-cache-port-id":"GigabitEthernet1/0/1","cdp-cache-interface-speed":1000}`)},
+	"lldp-neigh": {DataAPLLDPNeigh, mockList(mockAPOperModule, "lldp-neigh",
+		`{"wtp-mac":"`+mockAPMAC+`","system-name":"access-sw01","port-id":"gi1/0/1"}`)},
+	"ap-pwr-info": {DataAPPwrInfo, mockList(mockAPOperModule, "ap-pwr-info",
+		`{"wtp-mac":"`+mockAPMAC+`","status":"full-power"}`)},
+	"mesh-ap-oper-data": {DataAPMeshOperData, mockList(mockMeshOperModule, "mesh-ap-oper-data",
+		`{"wtp-mac":"`+mockAPMAC+`","ap-role":"root-ap","hop-count":0,"bhaul-channel":36}`)},
+	"radio-wmm-stats": {DataAPRadioWMMStats, mockList(mockAPOperModule, "radio-wmm-stats",
+		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":1,"access-category":"voice","tx-frames":"8120","queue-drops":"3"}`)},
+	"radio-atf-stats": {DataAPRadioATFStats, mockList(mockAPOperModule, "radio-atf-stats",
+		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":1,"wlan-id":1,"atf-policy-name":"voice-first",`+
+			`"airtime-allocation":"40","airtime-used":"1250000"}`)},
+	"wlan-client-stats": {DataWLANClientStats, mockList(mockAPGlobalOperModule, "wlan-client-stats",
 		`{"wlan-id":1,"data-usage":"6884480"}`)},
-	"common-oper-data": {dataClientCommonOperData, mockList(mockClientOperModule, "common-oper-data",
+	"common-oper-data": {DataClientCommonOperData, mockList(mockClientOperModule, "common-oper-data",
 		`{"client-mac":"`+mockClientMAC+`"}`)},
-	"dc-info": {dataClientDCInfo, mockList(mockClientOperModule, "dc-info",
+	"dc-info": {DataClientDCInfo, mockList(mockClientOperModule, "dc-info",
 		`{"client-mac":"`+mockClientMAC+`"}`)},
-	"dot11-oper-data": {dataClientDot11OperData, mockList(mockClientOperModule, "dot11-oper-data",
+	"dot11-oper-data": {DataClientDot11OperData, mockList(mockClientOperModule, "dot11-oper-data",
 		`{"ms-mac-address":"`+mockClientMAC+`"}`)},
-	"sisf-db-mac": {dataClientSISFDBMac, mockList(mockClientOperModule, "sisf-db-mac",
+	"sisf-db-mac": {DataClientSISFDBMac, mockList(mockClientOperModule, "sisf-db-mac",
 		`{"mac-addr":"`+mockClientMAC+`"}`)},
-	"traffic-stats": {dataClientTrafficStats, mockList(mockClientOperModule, "traffic-stats",
+	"traffic-stats": {DataClientTrafficStats, mockList(mockClientOperModule, "traffic-stats",
 		`{"ms-mac-address":"`+mockClientMAC+`"}`)},
-	"mm-if-client-history": {dataClientMMIFHistory, mockList(mockClientOperModule, "mm-if-client-history",
+	"mm-if-client-history": {DataClientMMIFHistory, mockList(mockClientOperModule, "mm-if-client-history",
 		`{"client-mac":"`+mockClientMAC+`"}`)},
-	"rrm-measurement": {dataRRMMeasurement, mockList(mockRRMOperModule, "rrm-measurement",
+	"rrm-measurement": {DataRRMMeasurement, mockList(mockRRMOperModule, "rrm-measurement",
 		`{"wtp-mac":"`+mockAPMAC+`"}`)},
-	"rrm-coverage": {dataRRMCoverage, mockList(mockRRMGlobalOperModule, "rrm-coverage",
+	"rrm-coverage": {DataRRMCoverage, mockList(mockRRMGlobalOperModule, "rrm-coverage",
 		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":0}`)},
-	"ap-dot11-radar-data": {dataRRMAPDot11RadarData, mockList(mockRRMOperModule, "ap-dot11-radar-data",
+	"ap-auto-rf-dot11-data": {DataRRMAPAutoRFDot11Data, mockList(mockRRMOperModule, "ap-auto-rf-dot11-data",
+		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":1,"neighbor-radio-info":{"neighbor-radio-list":`+
+			`[{"neighbor-radio-info":{"neighbor-radio-mac":"aa:bb:cc:11:22:20","rssi":-58,"channel":36}}]}}`)},
+	"ap-dot11-radar-data": {DataRRMAPDot11RadarData, mockList(mockRRMOperModule, "ap-dot11-radar-data",
 		`{"wtp-mac":"`+mockAPMAC+`"}`)},
-	"radio-slot": {dataRRMRadioSlot, mockList(mockRRMOperModule, "radio-slot",
+	"radio-slot": {DataRRMRadioSlot, mockList(mockRRMOperModule, "radio-slot",
 		`{"wtp-mac":"`+mockAPMAC+`","radio-slot-id":0}`)},
-	"main-data": {dataRRMMainData, mockList(mockRRMOperModule, "main-data",
+	"main-data": {DataRRMMainData, mockList(mockRRMOperModule, "main-data",
 		`{"phy-type":"dot11-5-ghz-band"}`)},
-	"spectrum-aq-table": {dataRRMSpectrumAqTable, mockList(mockRRMOperModule, "spectrum-aq-table",
+	"spectrum-aq-table": {DataRRMSpectrumAqTable, mockList(mockRRMOperModule, "spectrum-aq-table",
 		`{"wtp-mac":"`+mockAPMAC+`","band":"dot11-2-dot-4-ghz-band"}`)},
-	"spectrum-aq-worst-table": {dataRRMSpectrumAqWorst, mockList(mockRRMGlobalOperModule, "spectrum-aq-worst-table",
+	"spectrum-device-table": {DataRRMSpectrumDevice, mockList(mockRRMOperModule, "spectrum-device-table",
+		`{"device-id":"00:00:00:00:10:01","wtp-mac":"`+mockAPMAC+`","radio-slot-id":0,`+
+			`"dev-type":"si-dev-type-microwave-oven","channel":6,"severity":12,"duty-cycle":40}`)},
+	"spectrum-aq-worst-table": {DataRRMSpectrumAqWorst, mockList(mockRRMGlobalOperModule, "spectrum-aq-worst-table",
 		`{"band-id":1,"channel-num":11}`)},
 	// The two raw reads answer with the node itself as the only key rather than with a
 	// list, which is what mockContainer wraps and mockList cannot.
-	"boot-time": {dataControllerBootTime, mockContainer(mockDeviceHardwareModule, "boot-time",
+	"boot-time": {DataControllerBootTime, mockContainer(mockDeviceHardwareModule, "boot-time",
 		`"2026-01-01T00:00:00+00:00"`)},
-	"co-client-del-reason": {dataCoClientDelReason, mockContainer(mockClientGlobalModule, "co-client-del-reason",
+	"co-client-del-reason": {DataCoClientDelReason, mockContainer(mockClientGlobalModule, "co-client-del-reason",
 		// One leaf, because every mock here answers with exactly one item.
 		`{"ap-delete":24665}`)},
-	"client-roaming-stats": {dataClientRoamingStats, mockContainer(mockClientGlobalModule, "client-roaming-stats",
+	"client-roaming-stats": {DataClientRoamingStats, mockContainer(mockClientGlobalModule, "client-roaming-stats",
 		// One leaf, because every mock here answers with exactly one item.
 		`{"ap-auth-roams":30829}`)},
-	"wlan-cfg-entries": {dataWLANCfgEntries, mockNestedList(mockWLANCfgModule, "wlan-cfg-entries",
+	"aaa-radius-stats": {DataAAARadiusStats, mockList(mockAAAOperModule, "aaa-radius-stats",
+		`{"group-name":"radius-group","radius-server-ip":"192.168.255.50","server-state":"up"}`)},
+	"avc-wlan-stats": {DataWLANAVCStats, mockList(mockAVCOperModule, "avc-wlan-stats",
+		`{"wlan-id":1,"app-name":"ms-teams","direction":"ingress","bytes":"1048576","packets":"900"}`)},
+	"wlan-cfg-entries": {DataWLANCfgEntries, mockNestedList(mockWLANCfgModule, "wlan-cfg-entries",
 		"wlan-cfg-entry", `{"wlan-id":1}`)},
-	"wlan-policies": {dataWLANPolicies, mockNestedList(mockWLANCfgModule, "wlan-policies",
+	"wlan-policies": {DataWLANPolicies, mockNestedList(mockWLANCfgModule, "wlan-policies",
 		"wlan-policy", `{"policy-profile-name":"test-policy"}`)},
-	"policy-list-entries": {dataWLANPolicyListEntries, mockNestedList(mockWLANCfgModule, "policy-list-entries",
+	"policy-list-entries": {DataWLANPolicyListEntries, mockNestedList(mockWLANCfgModule, "policy-list-entries",
 		"policy-list-entry", `{"tag-name":"test-tag"}`)},
 }
 
@@ -828,9 +881,11 @@ func mockNestedList(module, container, list, entry string) string {
 	return `{"` + module + `:` + container + `":{"` + list + `":[` + entry + `]}}`
 }
 
-// mockServerConfig selects which data types the mock server answers with HTTP 500.
+// mockServerConfig selects which data types the mock server answers with HTTP 500, and
+// which it answers with HTTP 503 a number of times before answering normally.
 type mockServerConfig struct {
 	fail map[string]bool
+	busy map[string]int
 }
 
 // failing returns a config where exactly the named data types fail.
@@ -842,7 +897,16 @@ func failing(dataTypes ...string) mockServerConfig {
 	return mockServerConfig{fail: fail}
 }
 
+// busyFor returns a config where the named data type answers 503 the given number of
+// times before it succeeds.
+func busyFor(dataType string, times int) mockServerConfig {
+	return mockServerConfig{busy: map[string]int{dataType: times}}
+}
+
 func newMockWNCServer(cfg mockServerConfig) *httptest.Server {
+	var mu sync.Mutex
+	busy := maps.Clone(cfg.busy)
+
 	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/yang-data+json")
 
@@ -856,6 +920,17 @@ func newMockWNCServer(cfg mockServerConfig) *httptest.Server {
 			return
 		}
 
+		mu.Lock()
+		answerBusy := busy[ep.dataType] > 0
+		if answerBusy {
+			busy[ep.dataType]--
+		}
+		mu.Unlock()
+		if answerBusy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(ep.body))
 	}))
@@ -886,15 +961,19 @@ func allModules() config.Collectors {
 	return config.Collectors{
 		AP: config.APCollectorModules{
 			General: true, Radio: true, Traffic: true, Errors: true, Join: true,
-			Spectrum: true, Info: true,
+			Uplink: true, Mesh: true, QoS: true, Restarts: true, Departed: true, Neighbors: true, NeighborsTopN: 5, Spectrum: true, Interferers: true,
+			Info: true,
 		},
 		Client: config.ClientCollectorModules{
-			General: true, Radio: true, Traffic: true, Errors: true, Info: true,
+			General: true, Radio: true, Traffic: true, Errors: true, Devices: true, Onboarding: true,
+			Sessions: true, Info: true,
 		},
 		WLAN: config.WLANCollectorModules{
-			General: true, Traffic: true, Config: true, Info: true,
+			General: true, Traffic: true, Config: true, Applications: true, ApplicationsTopN: 5,
+			ATF: true, Info: true,
 		},
-		Controller: config.ControllerCollectorModules{General: true},
+		Controller: config.ControllerCollectorModules{General: true, AAA: true},
+		RRM:        config.RRMCollectorModules{Channels: true},
 	}
 }
 
@@ -0,0 +1,95 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the per-data-type diagnostics behind the check subcommand.
+package wnc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	wnc "github.com/umatare5/cisco-ios-xe-wireless-go"
+)
+
+// errTokenRejected is returned by CheckAuthorization for a token the controller refused.
+var errTokenRejected = errors.New("controller rejected the access token")
+
+// DataTypeCheck is the outcome of reading one data type once.
+type DataTypeCheck struct {
+	Name string
+	// Err is the error the read failed with, nil on success.
+	Err error
+	// Code is the HTTP status the last request of the read was answered with, and
+	// "error" for a failure with no status, as the code label of wnc_requests_total.
+	Code    string
+	Items   int
+	Latency time.Duration
+	// DefaultsFallback reports that the controller rejected the request for the values
+	// in force and the read settled for a plain one.
+	DefaultsFallback bool
+}
+
+// Checker is implemented by data sources that can diagnose their access to the
+// controller one step at a time, rather than as a refresh that folds every failure
+// into the same series.
+type Checker interface {
+	// CheckAuthorization makes one request and reports whether the controller accepted
+	// the access token, with the outcome code of that request.
+	CheckAuthorization(ctx context.Context) (code string, err error)
+	// CheckDataTypes reads each data type the enabled modules need once, in fetch
+	// order. A failure is not retried, so a data type that only a retry would rescue
+	// shows as failed.
+	CheckDataTypes(ctx context.Context) []DataTypeCheck
+}
+
+// CheckAuthorization implements Checker. It reads the controller's boot time, one leaf
+// any account RESTCONF admits may read, so a 401 or 403 is the token and anything
+// else, a 404 for a leaf this image does not carry included, is past authorization.
+func (s *dataSource) CheckAuthorization(ctx context.Context) (string, error) {
+	ctx, outcome := withRequestOutcome(ctx)
+	_, err := s.client.Core().Do(ctx, http.MethodGet, restconfDataPath+routeControllerBootTime)
+	code := outcome.Code()
+
+	var apiErr *wnc.APIError
+	switch {
+	case err == nil:
+		return code, nil
+	case errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		return code, errTokenRejected
+	case errors.As(err, &apiErr):
+		return code, nil
+	default:
+		return code, err
+	}
+}
+
+// CheckDataTypes implements Checker. The reads go through the same fetchers, so the
+// same request instrumentation and tracing, as a refresh, into a snapshot that is
+// discarded rather than published.
+func (s *dataSource) CheckDataTypes(ctx context.Context) []DataTypeCheck {
+	data := &WNCDataCache{FetchErrors: make(map[string]error)}
+
+	results := make([]DataTypeCheck, 0, len(s.names))
+	for _, f := range s.fetchers() {
+		if !slices.Contains(s.names, f.name) {
+			continue
+		}
+
+		fallbacks := s.defaultsFallbacks.Load()
+		start := time.Now()
+		fetchCtx, outcome := withRequestOutcome(withRequestData(ctx, f.name))
+		items, err := f.fetch(fetchCtx, data)
+
+		results = append(results, DataTypeCheck{
+			Name:             f.name,
+			Err:              err,
+			Code:             outcome.Code(),
+			Items:            items,
+			Latency:          time.Since(start),
+			DefaultsFallback: s.defaultsFallbacks.Load() > fallbacks,
+		})
+	}
+	return results
+}
@@ -0,0 +1,71 @@
+package wnc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCheckDataTypes_ReportsEachDataType pins the table the check subcommand prints: one
+// row per data type the modules read, in fetch order, with the status a failure was
+// answered with and the items a success carried.
+func TestCheckDataTypes_ReportsEachDataType(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(failing(DataAPCAPWAPData))
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	results := ds.CheckDataTypes(context.Background())
+
+	if len(results) != len(ds.names) {
+		t.Fatalf("CheckDataTypes() returned %d results, want one per requested data type (%d)",
+			len(results), len(ds.names))
+	}
+	for i, result := range results {
+		if result.Name != ds.names[i] {
+			t.Errorf("results[%d] = %s, want %s in fetch order", i, result.Name, ds.names[i])
+		}
+		if result.Name == DataAPCAPWAPData {
+			if result.Err == nil || result.Code != "500" {
+				t.Errorf("%s = %v, code %s, want the 500 it was answered with", result.Name, result.Err, result.Code)
+			}
+			continue
+		}
+		if result.Err != nil || result.Code != "200" {
+			t.Errorf("%s = %v, code %s, want a success", result.Name, result.Err, result.Code)
+		}
+	}
+
+	suppressBackgroundRefresh(ds)
+	if _, err := ds.GetCachedData(context.Background()); err == nil {
+		t.Error("GetCachedData() after CheckDataTypes() returned a snapshot, want none published")
+	}
+}
+
+// TestCheckAuthorization tells a refused token from a controller that answered.
+func TestCheckAuthorization(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(mockServerConfig{})
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	if code, err := ds.CheckAuthorization(context.Background()); err != nil || code != "200" {
+		t.Errorf("CheckAuthorization() = %s, %v, want 200 and nil", code, err)
+	}
+
+	refusing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer refusing.Close()
+
+	ds = newTestDataSource(t, refusing.URL, time.Minute)
+	code, err := ds.CheckAuthorization(context.Background())
+	if !errors.Is(err, errTokenRejected) || code != "401" {
+		t.Errorf("CheckAuthorization() = %s, %v, want 401 and %v", code, err, errTokenRejected)
+	}
+}
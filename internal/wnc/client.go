@@ -4,6 +4,7 @@ package wnc
 
 import (
 	"context"
+	"time"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
 )
@@ -16,6 +17,7 @@ type ClientSource interface {
 	GetSISFDBData(ctx context.Context) ([]client.SisfDBMac, error)
 	GetTrafficStats(ctx context.Context) ([]client.TrafficStats, error)
 	GetMobilityHistory(ctx context.Context) ([]client.MmIfClientHistory, error)
+	GetRefreshedAt(ctx context.Context) (time.Time, error)
 }
 
 // clientSource implements ClientSource using SharedDataSource for caching.
@@ -32,7 +34,7 @@ func NewClientSource(sharedDataSource DataSource) ClientSource {
 
 // GetClientData returns client common operational data from WNC via SharedDataSource (cached).
 func (s *clientSource) GetClientData(ctx context.Context) ([]client.CommonOperData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientCommonOperData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientCommonOperData)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +43,7 @@ func (s *clientSource) GetClientData(ctx context.Context) ([]client.CommonOperDa
 
 // GetDeviceData returns device classification info from WNC via SharedDataSource (cached).
 func (s *clientSource) GetDeviceData(ctx context.Context) ([]client.DcInfo, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientDCInfo)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientDCInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +52,7 @@ func (s *clientSource) GetDeviceData(ctx context.Context) ([]client.DcInfo, erro
 
 // GetDot11Data returns 802.11 operational data from WNC via SharedDataSource (cached).
 func (s *clientSource) GetDot11Data(ctx context.Context) ([]client.Dot11OperData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientDot11OperData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientDot11OperData)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +61,7 @@ func (s *clientSource) GetDot11Data(ctx context.Context) ([]client.Dot11OperData
 
 // GetSISFDBData returns SISF database information from WNC via SharedDataSource (cached).
 func (s *clientSource) GetSISFDBData(ctx context.Context) ([]client.SisfDBMac, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientSISFDBMac)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientSISFDBMac)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +70,7 @@ func (s *clientSource) GetSISFDBData(ctx context.Context) ([]client.SisfDBMac, e
 
 // GetTrafficStats returns traffic statistics from WNC via SharedDataSource (cached).
 func (s *clientSource) GetTrafficStats(ctx context.Context) ([]client.TrafficStats, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientTrafficStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientTrafficStats)
 	if err != nil {
 		return nil, err
 	}
@@ -77,9 +79,19 @@ func (s *clientSource) GetTrafficStats(ctx context.Context) ([]client.TrafficSta
 
 // GetMobilityHistory returns mobility manager interface client history from WNC via SharedDataSource (cached).
 func (s *clientSource) GetMobilityHistory(ctx context.Context) ([]client.MmIfClientHistory, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientMMIFHistory)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientMMIFHistory)
 	if err != nil {
 		return nil, err
 	}
 	return data.MmIfClientHistory, nil
 }
+
+// GetRefreshedAt returns when the refresh behind the snapshot the other methods read
+// started, which tells a module that compares snapshots whether this one is new.
+func (s *clientSource) GetRefreshedAt(ctx context.Context) (time.Time, error) {
+	data, err := s.sharedDataSource.GetCachedData(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return data.RefreshedAt, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/umatare5/cisco-ios-xe-wireless-go/service/client"
 )
@@ -546,3 +547,20 @@ func TestClientSource_GetMobilityHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestClientSource_GetRefreshedAt(t *testing.T) {
+	t.Parallel()
+
+	refreshedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	source := NewClientSource(&mockClientDataSource{data: &WNCDataCache{RefreshedAt: refreshedAt}})
+
+	got, err := source.GetRefreshedAt(context.Background())
+	if err != nil || !got.Equal(refreshedAt) {
+		t.Errorf("GetRefreshedAt() = %v, %v, want %v, nil", got, err, refreshedAt)
+	}
+
+	failing := NewClientSource(&mockClientDataSource{err: errors.New("cache refresh failed")})
+	if _, err := failing.GetRefreshedAt(context.Background()); err == nil {
+		t.Error("GetRefreshedAt() error = nil, want the data source's error")
+	}
+}
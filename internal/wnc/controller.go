@@ -11,6 +11,7 @@ type ControllerSource interface {
 	GetBootTime(ctx context.Context) (string, error)
 	GetClientDeleteReasons(ctx context.Context) (map[string]float64, error)
 	GetClientRoamingStats(ctx context.Context) (map[string]float64, error)
+	GetAAAServers(ctx context.Context) ([]AAARadiusServer, error)
 }
 
 // controllerSource implements ControllerSource using SharedDataSource for caching.
@@ -29,7 +30,7 @@ func NewControllerSource(sharedDataSource DataSource) ControllerSource {
 // It is empty when the controller carries no such leaf, which the collector reads as
 // absence rather than as an instant.
 func (s *controllerSource) GetBootTime(ctx context.Context) (string, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataControllerBootTime)
+	data, err := snapshot(ctx, s.sharedDataSource, DataControllerBootTime)
 	if err != nil {
 		return "", err
 	}
@@ -39,7 +40,7 @@ func (s *controllerSource) GetBootTime(ctx context.Context) (string, error) {
 // GetClientRoamingStats returns the controller-wide roam counters from WNC via
 // SharedDataSource (cached).
 func (s *controllerSource) GetClientRoamingStats(ctx context.Context) (map[string]float64, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataClientRoamingStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataClientRoamingStats)
 	if err != nil {
 		return nil, err
 	}
@@ -49,9 +50,19 @@ func (s *controllerSource) GetClientRoamingStats(ctx context.Context) (map[strin
 // GetClientDeleteReasons returns the per-reason client deletion counters from WNC via
 // SharedDataSource (cached).
 func (s *controllerSource) GetClientDeleteReasons(ctx context.Context) (map[string]float64, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataCoClientDelReason)
+	data, err := snapshot(ctx, s.sharedDataSource, DataCoClientDelReason)
 	if err != nil {
 		return nil, err
 	}
 	return data.ClientDeleteReasons, nil
 }
+
+// GetAAAServers returns the per-server RADIUS statistics from WNC via SharedDataSource
+// (cached).
+func (s *controllerSource) GetAAAServers(ctx context.Context) ([]AAARadiusServer, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAAARadiusStats)
+	if err != nil {
+		return nil, err
+	}
+	return data.AAAServers, nil
+}
@@ -146,3 +146,51 @@ func TestControllerSource_GetClientDeleteReasons(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerSource_GetAAAServers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mock    *mockDataSource
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name: "Success with servers",
+			mock: &mockDataSource{
+				data: &WNCDataCache{AAAServers: []AAARadiusServer{
+					{GroupName: "radius-group", ServerIP: "192.168.255.50", State: "up"},
+				}},
+			},
+			wantLen: 1,
+		},
+		{
+			name:    "Empty when the controller carries no server",
+			mock:    &mockDataSource{data: &WNCDataCache{}},
+			wantLen: 0,
+		},
+		{
+			name:    "Error from data source",
+			mock:    &mockDataSource{err: errors.New("cache refresh failed")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			source := NewControllerSource(tt.mock)
+
+			got, err := source.GetAAAServers(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetAAAServers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && len(got) != tt.wantLen {
+				t.Errorf("GetAAAServers() returned %d servers, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
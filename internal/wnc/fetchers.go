@@ -13,33 +13,43 @@ import (
 // A refresh truncated by its deadline drops the tail, so ap_capwap_data comes
 // first: the AP inventory is what every other AP series is labeled from.
 var dataTypeNames = []string{
-	dataAPCAPWAPData,
-	dataAPOperData,
-	dataAPRadioOperData,
-	dataAPNameMACMap,
-	dataAPJoinStats,
-	dataRRMMeasurement,
-	dataWLANCfgEntries,
-	dataWLANPolicies,
-	dataWLANPolicyListEntries,
-	dataWLANClientStats,
-	dataControllerBootTime,
-	dataCoClientDelReason,
-	dataClientRoamingStats,
-	dataClientCommonOperData,
-	dataClientDCInfo,
-	dataClientDot11OperData,
-	dataClientSISFDBMac,
-	dataClientTrafficStats,
-	dataClientMMIFHistory,
-	dataAPRadioOperStats,
-	dataAPRadioResetStats,
-	dataRRMCoverage,
-	dataRRMAPDot11RadarData,
-	dataRRMRadioSlot,
-	dataRRMMainData,
-	dataRRMSpectrumAqWorst,
-	dataRRMSpectrumAqTable,
+	DataAPCAPWAPData,
+	DataAPOperData,
+	DataAPRadioOperData,
+	DataAPNameMACMap,
+	DataAPJoinStats,
+	DataAPCDPCacheData,
+	DataAPLLDPNeigh,
+	DataAPPwrInfo,
+	DataAPMeshOperData,
+	DataAPRadioWMMStats,
+	DataAPRadioATFStats,
+	DataRRMMeasurement,
+	DataRRMAPAutoRFDot11Data,
+	DataWLANCfgEntries,
+	DataWLANPolicies,
+	DataWLANPolicyListEntries,
+	DataWLANClientStats,
+	DataWLANAVCStats,
+	DataControllerBootTime,
+	DataCoClientDelReason,
+	DataClientRoamingStats,
+	DataAAARadiusStats,
+	DataClientCommonOperData,
+	DataClientDCInfo,
+	DataClientDot11OperData,
+	DataClientSISFDBMac,
+	DataClientTrafficStats,
+	DataClientMMIFHistory,
+	DataAPRadioOperStats,
+	DataAPRadioResetStats,
+	DataRRMCoverage,
+	DataRRMAPDot11RadarData,
+	DataRRMRadioSlot,
+	DataRRMMainData,
+	DataRRMSpectrumDevice,
+	DataRRMSpectrumAqWorst,
+	DataRRMSpectrumAqTable,
 }
 
 // boolToInt reports one item for a leaf the controller carries and none for one it
@@ -79,6 +89,12 @@ func requiredDataTypes(modules config.Collectors) []string {
 	return names
 }
 
+// DataTypes returns the data types the enabled modules read, in fetch order, as the
+// data label of the refresh series names them.
+func DataTypes(modules config.Collectors) []string {
+	return requiredDataTypes(modules)
+}
+
 // isDataTypeRequired mirrors the source calls each Collect method makes under its
 // module guards, so it has to be updated alongside them. Three data types are read
 // before any guard, and one is read by three modules across two collectors, so the
@@ -90,50 +106,94 @@ func isDataTypeRequired(name string, modules config.Collectors) bool {
 	anyAP := anyOf(modules.AP.General, modules.AP.Radio,
 		modules.AP.Traffic, modules.AP.Errors, modules.AP.Info, modules.AP.Spectrum)
 	anyClient := anyOf(modules.Client.General, modules.Client.Radio,
-		modules.Client.Traffic, modules.Client.Errors, modules.Client.Info)
+		modules.Client.Traffic, modules.Client.Errors, modules.Client.Devices, modules.Client.Info,
+		modules.Client.Onboarding, modules.Client.Sessions)
 	anyWLAN := anyOf(modules.WLAN.General, modules.WLAN.Traffic,
 		modules.WLAN.Config, modules.WLAN.Info)
 
 	switch name {
-	case dataAPCAPWAPData, dataAPRadioOperData:
-		return anyAP
-	case dataAPOperData:
+	case DataAPCAPWAPData:
+		// The restarts module compares the boot and join time this list carries, and
+		// the departed module records which APs it lists.
+		return anyOf(anyAP, modules.AP.Restarts, modules.AP.Departed)
+	case DataAPRadioOperData:
+		// The RRM channel summary groups the radios by the channel they operate on.
+		return anyOf(anyAP, modules.RRM.Channels)
+	case DataAPOperData:
 		return modules.AP.General
-	case dataAPNameMACMap, dataRRMMeasurement:
-		return modules.AP.Radio
-	case dataAPJoinStats:
+	case DataAPNameMACMap:
+		// The neighbors module names the radios it publishes from the same map the
+		// radio module and the RRM channel summary count clients with.
+		return anyOf(modules.AP.Radio, modules.AP.Neighbors, modules.RRM.Channels)
+	case DataRRMMeasurement:
+		return anyOf(modules.AP.Radio, modules.RRM.Channels)
+	case DataRRMAPAutoRFDot11Data:
+		return modules.AP.Neighbors
+	case DataAPJoinStats:
 		// The join module is keyed by the statistics list itself, which keeps a record
-		// for an AP the inventory has dropped, so it reads no other AP data type.
-		return modules.AP.Join
-	case dataAPRadioOperStats:
+		// for an AP the inventory has dropped, so it reads no other AP data type. The
+		// restarts module reads it for the reason leaves alone.
+		return anyOf(modules.AP.Join, modules.AP.Restarts)
+	case DataAPCDPCacheData, DataAPLLDPNeigh, DataAPPwrInfo:
+		// The uplink module is keyed by the neighbor lists themselves, for the reason
+		// the join module is: an AP that has just dropped is the one whose port matters.
+		return modules.AP.Uplink
+	case DataAPMeshOperData:
+		// The mesh list names each AP's parent itself, so the mesh tree needs no other
+		// read to be drawn.
+		return modules.AP.Mesh
+	case DataAPRadioWMMStats:
+		// The queue statistics carry the AP and the radio slot themselves, so the qos
+		// module is keyed by them and reads no inventory.
+		return modules.AP.QoS
+	case DataAPRadioATFStats:
+		// The ATF statistics carry the WLAN identifier, the key of every wnc_wlan_*
+		// series, so the atf module needs no configuration entry to label them.
+		return modules.WLAN.ATF
+	case DataAPRadioOperStats:
 		return anyOf(modules.AP.Traffic, modules.AP.Errors)
-	case dataAPRadioResetStats, dataRRMCoverage, dataRRMAPDot11RadarData:
+	case DataAPRadioResetStats, DataRRMCoverage, DataRRMAPDot11RadarData:
 		return modules.AP.Errors
-	case dataRRMRadioSlot, dataRRMMainData:
+	case DataRRMRadioSlot, DataRRMMainData:
 		return modules.AP.Radio
-	case dataRRMSpectrumAqWorst, dataRRMSpectrumAqTable:
+	case DataRRMSpectrumAqWorst, DataRRMSpectrumAqTable:
 		return modules.AP.Spectrum
-	case dataControllerBootTime, dataCoClientDelReason, dataClientRoamingStats:
+	case DataRRMSpectrumDevice:
+		// The device table carries the radio and the channel each device was detected
+		// on, so the interferers module reads nothing else.
+		return modules.AP.Interferers
+	case DataControllerBootTime, DataCoClientDelReason, DataClientRoamingStats:
 		return modules.Controller.General
-	case dataWLANCfgEntries:
+	case DataAAARadiusStats:
+		return modules.Controller.AAA
+	case DataWLANCfgEntries:
 		return anyWLAN
-	case dataWLANPolicies, dataWLANPolicyListEntries:
+	case DataWLANPolicies, DataWLANPolicyListEntries:
 		return modules.WLAN.Config
-	case dataWLANClientStats:
+	case DataWLANClientStats:
 		return modules.WLAN.Traffic
-	case dataClientCommonOperData:
+	case DataWLANAVCStats:
+		// The statistics are keyed by the WLAN identifier, the key of every wnc_wlan_*
+		// series, so the applications module needs no configuration entry to label them.
+		return modules.WLAN.Applications
+	case DataClientCommonOperData:
 		// The per-radio and per-WLAN client counts read it through their own
 		// collectors, so a client module is not the only reason to fetch it.
-		return anyOf(anyClient, modules.AP.Radio, modules.WLAN.Traffic)
-	case dataClientDCInfo, dataClientSISFDBMac:
+		return anyOf(anyClient, modules.AP.Radio, modules.RRM.Channels, modules.WLAN.Traffic)
+	case DataClientDCInfo:
+		return anyOf(modules.Client.Devices, modules.Client.Info)
+	case DataClientSISFDBMac:
 		return modules.Client.Info
-	case dataClientDot11OperData:
-		return anyOf(modules.Client.General, modules.Client.Radio, modules.Client.Info)
-	case dataClientTrafficStats:
+	case DataClientDot11OperData:
+		// The onboarding and sessions modules measure from the association time this
+		// list carries.
+		return anyOf(modules.Client.General, modules.Client.Radio, modules.Client.Info,
+			modules.Client.Onboarding, modules.Client.Sessions)
+	case DataClientTrafficStats:
 		return anyOf(modules.Client.General, modules.Client.Radio,
 			modules.Client.Traffic, modules.Client.Errors)
-	case dataClientMMIFHistory:
-		return modules.Client.General
+	case DataClientMMIFHistory:
+		return anyOf(modules.Client.General, modules.Client.Onboarding)
 	default:
 		return true
 	}
@@ -142,7 +202,7 @@ func isDataTypeRequired(name string, modules config.Collectors) bool {
 // fetchers returns the data fetchers in the order of dataTypeNames.
 func (s *dataSource) fetchers() []dataFetcher {
 	return []dataFetcher{
-		{dataAPCAPWAPData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPCAPWAPData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListCAPWAPData(ctx)
 			if err != nil {
 				return 0, err
@@ -150,7 +210,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.CAPWAPData = data.CAPWAPData
 			return len(c.CAPWAPData), nil
 		}},
-		{dataAPOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListApOperData(ctx)
 			if err != nil {
 				return 0, err
@@ -158,7 +218,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.ApOperData = data.OperData
 			return len(c.ApOperData), nil
 		}},
-		{dataAPRadioOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPRadioOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListRadioData(ctx)
 			if err != nil {
 				return 0, err
@@ -166,7 +226,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RadioOperData = data.RadioOperData
 			return len(c.RadioOperData), nil
 		}},
-		{dataAPNameMACMap, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPNameMACMap, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListNameMACMaps(ctx)
 			if err != nil {
 				return 0, err
@@ -174,7 +234,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.NameMACMaps = data.ApNameMACMap
 			return len(c.NameMACMaps), nil
 		}},
-		{dataAPJoinStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPJoinStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListAPJoinStats(ctx)
 			if err != nil {
 				return 0, err
@@ -182,7 +242,55 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.JoinStats = data.ApJoinStats
 			return len(c.JoinStats), nil
 		}},
-		{dataRRMMeasurement, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPCDPCacheData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			neighbors, _, err := rawValue[[]CDPNeighbor](ctx, s.client.Core(), routeAPCDPCacheData)
+			if err != nil {
+				return 0, err
+			}
+			c.CDPNeighbors = neighbors
+			return len(c.CDPNeighbors), nil
+		}},
+		{DataAPLLDPNeigh, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			neighbors, _, err := rawValue[[]LLDPNeighbor](ctx, s.client.Core(), routeAPLLDPNeigh)
+			if err != nil {
+				return 0, err
+			}
+			c.LLDPNeighbors = neighbors
+			return len(c.LLDPNeighbors), nil
+		}},
+		{DataAPPwrInfo, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			power, _, err := rawValue[[]APPowerInfo](ctx, s.client.Core(), routeAPPwrInfo)
+			if err != nil {
+				return 0, err
+			}
+			c.APPowerInfo = power
+			return len(c.APPowerInfo), nil
+		}},
+		{DataAPMeshOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			meshAPs, _, err := rawValue[[]MeshAPOperData](ctx, s.client.Core(), routeAPMeshOperData)
+			if err != nil {
+				return 0, err
+			}
+			c.MeshAPs = meshAPs
+			return len(c.MeshAPs), nil
+		}},
+		{DataAPRadioWMMStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			stats, _, err := rawValue[[]RadioWMMStats](ctx, s.client.Core(), routeAPRadioWMMStats)
+			if err != nil {
+				return 0, err
+			}
+			c.RadioWMMStats = stats
+			return len(c.RadioWMMStats), nil
+		}},
+		{DataAPRadioATFStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			stats, _, err := rawValue[[]RadioATFStats](ctx, s.client.Core(), routeAPRadioATFStats)
+			if err != nil {
+				return 0, err
+			}
+			c.RadioATFStats = stats
+			return len(c.RadioATFStats), nil
+		}},
+		{DataRRMMeasurement, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListRRMMeasurement(ctx)
 			if err != nil {
 				return 0, err
@@ -190,7 +298,15 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RRMMeasurements = data.RRMMeasurement
 			return len(c.RRMMeasurements), nil
 		}},
-		{dataWLANCfgEntries, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMAPAutoRFDot11Data, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			neighbors, _, err := rawValue[[]RRMNeighborData](ctx, s.client.Core(), routeRRMAPAutoRFDot11Data)
+			if err != nil {
+				return 0, err
+			}
+			c.RRMNeighbors = neighbors
+			return len(c.RRMNeighbors), nil
+		}},
+		{DataWLANCfgEntries, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := readEffective(ctx, &s.defaultsFallbacks, s.client.WLAN().ListWlanCfgEntries)
 			if err != nil {
 				return 0, err
@@ -200,7 +316,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			}
 			return len(c.WLANConfigEntries), nil
 		}},
-		{dataWLANPolicies, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataWLANPolicies, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := readEffective(ctx, &s.defaultsFallbacks, s.client.WLAN().ListWlanPolicies)
 			if err != nil {
 				return 0, err
@@ -210,7 +326,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			}
 			return len(c.WLANPolicies), nil
 		}},
-		{dataWLANPolicyListEntries, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataWLANPolicyListEntries, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.WLAN().ListCfgPolicyListEntries(ctx)
 			if err != nil {
 				return 0, err
@@ -220,7 +336,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			}
 			return len(c.WLANPolicyListEntries), nil
 		}},
-		{dataWLANClientStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataWLANClientStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListWLANClientStats(ctx)
 			if err != nil {
 				return 0, err
@@ -228,7 +344,15 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.WLANClientStats = data.WlanClientStats
 			return len(c.WLANClientStats), nil
 		}},
-		{dataControllerBootTime, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataWLANAVCStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			stats, _, err := rawValue[[]WLANAppStats](ctx, s.client.Core(), routeWLANAVCStats)
+			if err != nil {
+				return 0, err
+			}
+			c.WLANAppStats = stats
+			return len(c.WLANAppStats), nil
+		}},
+		{DataControllerBootTime, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			bootTime, present, err := rawValue[string](ctx, s.client.Core(), routeControllerBootTime)
 			if err != nil {
 				return 0, err
@@ -236,7 +360,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.ControllerBootTime = bootTime
 			return boolToInt(present), nil
 		}},
-		{dataCoClientDelReason, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataCoClientDelReason, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			leaves, present, err := rawValue[map[string]json.RawMessage](
 				ctx, s.client.Core(), routeCoClientDelReason,
 			)
@@ -246,10 +370,10 @@ func (s *dataSource) fetchers() []dataFetcher {
 			if !present {
 				return 0, nil
 			}
-			c.ClientDeleteReasons = numericLeaves(leaves, dataCoClientDelReason)
+			c.ClientDeleteReasons = numericLeaves(leaves, DataCoClientDelReason)
 			return len(c.ClientDeleteReasons), nil
 		}},
-		{dataClientRoamingStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientRoamingStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			leaves, present, err := rawValue[map[string]json.RawMessage](
 				ctx, s.client.Core(), routeClientRoamingStats,
 			)
@@ -259,10 +383,18 @@ func (s *dataSource) fetchers() []dataFetcher {
 			if !present {
 				return 0, nil
 			}
-			c.ClientRoamingStats = numericLeaves(leaves, dataClientRoamingStats)
+			c.ClientRoamingStats = numericLeaves(leaves, DataClientRoamingStats)
 			return len(c.ClientRoamingStats), nil
 		}},
-		{dataClientCommonOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAAARadiusStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			servers, _, err := rawValue[[]AAARadiusServer](ctx, s.client.Core(), routeAAARadiusStats)
+			if err != nil {
+				return 0, err
+			}
+			c.AAAServers = servers
+			return len(c.AAAServers), nil
+		}},
+		{DataClientCommonOperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListCommonInfo(ctx)
 			if err != nil {
 				return 0, err
@@ -270,7 +402,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.CommonOperData = data.CommonOperData
 			return len(c.CommonOperData), nil
 		}},
-		{dataClientDCInfo, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientDCInfo, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListDCInfo(ctx)
 			if err != nil {
 				return 0, err
@@ -278,7 +410,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.DCInfo = data.DcInfo
 			return len(c.DCInfo), nil
 		}},
-		{dataClientDot11OperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientDot11OperData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListDot11Info(ctx)
 			if err != nil {
 				return 0, err
@@ -286,7 +418,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.Dot11OperData = data.Dot11OperData
 			return len(c.Dot11OperData), nil
 		}},
-		{dataClientSISFDBMac, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientSISFDBMac, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListSISFDB(ctx)
 			if err != nil {
 				return 0, err
@@ -294,7 +426,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.SisfDBMac = data.SisfDBMac
 			return len(c.SisfDBMac), nil
 		}},
-		{dataClientTrafficStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientTrafficStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListTrafficStats(ctx)
 			if err != nil {
 				return 0, err
@@ -302,7 +434,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.TrafficStats = data.TrafficStats
 			return len(c.TrafficStats), nil
 		}},
-		{dataClientMMIFHistory, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataClientMMIFHistory, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.Client().ListMMIFClientHistory(ctx)
 			if err != nil {
 				return 0, err
@@ -310,7 +442,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.MmIfClientHistory = data.MmIfClientHistory
 			return len(c.MmIfClientHistory), nil
 		}},
-		{dataAPRadioOperStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPRadioOperStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListRadioOperStats(ctx)
 			if err != nil {
 				return 0, err
@@ -318,7 +450,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RadioOperStats = data.RadioOperStats
 			return len(c.RadioOperStats), nil
 		}},
-		{dataAPRadioResetStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataAPRadioResetStats, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.AP().ListRadioResetStats(ctx)
 			if err != nil {
 				return 0, err
@@ -326,7 +458,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RadioResetStats = data.RadioResetStats
 			return len(c.RadioResetStats), nil
 		}},
-		{dataRRMCoverage, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMCoverage, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListRRMCoverage(ctx)
 			if err != nil {
 				return 0, err
@@ -334,7 +466,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RRMCoverage = data.RRMCoverage
 			return len(c.RRMCoverage), nil
 		}},
-		{dataRRMAPDot11RadarData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMAPDot11RadarData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListApDot11RadarData(ctx)
 			if err != nil {
 				return 0, err
@@ -342,7 +474,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.ApDot11RadarData = data.ApDot11RadarData
 			return len(c.ApDot11RadarData), nil
 		}},
-		{dataRRMRadioSlot, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMRadioSlot, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListRadioSlot(ctx)
 			if err != nil {
 				return 0, err
@@ -350,7 +482,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RadioSlots = data.RadioSlot
 			return len(c.RadioSlots), nil
 		}},
-		{dataRRMMainData, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMMainData, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListMainData(ctx)
 			if err != nil {
 				return 0, err
@@ -358,7 +490,15 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.RRMMainData = data.MainData
 			return len(c.RRMMainData), nil
 		}},
-		{dataRRMSpectrumAqWorst, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMSpectrumDevice, func(ctx context.Context, c *WNCDataCache) (int, error) {
+			devices, _, err := rawValue[[]SpectrumDevice](ctx, s.client.Core(), routeRRMSpectrumDeviceTable)
+			if err != nil {
+				return 0, err
+			}
+			c.SpectrumDevices = devices
+			return len(c.SpectrumDevices), nil
+		}},
+		{DataRRMSpectrumAqWorst, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListSpectrumAqWorstTable(ctx)
 			if err != nil {
 				return 0, err
@@ -366,7 +506,7 @@ func (s *dataSource) fetchers() []dataFetcher {
 			c.SpectrumAqWorst = data.SpectrumAqWorstTable
 			return len(c.SpectrumAqWorst), nil
 		}},
-		{dataRRMSpectrumAqTable, func(ctx context.Context, c *WNCDataCache) (int, error) {
+		{DataRRMSpectrumAqTable, func(ctx context.Context, c *WNCDataCache) (int, error) {
 			data, err := s.client.RRM().ListSpectrumAqTable(ctx)
 			if err != nil {
 				return 0, err
@@ -134,7 +134,7 @@ func TestFetchers_FallBackToPlainReadOnRejection(t *testing.T) {
 		t.Fatalf("fetchAllData() error = %v, want nil", err)
 	}
 
-	for _, id := range []string{dataWLANCfgEntries, dataWLANPolicies} {
+	for _, id := range []string{DataWLANCfgEntries, DataWLANPolicies} {
 		if fetchErr := data.FetchErrors[id]; fetchErr != nil {
 			t.Errorf("FetchErrors[%s] = %v, want nil after the plain re-read", id, fetchErr)
 		}
@@ -180,7 +180,7 @@ func TestFetchers_NoFallBackOnOtherStatus(t *testing.T) {
 		t.Fatalf("fetchAllData() error = %v, want nil: one failed data type is not a failed refresh", err)
 	}
 
-	if data.FetchErrors[dataWLANCfgEntries] == nil {
+	if data.FetchErrors[DataWLANCfgEntries] == nil {
 		t.Error("FetchErrors[wlan_cfg_entries] is nil, want the fetch error recorded")
 	}
 	if queries := rec.get("wlan-cfg-entries"); len(queries) != 1 {
@@ -207,15 +207,15 @@ func TestRequiredDataTypes_MatchesWhatTheModulesRead(t *testing.T) {
 		{
 			"AP general reads the inventory, the radios and the AP oper data",
 			config.Collectors{AP: config.APCollectorModules{General: true}},
-			[]string{dataAPCAPWAPData, dataAPOperData, dataAPRadioOperData},
+			[]string{DataAPCAPWAPData, DataAPOperData, DataAPRadioOperData},
 		},
 		{
 			"AP radio reads client data for the per-radio client count",
 			config.Collectors{AP: config.APCollectorModules{Radio: true}},
 			[]string{
-				dataAPCAPWAPData, dataAPRadioOperData, dataAPNameMACMap,
-				dataRRMMeasurement, dataClientCommonOperData, dataRRMRadioSlot,
-				dataRRMMainData,
+				DataAPCAPWAPData, DataAPRadioOperData, DataAPNameMACMap,
+				DataRRMMeasurement, DataClientCommonOperData, DataRRMRadioSlot,
+				DataRRMMainData,
 			},
 		},
 		{
@@ -223,57 +223,134 @@ func TestRequiredDataTypes_MatchesWhatTheModulesRead(t *testing.T) {
 			// radio through the radio list, which this module has to pay for as well.
 			"AP spectrum reads the radio list its band and channel come from",
 			config.Collectors{AP: config.APCollectorModules{Spectrum: true}},
-			[]string{dataAPCAPWAPData, dataAPRadioOperData, dataRRMSpectrumAqWorst, dataRRMSpectrumAqTable},
+			[]string{DataAPCAPWAPData, DataAPRadioOperData, DataRRMSpectrumAqWorst, DataRRMSpectrumAqTable},
+		},
+		{
+			// An AP that has just left CAPWAP is missing from the inventory, so naming
+			// its switch port cannot depend on reading it.
+			"AP uplink reads the two neighbor lists and the power list, not the inventory",
+			config.Collectors{AP: config.APCollectorModules{Uplink: true}},
+			[]string{DataAPCDPCacheData, DataAPLLDPNeigh, DataAPPwrInfo},
+		},
+		{
+			// The neighbor list carries the radio MACs it keys on, so naming them takes the
+			// name map and nothing from the inventory.
+			"AP neighbors reads the name map and the RRM neighbor list",
+			config.Collectors{AP: config.APCollectorModules{Neighbors: true, NeighborsTopN: 5}},
+			[]string{DataAPNameMACMap, DataRRMAPAutoRFDot11Data},
+		},
+		{
+			// The summary groups radios by channel, so it has no use for the inventory.
+			"RRM channels reads the radios, their measurements and the client count inputs",
+			config.Collectors{RRM: config.RRMCollectorModules{Channels: true}},
+			[]string{
+				DataAPRadioOperData, DataAPNameMACMap, DataRRMMeasurement, DataClientCommonOperData,
+			},
+		},
+		{
+			"AP interferers reads the CleanAir device table alone",
+			config.Collectors{AP: config.APCollectorModules{Interferers: true}},
+			[]string{DataRRMSpectrumDevice},
 		},
 		{
 			"AP info reads only the two the AP collector fetches unconditionally",
 			config.Collectors{AP: config.APCollectorModules{Info: true}},
-			[]string{dataAPCAPWAPData, dataAPRadioOperData},
+			[]string{DataAPCAPWAPData, DataAPRadioOperData},
 		},
 		{
 			"AP errors reads the reset stats and both RRM error routes",
 			config.Collectors{AP: config.APCollectorModules{Errors: true}},
 			[]string{
-				dataAPCAPWAPData, dataAPRadioOperData, dataAPRadioOperStats,
-				dataAPRadioResetStats, dataRRMCoverage, dataRRMAPDot11RadarData,
+				DataAPCAPWAPData, DataAPRadioOperData, DataAPRadioOperStats,
+				DataAPRadioResetStats, DataRRMCoverage, DataRRMAPDot11RadarData,
 			},
 		},
 		{
 			"WLAN config reads all three configuration routes",
 			config.Collectors{WLAN: config.WLANCollectorModules{Config: true}},
-			[]string{dataWLANCfgEntries, dataWLANPolicies, dataWLANPolicyListEntries},
+			[]string{DataWLANCfgEntries, DataWLANPolicies, DataWLANPolicyListEntries},
 		},
 		{
 			"WLAN traffic reads client data and the WLAN statistics, not the policy routes",
 			config.Collectors{WLAN: config.WLANCollectorModules{Traffic: true}},
-			[]string{dataWLANCfgEntries, dataWLANClientStats, dataClientCommonOperData},
+			[]string{DataWLANCfgEntries, DataWLANClientStats, DataClientCommonOperData},
 		},
 		{
 			"WLAN general reads one data type of the whole set",
 			config.Collectors{WLAN: config.WLANCollectorModules{General: true}},
-			[]string{dataWLANCfgEntries},
+			[]string{DataWLANCfgEntries},
 		},
 		{
 			"client traffic reads two data types",
 			config.Collectors{Client: config.ClientCollectorModules{Traffic: true}},
-			[]string{dataClientCommonOperData, dataClientTrafficStats},
+			[]string{DataClientCommonOperData, DataClientTrafficStats},
 		},
 		{
 			"client info reads the two identity routes and the dot11 data",
 			config.Collectors{Client: config.ClientCollectorModules{Info: true}},
 			[]string{
-				dataClientCommonOperData, dataClientDCInfo,
-				dataClientDot11OperData, dataClientSISFDBMac,
+				DataClientCommonOperData, DataClientDCInfo,
+				DataClientDot11OperData, DataClientSISFDBMac,
 			},
 		},
+		{
+			"client devices reads the client list and the classification",
+			config.Collectors{Client: config.ClientCollectorModules{Devices: true}},
+			[]string{DataClientCommonOperData, DataClientDCInfo},
+		},
 		{
 			"client general also reads the mobility history",
 			config.Collectors{Client: config.ClientCollectorModules{General: true}},
 			[]string{
-				dataClientCommonOperData, dataClientDot11OperData,
-				dataClientTrafficStats, dataClientMMIFHistory,
+				DataClientCommonOperData, DataClientDot11OperData,
+				DataClientTrafficStats, DataClientMMIFHistory,
 			},
 		},
+		{
+			"client onboarding reads the client list, the dot11 data and the mobility history",
+			config.Collectors{Client: config.ClientCollectorModules{Onboarding: true}},
+			[]string{DataClientCommonOperData, DataClientDot11OperData, DataClientMMIFHistory},
+		},
+		{
+			"client sessions reads the client list and the dot11 data",
+			config.Collectors{Client: config.ClientCollectorModules{Sessions: true}},
+			[]string{DataClientCommonOperData, DataClientDot11OperData},
+		},
+		{
+			"ap restarts reads the inventory and the join statistics",
+			config.Collectors{AP: config.APCollectorModules{Restarts: true}},
+			[]string{DataAPCAPWAPData, DataAPJoinStats},
+		},
+		{
+			"ap departed reads the inventory alone",
+			config.Collectors{AP: config.APCollectorModules{Departed: true}},
+			[]string{DataAPCAPWAPData},
+		},
+		{
+			"ap mesh reads the mesh list alone",
+			config.Collectors{AP: config.APCollectorModules{Mesh: true}},
+			[]string{DataAPMeshOperData},
+		},
+		{
+			"controller aaa reads the RADIUS statistics alone",
+			config.Collectors{Controller: config.ControllerCollectorModules{AAA: true}},
+			[]string{DataAAARadiusStats},
+		},
+		{
+			"ap qos reads the WMM statistics alone",
+			config.Collectors{AP: config.APCollectorModules{QoS: true}},
+			[]string{DataAPRadioWMMStats},
+		},
+		{
+			"wlan atf reads the ATF statistics alone",
+			config.Collectors{WLAN: config.WLANCollectorModules{ATF: true}},
+			[]string{DataAPRadioATFStats},
+		},
+		{
+			"wlan applications reads the AVC statistics alone",
+			config.Collectors{WLAN: config.WLANCollectorModules{Applications: true, ApplicationsTopN: 5}},
+			[]string{DataWLANAVCStats},
+		},
 		{"every module reads every data type, in fetch order", allModules(), dataTypeNames},
 		{"no module reads nothing", config.Collectors{}, []string{}},
 	}
@@ -328,7 +405,7 @@ func TestFetchers_SkipWhatNoEnabledModuleReads(t *testing.T) {
 		t.Fatalf("fetchAllData() error = %v, want nil", err)
 	}
 
-	required := []string{dataClientCommonOperData, dataClientTrafficStats}
+	required := []string{DataClientCommonOperData, DataClientTrafficStats}
 	for segment, endpoint := range mockEndpoints {
 		want := 0
 		if slices.Contains(required, endpoint.dataType) {
@@ -381,7 +458,7 @@ func TestFetchers_SkipWhatNoEnabledModuleReads(t *testing.T) {
 func TestFetchers_DownWhenEveryRequiredDataTypeFails(t *testing.T) {
 	t.Parallel()
 
-	server := newMockWNCServer(failing(dataClientCommonOperData, dataClientTrafficStats))
+	server := newMockWNCServer(failing(DataClientCommonOperData, DataClientTrafficStats))
 	defer server.Close()
 
 	ds, ok := NewDataSource(config.WNC{
@@ -443,9 +520,9 @@ func TestFetchers_SkipBeforeTheDeadlineCheck(t *testing.T) {
 	if len(stats.Errors) != 1 {
 		t.Errorf("Stats().Errors covers %d data types, want 1", len(stats.Errors))
 	}
-	if count, present := stats.Errors[dataWLANCfgEntries]; !present || count != 1 {
+	if count, present := stats.Errors[DataWLANCfgEntries]; !present || count != 1 {
 		t.Errorf("Stats().Errors[%s] = %d (present %v), want 1",
-			dataWLANCfgEntries, count, present)
+			DataWLANCfgEntries, count, present)
 	}
 }
 
@@ -476,8 +553,8 @@ func TestDataSource_PanicCountsOnlyTheRequiredDataTypes(t *testing.T) {
 	if len(stats.Errors) != 1 {
 		t.Errorf("Stats().Errors covers %d data types after a panic, want 1", len(stats.Errors))
 	}
-	if count := stats.Errors[dataWLANCfgEntries]; count != 1 {
-		t.Errorf("Stats().Errors[%s] = %d, want 1", dataWLANCfgEntries, count)
+	if count := stats.Errors[DataWLANCfgEntries]; count != 1 {
+		t.Errorf("Stats().Errors[%s] = %d, want 1", DataWLANCfgEntries, count)
 	}
 	if stats.Up {
 		t.Error("Stats().Up = true after a panicked refresh, want false")
@@ -523,11 +600,11 @@ func TestSnapshot_RejectsADataTypeNoModuleDeclared(t *testing.T) {
 
 	src := stubSource{data: data}
 
-	if _, err := snapshot(context.Background(), src, dataAPCAPWAPData); !errors.Is(err, errDataTypeNotRequested) {
+	if _, err := snapshot(context.Background(), src, DataAPCAPWAPData); !errors.Is(err, errDataTypeNotRequested) {
 		t.Errorf("snapshot() error = %v, want errDataTypeNotRequested", err)
 	}
 
-	if _, err := snapshot(context.Background(), src, dataWLANCfgEntries); err != nil {
+	if _, err := snapshot(context.Background(), src, DataWLANCfgEntries); err != nil {
 		t.Errorf("snapshot() error = %v, want nil for a data type a module declared", err)
 	}
 }
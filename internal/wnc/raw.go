@@ -22,6 +22,24 @@ const (
 		"/client-stats/co-client-del-reason"
 	routeClientRoamingStats = "Cisco-IOS-XE-wireless-client-global-oper:client-global-oper-data" +
 		"/client-dot11-stats/client-roaming-stats"
+	routeAPCDPCacheData = "Cisco-IOS-XE-wireless-access-point-oper:access-point-oper-data" +
+		"/cdp-cache-data"
+	routeAPLLDPNeigh = "Cisco-IOS-XE-wireless-access-point-oper:access-point-oper-data" +
+		"/lldp-neigh"
+	routeAPPwrInfo = "Cisco-IOS-XE-wireless-access-point-oper:access-point-oper-data" +
+		"/ap-pwr-info"
+	routeAPMeshOperData = "Cisco-IOS-XE-wireless-mesh-oper:mesh-oper-data" +
+		"/mesh-ap-oper-data"
+	routeAPRadioWMMStats = "Cisco-IOS-XE-wireless-access-point-oper:access-point-oper-data" +
+		"/radio-wmm-stats"
+	routeAPRadioATFStats = "Cisco-IOS-XE-wireless-access-point-oper:access-point-oper-data" +
+		"/radio-atf-stats"
+	routeRRMAPAutoRFDot11Data = "Cisco-IOS-XE-wireless-rrm-oper:rrm-oper-data" +
+		"/ap-auto-rf-dot11-data"
+	routeRRMSpectrumDeviceTable = "Cisco-IOS-XE-wireless-rrm-oper:rrm-oper-data" +
+		"/spectrum-device-table"
+	routeAAARadiusStats = "Cisco-IOS-XE-aaa-oper:aaa-data/aaa-radius-stats"
+	routeWLANAVCStats   = "Cisco-IOS-XE-wireless-avc-oper:avc-oper-data/avc-wlan-stats"
 )
 
 // restconfDataPath prefixes every path above, matching what the SDK builds for its
@@ -48,7 +66,14 @@ type rawGetter interface {
 func rawValue[V any](
 	ctx context.Context, getter rawGetter, requestPath string,
 ) (value V, present bool, err error) {
-	body, err := getter.Do(ctx, http.MethodGet, restconfDataPath+requestPath)
+	ctx, span := startSpan(ctx, spanRawRead, attrPath.String(requestPath))
+	var body []byte
+	defer func() {
+		span.SetAttributes(attrSize.Int(len(body)), attrPresent.Bool(present))
+		endSpan(span, err)
+	}()
+
+	body, err = getter.Do(ctx, http.MethodGet, restconfDataPath+requestPath)
 	if err != nil {
 		return value, false, err
 	}
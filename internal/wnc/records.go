@@ -0,0 +1,198 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the records of the lists the SDK has no type for.
+package wnc
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// The records below decode one entry of a list read through rawValue. The envelope
+// around the list is still checked by soleValue, so a wrong route fails loudly; the
+// tags only name leaves inside an entry, and a leaf the controller omits reads as its
+// zero value, which every consumer treats as unreported rather than as a reading.
+
+// Number is a numeric leaf this controller writes as a JSON number in one release and as
+// a JSON string in another: RFC 7951 encodes the 64-bit YANG integers and decimal64 as
+// strings, and the controller applies that to some narrower leaves too. It decodes both,
+// and decodes any other string, such as "auto", or any other JSON value as empty rather
+// than failing the whole list, so a consumer reads an unreported leaf through Float64
+// failing.
+type Number string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		// Not a string: a number literal is taken as it is written, anything else
+		// (null, a boolean, a container) is left unreported.
+		text = string(data)
+	}
+
+	*n = ""
+	if json.Valid([]byte(text)) {
+		if _, err := strconv.ParseFloat(text, 64); err == nil {
+			*n = Number(text)
+		}
+	}
+	return nil
+}
+
+// Float64 returns the value of the leaf, failing for a leaf that is unreported.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// CDPNeighbor is one entry of the CDP cache the controller keeps per AP, which names
+// the switch port the AP's Ethernet uplink is cabled to.
+type CDPNeighbor struct {
+	WtpMAC    string `json:"mac-addr"`
+	DeviceID  string `json:"cdp-cache-device-id"`
+	Platform  string `json:"cdp-cache-platform"`
+	PortID    string `json:"cdp-cache-port-id"`
+	LocalPort string `json:"cdp-cache-local-port"`
+	Duplex    string `json:"cdp-cache-duplex"`
+	// InterfaceSpeed is a speed the controller may spell "auto", which fails the leaf
+	// rather than the whole list.
+	InterfaceSpeed Number `json:"cdp-cache-interface-speed"`
+}
+
+// LLDPNeighbor is one entry of the LLDP neighbor list the controller keeps per AP. It
+// carries no platform and no link settings, only the neighbor's identity and port.
+type LLDPNeighbor struct {
+	WtpMAC      string `json:"wtp-mac"`
+	NeighborMAC string `json:"neighbor-mac"`
+	PortID      string `json:"port-id"`
+	SystemName  string `json:"system-name"`
+}
+
+// APPowerInfo is one entry of the AP power list, which reports whether the AP draws
+// the power its radios need from its uplink.
+type APPowerInfo struct {
+	WtpMAC string `json:"wtp-mac"`
+	Status string `json:"status"`
+}
+
+// MeshAPOperData is one entry of the mesh AP list, which the controller keeps for every
+// AP in a mesh role, root APs included. The parent and the link readings describe the
+// backhaul toward the parent, so a root AP, whose uplink is wired, carries none of them.
+type MeshAPOperData struct {
+	WtpMAC    string `json:"wtp-mac"`
+	Role      string `json:"ap-role"`
+	ParentMAC string `json:"parent-mac"`
+	HopCount  int    `json:"hop-count"`
+	// The backhaul radio and the channel it operates on.
+	BackhaulSlotID  int `json:"bhaul-slot-id"`
+	BackhaulChannel int `json:"bhaul-channel"`
+	// LinkSNR is the SNR in dB at which the AP hears its parent.
+	LinkSNR int `json:"link-snr"`
+	// BackhaulDataRate is the rate of the link to the parent in Mbps.
+	BackhaulDataRate Number `json:"bhaul-data-rate"`
+}
+
+// RadioWMMStats is one entry of the WMM queue statistics the controller keeps per AP
+// radio and access category, with the access category spelled as the controller spells
+// it.
+type RadioWMMStats struct {
+	WtpMAC         string `json:"wtp-mac"`
+	RadioSlotID    int    `json:"radio-slot-id"`
+	AccessCategory string `json:"access-category"`
+
+	TxFrames   Number `json:"tx-frames"`
+	QueueDrops Number `json:"queue-drops"`
+}
+
+// RadioATFStats is one entry of the Air Time Fairness statistics the controller keeps
+// per AP radio, WLAN and ATF policy.
+type RadioATFStats struct {
+	WtpMAC      string `json:"wtp-mac"`
+	RadioSlotID int    `json:"radio-slot-id"`
+	WlanID      int    `json:"wlan-id"`
+	PolicyName  string `json:"atf-policy-name"`
+	// AirtimeAllocation is the share of the radio's airtime the policy is allotted, in
+	// percent.
+	AirtimeAllocation Number `json:"airtime-allocation"`
+	// AirtimeUsed is the running total of airtime the policy's clients have consumed on
+	// the radio, in microseconds.
+	AirtimeUsed Number `json:"airtime-used"`
+}
+
+// RRMNeighborData is one entry of the RRM neighbor list, kept per AP radio, which
+// carries every radio that radio hears over the air.
+type RRMNeighborData struct {
+	WtpMAC            string               `json:"wtp-mac"`
+	RadioSlotID       int                  `json:"radio-slot-id"`
+	NeighborRadioInfo RRMNeighborRadioInfo `json:"neighbor-radio-info"`
+}
+
+// RRMNeighborRadioInfo holds the neighbor list of one AP radio.
+type RRMNeighborRadioInfo struct {
+	NeighborRadioList []RRMNeighborRadioEntry `json:"neighbor-radio-list"`
+}
+
+// RRMNeighborRadioEntry wraps one neighbor, which the model nests a level deeper than
+// the list entry.
+type RRMNeighborRadioEntry struct {
+	NeighborRadioInfo RRMNeighborRadio `json:"neighbor-radio-info"`
+}
+
+// RRMNeighborRadio is one radio an AP radio hears, with the RSSI and the channel it
+// was heard at. The MAC is the neighbor AP's radio MAC, the key wnc_ap_joined uses.
+type RRMNeighborRadio struct {
+	NeighborRadioMAC    string `json:"neighbor-radio-mac"`
+	NeighborRadioSlotID int    `json:"neighbor-radio-slot-id"`
+	RSSI                int    `json:"rssi"`
+	SNR                 int    `json:"snr"`
+	Channel             int    `json:"channel"`
+}
+
+// SpectrumDevice is one entry of the CleanAir device table: an interference source
+// CleanAir classified, with the AP radio that detected it and the channel it was
+// detected on. The device-id is the cluster CleanAir merges one source's reports into,
+// so the same device detected by two radios is two entries under one identifier.
+type SpectrumDevice struct {
+	DeviceID    string `json:"device-id"`
+	WtpMAC      string `json:"wtp-mac"`
+	RadioSlotID int    `json:"radio-slot-id"`
+	DeviceType  string `json:"dev-type"`
+	Channel     int    `json:"channel"`
+	Severity    int    `json:"severity"`
+	DutyCycle   int    `json:"duty-cycle"`
+}
+
+// AAARadiusServer is one entry of the RADIUS statistics the controller keeps per server,
+// keyed by the server group, the server address and its two ports. A counter the entry
+// omits decodes to an empty Number, which the collector withholds rather than reading
+// as zero.
+type AAARadiusServer struct {
+	GroupName  string `json:"group-name"`
+	ServerIP   string `json:"radius-server-ip"`
+	AuthPort   int    `json:"auth-port"`
+	AcctPort   int    `json:"acct-port"`
+	ServerName string `json:"server-name"`
+	State      string `json:"server-state"`
+
+	AuthRequests    Number `json:"auth-requests"`
+	AuthAccepts     Number `json:"auth-accepts"`
+	AuthRejects     Number `json:"auth-rejects"`
+	AuthTimeouts    Number `json:"auth-timeouts"`
+	AuthRetransmits Number `json:"auth-retransmits"`
+	AcctRequests    Number `json:"acct-requests"`
+	AcctResponses   Number `json:"acct-responses"`
+	AcctTimeouts    Number `json:"acct-timeouts"`
+	AcctRetransmits Number `json:"acct-retransmits"`
+	// ResponseTime is the round-trip time of the server's last response, in
+	// milliseconds.
+	ResponseTime Number `json:"response-time"`
+}
+
+// WLANAppStats is one entry of the AVC statistics the controller keeps per WLAN,
+// application and direction, with the application spelled as NBAR names it.
+type WLANAppStats struct {
+	WlanID    int    `json:"wlan-id"`
+	AppName   string `json:"app-name"`
+	Direction string `json:"direction"`
+
+	Bytes   Number `json:"bytes"`
+	Packets Number `json:"packets"`
+}
@@ -0,0 +1,43 @@
+package wnc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestNumber_DecodesEveryLeafShape decodes a CDP list as the controller writes it, with
+// the speed as a number, as a numeric string and as a word, and must keep the list
+// whole: only the word fails, and only for its own leaf.
+func TestNumber_DecodesEveryLeafShape(t *testing.T) {
+	t.Parallel()
+
+	fixture := `[
+		{"mac-addr": "aa:bb:cc:11:22:80", "cdp-cache-interface-speed": 1000},
+		{"mac-addr": "aa:bb:cc:11:22:90", "cdp-cache-interface-speed": "2500"},
+		{"mac-addr": "aa:bb:cc:11:22:a0", "cdp-cache-interface-speed": "auto"},
+		{"mac-addr": "aa:bb:cc:11:22:b0", "cdp-cache-interface-speed": null},
+		{"mac-addr": "aa:bb:cc:11:22:c0", "cdp-cache-interface-speed": true},
+		{"mac-addr": "aa:bb:cc:11:22:d0", "cdp-cache-interface-speed": "NaN"},
+		{"mac-addr": "aa:bb:cc:11:22:e0"}
+	]`
+
+	var neighbors []CDPNeighbor
+	if err := json.Unmarshal([]byte(fixture), &neighbors); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, want the list decoded", err)
+	}
+	if len(neighbors) != 7 {
+		t.Fatalf("decoded %d neighbors, want 7", len(neighbors))
+	}
+
+	want := []struct {
+		value float64
+		ok    bool
+	}{{1000, true}, {2500, true}, {0, false}, {0, false}, {0, false}, {0, false}, {0, false}}
+	for i, neighbor := range neighbors {
+		value, err := neighbor.InterfaceSpeed.Float64()
+		if ok := err == nil; ok != want[i].ok || value != want[i].value {
+			t.Errorf("%s speed = %v (ok=%t), want %v (ok=%t)",
+				neighbor.WtpMAC, value, ok, want[i].value, want[i].ok)
+		}
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"runtime/debug"
 	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -41,6 +44,9 @@ type refresher struct {
 	run  func(context.Context) (*WNCDataCache, error)
 	// onDone reports every outcome, including a recovered panic.
 	onDone func(err error, elapsed time.Duration)
+	// tracer starts the span each refresh runs under. It defaults to the global
+	// provider's, which is a no-op unless tracing is configured.
+	tracer trace.Tracer
 }
 
 func newRefresher(
@@ -48,7 +54,9 @@ func newRefresher(
 	run func(context.Context) (*WNCDataCache, error),
 	onDone func(err error, elapsed time.Duration),
 ) *refresher {
-	return &refresher{base: time.Now(), ttl: ttl, run: run, onDone: onDone}
+	return &refresher{
+		base: time.Now(), ttl: ttl, run: run, onDone: onDone, tracer: otel.Tracer(tracerName),
+	}
 }
 
 // get returns the current snapshot, nil until the first refresh succeeds, and
@@ -71,8 +79,9 @@ func (r *refresher) stamp() {
 	r.nextAt.Store(int64(time.Since(r.base)) + int64(r.ttl))
 }
 
-// refreshOnce runs one refresh. It is safe to call directly from a test.
-func (r *refresher) refreshOnce(ctx context.Context) {
+// refreshOnce runs one refresh and returns the error that failed it, nil when it
+// succeeded or was not due. It is safe to call directly from a test.
+func (r *refresher) refreshOnce(ctx context.Context) (err error) {
 	// Registered first so it runs last: releasing the guard before stamping would
 	// let the next caller start a refresh immediately.
 	defer r.inflight.Store(false)
@@ -81,16 +90,19 @@ func (r *refresher) refreshOnce(ctx context.Context) {
 	// the CAS after it released. Re-checking here, rather than in get, keeps the
 	// deferred release above on every path.
 	if !r.due() {
-		return
+		return nil
 	}
 
 	start := time.Now()
+	ctx, span := r.tracer.Start(ctx, spanRefresh)
 
 	defer r.stamp()
 	defer func() {
 		if v := recover(); v != nil {
 			slog.Error("WNC data refresh panicked", "panic", v, "stack", string(debug.Stack()))
 			r.onDone(errRefreshPanicked, time.Since(start))
+			endSpan(span, errRefreshPanicked)
+			err = errRefreshPanicked
 		}
 	}()
 
@@ -113,4 +125,6 @@ func (r *refresher) refreshOnce(ctx context.Context) {
 	}
 
 	r.onDone(err, elapsed)
+	endSpan(span, err)
+	return err
 }
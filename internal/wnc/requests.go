@@ -0,0 +1,255 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the per-request instrumentation of the calls a refresh makes.
+package wnc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// requestCodeError counts a request that failed without an HTTP status: the
+// connection failed, the body was cut short, or the refresh deadline cancelled it.
+const requestCodeError = "error"
+
+// requestDurationBuckets bound one RESTCONF request. A small list answers in tens of
+// milliseconds and a client list on a busy controller in seconds, while a request
+// past the top bucket is meeting the request timeout rather than a slow controller.
+var requestDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// responseSizeBuckets bound the body of one RESTCONF answer, from a single leaf to
+// the client lists of a controller at its scale limit.
+var responseSizeBuckets = []float64{
+	1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20,
+}
+
+// Histogram is a cumulative histogram in the form a const histogram metric takes.
+type Histogram struct {
+	Count uint64
+	Sum   float64
+	// Buckets maps each upper bound to the observations at or below it.
+	Buckets map[float64]uint64
+}
+
+// clone returns a deep copy, so a snapshot handed to a scrape does not change under it.
+func (h *Histogram) clone() Histogram {
+	return Histogram{Count: h.Count, Sum: h.Sum, Buckets: maps.Clone(h.Buckets)}
+}
+
+// observe records one value.
+func (h *Histogram) observe(value float64) {
+	h.Count++
+	h.Sum += value
+	for bound := range h.Buckets {
+		if value <= bound {
+			h.Buckets[bound]++
+		}
+	}
+}
+
+// newHistogram returns an empty histogram over the given bounds.
+func newHistogram(bounds []float64) *Histogram {
+	buckets := make(map[float64]uint64, len(bounds))
+	for _, bound := range bounds {
+		buckets[bound] = 0
+	}
+	return &Histogram{Buckets: buckets}
+}
+
+// requestDataKey carries the data type a request is made for through its context, so
+// the transport, which knows nothing of data types, can label it.
+type requestDataKey struct{}
+
+// withRequestData returns a context whose requests are counted for the data type.
+func withRequestData(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, requestDataKey{}, name)
+}
+
+// requestOutcomeKey carries a requestOutcome through a context.
+type requestOutcomeKey struct{}
+
+// requestOutcome records the code of the last request made under a context, so a
+// caller that only sees the SDK's error can name the status the controller answered.
+type requestOutcome struct {
+	mu   sync.Mutex
+	code string
+}
+
+// withRequestOutcome returns a context whose requests record their code in the
+// returned outcome.
+func withRequestOutcome(ctx context.Context) (context.Context, *requestOutcome) {
+	outcome := &requestOutcome{}
+	return context.WithValue(ctx, requestOutcomeKey{}, outcome), outcome
+}
+
+// Code returns the code of the last request recorded, or requestCodeError when no
+// request got as far as being recorded.
+func (o *requestOutcome) Code() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.code == "" {
+		return requestCodeError
+	}
+	return o.code
+}
+
+func (o *requestOutcome) set(code string) {
+	o.mu.Lock()
+	o.code = code
+	o.mu.Unlock()
+}
+
+// requestMeter counts the requests a refresh makes, per data type. It is written by
+// the refresh goroutine and read by a scrape.
+//
+// A data type can take more than one request per refresh: a retry is a request, and
+// so is the plain re-read after a controller rejects the request for the values in
+// force. Each is counted on its own, which is what makes this the load the controller
+// sees rather than the data types the exporter reads.
+type requestMeter struct {
+	mu        sync.Mutex
+	counts    map[string]map[string]int
+	durations map[string]*Histogram
+	sizes     map[string]*Histogram
+}
+
+func newRequestMeter() *requestMeter {
+	return &requestMeter{
+		counts:    make(map[string]map[string]int),
+		durations: make(map[string]*Histogram),
+		sizes:     make(map[string]*Histogram),
+	}
+}
+
+// observe records one request for the data type that started at start. A negative
+// size means no whole body was read, so only the duration and the code are recorded.
+func (m *requestMeter) observe(name, code string, start time.Time, size int) {
+	elapsed := time.Since(start).Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.counts[name] == nil {
+		m.counts[name] = make(map[string]int)
+		m.durations[name] = newHistogram(requestDurationBuckets)
+	}
+	m.counts[name][code]++
+	m.durations[name].observe(elapsed)
+
+	if size < 0 {
+		return
+	}
+	if m.sizes[name] == nil {
+		m.sizes[name] = newHistogram(responseSizeBuckets)
+	}
+	m.sizes[name].observe(float64(size))
+}
+
+// snapshot returns deep copies of what has been recorded since process start.
+func (m *requestMeter) snapshot() (
+	counts map[string]map[string]int, durations, sizes map[string]Histogram,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts = make(map[string]map[string]int, len(m.counts))
+	for name, codes := range m.counts {
+		counts[name] = maps.Clone(codes)
+	}
+	durations = make(map[string]Histogram, len(m.durations))
+	for name, h := range m.durations {
+		durations[name] = h.clone()
+	}
+	sizes = make(map[string]Histogram, len(m.sizes))
+	for name, h := range m.sizes {
+		sizes[name] = h.clone()
+	}
+
+	return counts, durations, sizes
+}
+
+// meteredTransport wraps the transport of the SDK client, so every request a
+// refresh makes, typed or raw, is recorded with the status the controller answered and
+// the size of the body it sent. A request whose context names no data type, such as
+// the check subcommand's authorization probe, is passed through unrecorded.
+type meteredTransport struct {
+	base  http.RoundTripper
+	meter *requestMeter
+}
+
+// RoundTrip implements http.RoundTripper. The request is recorded when its body is
+// closed, so its duration covers reading the answer and its size is the whole body.
+func (t *meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	name, metered := ctx.Value(requestDataKey{}).(string)
+	outcome, _ := ctx.Value(requestOutcomeKey{}).(*requestOutcome)
+	if !metered && outcome == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.record(name, metered, outcome, requestCodeError, start, -1)
+		return nil, err
+	}
+
+	resp.Body = &meteredBody{
+		ReadCloser: resp.Body,
+		done: func(size int, err error) {
+			code := strconv.Itoa(resp.StatusCode)
+			if err != nil {
+				code, size = requestCodeError, -1
+			}
+			t.record(name, metered, outcome, code, start, size)
+		},
+	}
+	return resp, nil
+}
+
+// record hands one request to the meter and to the outcome, whichever the context
+// asked for.
+func (t *meteredTransport) record(
+	name string, metered bool, outcome *requestOutcome, code string, start time.Time, size int,
+) {
+	if metered {
+		t.meter.observe(name, code, start, size)
+	}
+	if outcome != nil {
+		outcome.set(code)
+	}
+}
+
+// meteredBody counts the bytes read from an answer and reports them once, on close.
+// A read that fails short of the end reports the failure instead.
+type meteredBody struct {
+	io.ReadCloser
+
+	size int
+	err  error
+	once sync.Once
+	done func(size int, err error)
+}
+
+// Read implements io.Reader.
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.size, b.err) })
+	return err
+}
@@ -0,0 +1,169 @@
+package wnc
+
+import (
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// TestMeteredTransport_RecordsStatusAndSize pins what the transport records: the status
+// each answer carried, success or not, the size of each whole body, an error for a
+// request with no answer, and nothing for a request made for no data type.
+func TestMeteredTransport_RecordsStatusAndSize(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"boot-time":"2026-01-01T00:00:00+00:00"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	meter := newRequestMeter()
+	client := &http.Client{Transport: &meteredTransport{base: http.DefaultTransport, meter: meter}}
+	get := func(ctx context.Context, url string) error {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf("NewRequestWithContext() error = %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Body.Close()
+	}
+
+	ctx := withRequestData(context.Background(), DataControllerBootTime)
+	if err := get(ctx, server.URL+"/"); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if err := get(ctx, server.URL+"/busy"); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if err := get(ctx, "http://127.0.0.1:0/"); err == nil {
+		t.Fatal("get() error = nil, want the dial's")
+	}
+	if err := get(context.Background(), server.URL+"/"); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	counts, durations, sizes := meter.snapshot()
+	want := map[string]int{"200": 1, "503": 1, requestCodeError: 1}
+	if got := counts[DataControllerBootTime]; !maps.Equal(got, want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	if len(counts) != 1 {
+		t.Errorf("counts = %v, want a request made for no data type left out", counts)
+	}
+	if got := durations[DataControllerBootTime].Count; got != 3 {
+		t.Errorf("duration count = %d, want 3: a failed request took time too", got)
+	}
+	if got := sizes[DataControllerBootTime]; got.Count != 2 || got.Sum != float64(len(body)) {
+		t.Errorf("size = %d observations summing %v, want two summing %d bytes", got.Count, got.Sum, len(body))
+	}
+}
+
+// TestRequestOutcome_ReportsTheLastCode pins the code the check subcommand prints.
+func TestRequestOutcome_ReportsTheLastCode(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, outcome := withRequestOutcome(context.Background())
+	if got := outcome.Code(); got != requestCodeError {
+		t.Errorf("Code() before any request = %q, want %q", got, requestCodeError)
+	}
+
+	client := &http.Client{Transport: &meteredTransport{base: http.DefaultTransport, meter: newRequestMeter()}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() error = %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got := outcome.Code(); got != "404" {
+		t.Errorf("Code() = %q, want 404", got)
+	}
+}
+
+// TestDataSource_FetchAllData_CountsEveryRequest pins that the request families count
+// what the controller sees: each retry is a request, under the status it was answered
+// with, and the typed SDK reads carry a size as the raw reads do.
+func TestDataSource_FetchAllData_CountsEveryRequest(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(busyFor(DataClientCommonOperData, 2))
+	defer server.Close()
+
+	ds := newRetryingDataSource(t, server.URL, 3)
+	if _, err := ds.fetchAllData(context.Background()); err != nil {
+		t.Fatalf("fetchAllData() error = %v, want nil", err)
+	}
+
+	suppressBackgroundRefresh(ds)
+	stats := ds.Stats()
+
+	common := stats.Requests[DataClientCommonOperData]
+	if common["503"] != 2 || common["200"] != 1 {
+		t.Errorf("Requests[%s] = %v, want two 503 and one 200", DataClientCommonOperData, common)
+	}
+	if got := stats.RequestDurations[DataClientCommonOperData].Count; got != 3 {
+		t.Errorf("RequestDurations[%s].Count = %d, want 3", DataClientCommonOperData, got)
+	}
+	for _, name := range dataTypeNames {
+		if _, ok := stats.Requests[name]; !ok {
+			t.Errorf("Requests has no entry for %s, want every fetched data type counted", name)
+		}
+	}
+
+	if got := stats.ResponseSizes[DataClientCommonOperData].Count; got != 3 {
+		t.Errorf("ResponseSizes[%s].Count = %d, want 3: a busy answer has a body too",
+			DataClientCommonOperData, got)
+	}
+	if got := stats.ResponseSizes[DataAAARadiusStats].Count; got != 1 {
+		t.Errorf("ResponseSizes[%s].Count = %d, want 1", DataAAARadiusStats, got)
+	}
+}
+
+// TestMeterTransport_KeepsTheSDKTransport wraps the transport the SDK built rather than
+// one of its own, so the settings the SDK applies still govern every request.
+func TestMeterTransport_KeepsTheSDKTransport(t *testing.T) {
+	t.Parallel()
+
+	sdk := &http.Transport{MaxIdleConnsPerHost: 7}
+	client := &http.Client{Transport: sdk}
+	meterTransport(client, newRequestMeter())
+	if metered, ok := client.Transport.(*meteredTransport); !ok || metered.base != sdk {
+		t.Errorf("Transport = %#v, want the SDK's transport wrapped", client.Transport)
+	}
+
+	client = &http.Client{}
+	meterTransport(client, newRequestMeter())
+	if metered, ok := client.Transport.(*meteredTransport); !ok || metered.base != http.DefaultTransport {
+		t.Errorf("Transport = %#v, want http.DefaultTransport wrapped", client.Transport)
+	}
+
+	wncClient := createWNCClient(config.WNC{Controller: "wnc1.example.internal", AccessToken: "token"},
+		newRequestMeter())
+	if _, ok := wncClient.Core().HTTP.Transport.(*meteredTransport); !ok {
+		t.Errorf("SDK client transport = %#v, want it metered", wncClient.Core().HTTP.Transport)
+	}
+}
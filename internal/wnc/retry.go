@@ -0,0 +1,140 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the retry policy a refresh applies to each data type it fetches.
+package wnc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	wnc "github.com/umatare5/cisco-ios-xe-wireless-go"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// retryableStatuses are the HTTP statuses a fetch is retried on: the controller saying
+// it is busy, or a proxy in front of it that could not reach it. Every other status, a
+// 500 included, is an answer the controller would give again, so retrying it only
+// spends the refresh deadline.
+var retryableStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryPolicy decides whether and when a failed fetch is tried again. It applies to each
+// data type on its own, so one data type retrying does not spend the attempts of the
+// next.
+type retryPolicy struct {
+	// attempts bounds the fetches of one data type per refresh, the first included.
+	attempts int
+	// backoff is the wait before the first retry, doubled before each next one.
+	backoff time.Duration
+	// statuses are the HTTP statuses worth another attempt.
+	statuses []int
+}
+
+// newRetryPolicy builds the policy the configuration asks for. A configuration that
+// never set the attempts, as a test's may not, fetches once.
+func newRetryPolicy(cfg config.WNC) retryPolicy {
+	return retryPolicy{
+		attempts: max(cfg.RetryAttempts, 1),
+		backoff:  cfg.RetryBackoff,
+		statuses: retryableStatuses,
+	}
+}
+
+// retryable reports whether err is worth another attempt.
+//
+// An HTTP answer is retried when its status is one of the policy's. An error with no
+// status is retried when the connection failed or the body was cut short, which is
+// what a dropped TCP connection looks like. A decoding error, or an envelope this
+// exporter refuses, is not: the same bytes would come back. Nothing is retried once
+// the refresh deadline has passed, since a request it cancelled looks like a network
+// error too.
+func (p retryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *wnc.APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.statuses, apiErr.StatusCode)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// delay returns the wait before the given retry, counted from 1.
+func (p retryPolicy) delay(retry int) time.Duration {
+	return p.backoff << (retry - 1)
+}
+
+// fetchWithRetry runs one fetcher under the retry policy and returns its last outcome.
+//
+// A retry whose wait would outlast the refresh deadline is not started, so the deadline
+// bounds the retries along with everything else, and a data type the deadline cut
+// short reports the error it last failed with. Every retry started raises the data
+// type's retry counter, whether or not it then succeeds. The data type is traced as
+// one span covering its retries, carrying its item count, attempts and last error.
+func (s *dataSource) fetchWithRetry(ctx context.Context, f dataFetcher, data *WNCDataCache) (count int, err error) {
+	ctx = withRequestData(ctx, f.name)
+	ctx, span := startSpan(ctx, spanFetch, attrData.String(f.name))
+
+	attempt := 1
+	defer func() {
+		span.SetAttributes(attrItems.Int(count), attrAttempts.Int(attempt))
+		endSpan(span, err)
+	}()
+
+	for ; ; attempt++ {
+		count, err = f.fetch(ctx, data)
+		if err == nil || attempt >= s.retry.attempts || !s.retry.retryable(ctx, err) {
+			return count, err
+		}
+
+		wait := s.retry.delay(attempt)
+		if !sleepWithin(ctx, wait) {
+			return count, err
+		}
+
+		s.countRetry(f.name)
+		slog.Debug("retrying data fetch", "data", f.name,
+			"attempt", attempt+1, "error", err)
+	}
+}
+
+// sleepWithin waits for d and reports whether it did. It refuses a wait that would end
+// past the context deadline, and gives up when the context is cancelled meanwhile.
+func sleepWithin(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// countRetry records one retry of a data type for wnc_refresh_retries_total. It is
+// counted as it happens rather than with the refresh outcome, so a refresh that panics
+// afterwards does not lose it.
+func (s *dataSource) countRetry(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retries[name]++
+}
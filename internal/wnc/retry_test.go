@@ -0,0 +1,170 @@
+package wnc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	wnc "github.com/umatare5/cisco-ios-xe-wireless-go"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	t.Parallel()
+
+	policy := newRetryPolicy(config.WNC{RetryAttempts: 3})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"busy controller", context.Background(),
+			&wnc.APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"unreachable behind a proxy", context.Background(),
+			fmt.Errorf("list: %w", &wnc.APIError{StatusCode: http.StatusBadGateway}), true},
+		{"rate limited", context.Background(),
+			&wnc.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		// A 500 is an answer the controller would give again, not a busy one.
+		{"server error", context.Background(),
+			&wnc.APIError{StatusCode: http.StatusInternalServerError}, false},
+		{"not found", context.Background(),
+			&wnc.APIError{StatusCode: http.StatusNotFound}, false},
+		{"dropped connection", context.Background(),
+			&net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("connection reset by peer")}, true},
+		{"truncated body", context.Background(),
+			fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), true},
+		{"undecodable body", context.Background(), &json.SyntaxError{}, false},
+		{"refresh deadline passed", cancelled,
+			&wnc.APIError{StatusCode: http.StatusServiceUnavailable}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policy.retryable(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DelayDoubles(t *testing.T) {
+	t.Parallel()
+
+	policy := newRetryPolicy(config.WNC{RetryAttempts: 4, RetryBackoff: time.Second})
+
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		if got := policy.delay(retry); got != want {
+			t.Errorf("delay(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
+
+// TestDataSource_FetchAllData_RetriesTransientFailure pins what the policy is for: one
+// busy answer must not withhold a data type for a whole TTL, and the retry is counted
+// where a failure would have been.
+func TestDataSource_FetchAllData_RetriesTransientFailure(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(busyFor(DataClientCommonOperData, 2))
+	defer server.Close()
+
+	ds := newRetryingDataSource(t, server.URL, 3)
+
+	data, err := ds.fetchAllData(context.Background())
+	if err != nil {
+		t.Fatalf("fetchAllData() error = %v, want nil", err)
+	}
+	if err := data.FetchErrors[DataClientCommonOperData]; err != nil {
+		t.Errorf("FetchErrors[%s] = %v, want nil after the retries succeeded", DataClientCommonOperData, err)
+	}
+	if len(data.CommonOperData) != 1 {
+		t.Errorf("CommonOperData length = %d, want 1", len(data.CommonOperData))
+	}
+
+	suppressBackgroundRefresh(ds)
+	stats := ds.Stats()
+	if got := stats.Retries[DataClientCommonOperData]; got != 2 {
+		t.Errorf("Stats().Retries[%s] = %d, want 2", DataClientCommonOperData, got)
+	}
+	if got := stats.Errors[DataClientCommonOperData]; got != 0 {
+		t.Errorf("Stats().Errors[%s] = %d, want 0: a data type the retry recovered did not fail",
+			DataClientCommonOperData, got)
+	}
+	if got, ok := stats.Retries[DataAPCAPWAPData]; !ok || got != 0 {
+		t.Errorf("Stats().Retries[%s] = %d (present %v), want a seeded 0", DataAPCAPWAPData, got, ok)
+	}
+}
+
+func TestDataSource_FetchAllData_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(busyFor(DataClientCommonOperData, 5))
+	defer server.Close()
+
+	ds := newRetryingDataSource(t, server.URL, 3)
+
+	data, err := ds.fetchAllData(context.Background())
+	if err != nil {
+		t.Fatalf("fetchAllData() error = %v, want nil: one failed data type keeps the snapshot", err)
+	}
+	if data.FetchErrors[DataClientCommonOperData] == nil {
+		t.Errorf("FetchErrors[%s] = nil, want the last busy answer", DataClientCommonOperData)
+	}
+
+	suppressBackgroundRefresh(ds)
+	stats := ds.Stats()
+	if got := stats.Retries[DataClientCommonOperData]; got != 2 {
+		t.Errorf("Stats().Retries[%s] = %d, want 2 for three attempts", DataClientCommonOperData, got)
+	}
+	if got := stats.Errors[DataClientCommonOperData]; got != 1 {
+		t.Errorf("Stats().Errors[%s] = %d, want 1: the refresh fails the data type once", DataClientCommonOperData, got)
+	}
+}
+
+// TestSleepWithin_RefusesWaitPastDeadline keeps the retries inside the refresh
+// deadline: a wait that would outlast it is not started.
+func TestSleepWithin_RefusesWaitPastDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	if sleepWithin(ctx, time.Hour) {
+		t.Error("sleepWithin() = true for a wait past the deadline, want false")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleepWithin() waited %v before refusing, want no wait", elapsed)
+	}
+}
+
+// newRetryingDataSource returns a data source whose retries wait a millisecond.
+func newRetryingDataSource(t *testing.T, controllerURL string, attempts int) *dataSource {
+	t.Helper()
+
+	ds, ok := NewDataSource(config.WNC{
+		Controller:    extractHostFromURL(controllerURL),
+		AccessToken:   "test-token",
+		Timeout:       5 * time.Second,
+		TLSSkipVerify: true,
+		CacheTTL:      55 * time.Second,
+		RetryAttempts: attempts,
+		RetryBackoff:  time.Millisecond,
+	}, allModules()).(*dataSource)
+	if !ok {
+		t.Fatal("NewDataSource did not return *dataSource")
+	}
+	return ds
+}
@@ -17,6 +17,8 @@ type RRMSource interface {
 	GetRRMMainData(ctx context.Context) ([]rrm.MainData, error)
 	GetSpectrumAqTable(ctx context.Context) ([]rrm.SpectrumAqTable, error)
 	GetSpectrumAqWorstTable(ctx context.Context) ([]rrm.SpectrumAqWorstTable, error)
+	GetNeighbors(ctx context.Context) ([]RRMNeighborData, error)
+	GetSpectrumDevices(ctx context.Context) ([]SpectrumDevice, error)
 }
 
 // rrmSource implements RRMSource using SharedDataSource for caching.
@@ -33,7 +35,7 @@ func NewRRMSource(sharedDataSource DataSource) RRMSource {
 
 // GetRRMMeasurements returns RRM measurement data from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetRRMMeasurements(ctx context.Context) ([]rrm.RRMMeasurement, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMMeasurement)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMMeasurement)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +44,7 @@ func (s *rrmSource) GetRRMMeasurements(ctx context.Context) ([]rrm.RRMMeasuremen
 
 // GetRRMCoverage returns RRM coverage data from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetRRMCoverage(ctx context.Context) ([]rrm.RRMCoverage, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMCoverage)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMCoverage)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +53,7 @@ func (s *rrmSource) GetRRMCoverage(ctx context.Context) ([]rrm.RRMCoverage, erro
 
 // GetApDot11RadarData returns AP radar detection data from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetApDot11RadarData(ctx context.Context) ([]rrm.ApDot11RadarData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMAPDot11RadarData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMAPDot11RadarData)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +62,7 @@ func (s *rrmSource) GetApDot11RadarData(ctx context.Context) ([]rrm.ApDot11Radar
 
 // GetRadioSlots returns the per-radio RRM slot data from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetRadioSlots(ctx context.Context) ([]rrm.RadioSlot, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMRadioSlot)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMRadioSlot)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +71,7 @@ func (s *rrmSource) GetRadioSlots(ctx context.Context) ([]rrm.RadioSlot, error)
 
 // GetRRMMainData returns the per-band RRM run data from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetRRMMainData(ctx context.Context) ([]rrm.MainData, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMMainData)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMMainData)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +80,7 @@ func (s *rrmSource) GetRRMMainData(ctx context.Context) ([]rrm.MainData, error)
 
 // GetSpectrumAqTable returns the per-band air quality table from WNC via SharedDataSource (cached).
 func (s *rrmSource) GetSpectrumAqTable(ctx context.Context) ([]rrm.SpectrumAqTable, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMSpectrumAqTable)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMSpectrumAqTable)
 	if err != nil {
 		return nil, err
 	}
@@ -88,10 +90,30 @@ func (s *rrmSource) GetSpectrumAqTable(ctx context.Context) ([]rrm.SpectrumAqTab
 // GetSpectrumAqWorstTable returns the worst air quality row of each band from WNC via
 // SharedDataSource (cached).
 func (s *rrmSource) GetSpectrumAqWorstTable(ctx context.Context) ([]rrm.SpectrumAqWorstTable, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataRRMSpectrumAqWorst)
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMSpectrumAqWorst)
 	if err != nil {
 		return nil, err
 	}
 
 	return data.SpectrumAqWorst, nil
 }
+
+// GetNeighbors returns the radios each AP radio hears over the air from WNC via
+// SharedDataSource (cached).
+func (s *rrmSource) GetNeighbors(ctx context.Context) ([]RRMNeighborData, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMAPAutoRFDot11Data)
+	if err != nil {
+		return nil, err
+	}
+	return data.RRMNeighbors, nil
+}
+
+// GetSpectrumDevices returns the interference sources CleanAir classified from WNC via
+// SharedDataSource (cached).
+func (s *rrmSource) GetSpectrumDevices(ctx context.Context) ([]SpectrumDevice, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataRRMSpectrumDevice)
+	if err != nil {
+		return nil, err
+	}
+	return data.SpectrumDevices, nil
+}
@@ -130,6 +130,29 @@ func newMockRRMDataSource() *mockRRMDataSource {
 					LastRadarOnRadio: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 				},
 			},
+			RRMNeighbors: []RRMNeighborData{
+				{
+					WtpMAC:      "aa:bb:cc:11:22:80",
+					RadioSlotID: 1,
+					NeighborRadioInfo: RRMNeighborRadioInfo{
+						NeighborRadioList: []RRMNeighborRadioEntry{
+							{NeighborRadioInfo: RRMNeighborRadio{
+								NeighborRadioMAC: "aa:bb:cc:11:22:20", RSSI: -58, Channel: 36,
+							}},
+						},
+					},
+				},
+			},
+			SpectrumDevices: []SpectrumDevice{
+				{
+					DeviceID: "00:00:00:00:10:01", WtpMAC: "aa:bb:cc:11:22:80", RadioSlotID: 0,
+					DeviceType: "si-dev-type-microwave-oven", Channel: 6, Severity: 12, DutyCycle: 40,
+				},
+				{
+					DeviceID: "00:00:00:00:10:02", WtpMAC: "aa:bb:cc:11:22:80", RadioSlotID: 0,
+					DeviceType: "si-dev-type-bt-link", Channel: 1, Severity: 3, DutyCycle: 5,
+				},
+			},
 		},
 	}
 }
@@ -369,3 +392,111 @@ func TestRRMSource_GetApDot11RadarData(t *testing.T) {
 		})
 	}
 }
+
+func TestRRMSource_GetNeighbors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mock    *mockRRMDataSource
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name:    "Success with one radio",
+			mock:    newMockRRMDataSource(),
+			wantLen: 1,
+			wantErr: false,
+		},
+		{
+			name: "Failed fetch of the neighbor list",
+			mock: &mockRRMDataSource{
+				data: &WNCDataCache{
+					FetchErrors: map[string]error{DataRRMAPAutoRFDot11Data: errors.New("fetch failed")},
+				},
+			},
+			wantLen: 0,
+			wantErr: true,
+		},
+		{
+			name: "Error from data source",
+			mock: &mockRRMDataSource{
+				err: errors.New("cache refresh failed"),
+			},
+			wantLen: 0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewRRMSource(tt.mock)
+			neighbors, err := source.GetNeighbors(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNeighbors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(neighbors) != tt.wantLen {
+				t.Errorf("GetNeighbors() got %d radios, want %d", len(neighbors), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestRRMSource_GetSpectrumDevices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mock    *mockRRMDataSource
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name:    "Success with two devices",
+			mock:    newMockRRMDataSource(),
+			wantLen: 2,
+			wantErr: false,
+		},
+		{
+			name: "Failed fetch of the device table",
+			mock: &mockRRMDataSource{
+				data: &WNCDataCache{
+					FetchErrors: map[string]error{DataRRMSpectrumDevice: errors.New("fetch failed")},
+				},
+			},
+			wantLen: 0,
+			wantErr: true,
+		},
+		{
+			name: "Error from data source",
+			mock: &mockRRMDataSource{
+				err: errors.New("cache refresh failed"),
+			},
+			wantLen: 0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewRRMSource(tt.mock)
+			devices, err := source.GetSpectrumDevices(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSpectrumDevices() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(devices) != tt.wantLen {
+				t.Errorf("GetSpectrumDevices() got %d devices, want %d", len(devices), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the snapshot file that lets a restart serve data before its first refresh.
+package wnc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// snapshotFileVersion is written into every snapshot file. A file of another version is
+// refused rather than decoded, since the records it holds may no longer match the types
+// it would be decoded into.
+const snapshotFileVersion = 1
+
+var (
+	// errSnapshotFileStale reports a snapshot file older than the configured age.
+	errSnapshotFileStale = errors.New("WNC data snapshot file is older than the allowed age")
+	// errSnapshotFileVersion reports a snapshot file written by another version.
+	errSnapshotFileVersion = errors.New("WNC data snapshot file has an unsupported version")
+	// errDataTypeNotInSnapshot marks a data type the enabled modules read but the
+	// restored snapshot's refresh did not, because the modules were different then.
+	// Without it a collector would take the empty slice for a successful empty fetch.
+	errDataTypeNotInSnapshot = errors.New("WNC data type not in the restored snapshot")
+)
+
+// snapshotFile is the on-disk form of a published snapshot.
+//
+// FetchErrors is carried as text, since an error value does not survive encoding, and
+// Requested names the data types the refresh that produced the snapshot read, so a
+// restart with other modules enabled can tell a data type it never fetched from one it
+// fetched empty.
+type snapshotFile struct {
+	Version     int               `json:"version"`
+	Requested   []string          `json:"requested"`
+	FetchErrors map[string]string `json:"fetch_errors"`
+	Data        *WNCDataCache     `json:"data"`
+}
+
+// saveSnapshot writes the snapshot to path. It writes a temporary file beside it and
+// renames it over the old one, so a crash mid-write leaves the previous snapshot rather
+// than a truncated one.
+func saveSnapshot(path string, data *WNCDataCache, requested []string) error {
+	fetchErrors := make(map[string]string, len(data.FetchErrors))
+	for name, err := range data.FetchErrors {
+		fetchErrors[name] = err.Error()
+	}
+
+	content, err := json.Marshal(snapshotFile{
+		Version:     snapshotFileVersion,
+		Requested:   requested,
+		FetchErrors: fetchErrors,
+		Data:        data,
+	})
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	// Once the rename has succeeded there is nothing left to remove.
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if _, err := temp.Write(content); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+// loadSnapshot reads the snapshot at path, refusing one whose refresh started more than
+// maxAge ago, and marks it for the data types the enabled modules read now.
+//
+// A data type no enabled module reads is marked errDataTypeNotRequested, as a refresh
+// would mark it, and one they read that the snapshot's refresh did not is marked
+// errDataTypeNotInSnapshot. A failure the snapshot recorded is restored as its text, so
+// the series derived from it stay withheld until the first refresh.
+func loadSnapshot(path string, maxAge time.Duration, requested []string) (*WNCDataCache, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // The path is the operator's own flag.
+	if err != nil {
+		return nil, err
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != snapshotFileVersion || file.Data == nil {
+		return nil, fmt.Errorf("%w: %d", errSnapshotFileVersion, file.Version)
+	}
+
+	data := file.Data
+	if age := time.Since(data.RefreshedAt); age > maxAge {
+		return nil, fmt.Errorf("%w: refreshed %s ago", errSnapshotFileStale, age.Round(time.Second))
+	}
+
+	data.FetchErrors = make(map[string]error, len(dataTypeNames))
+	for _, name := range dataTypeNames {
+		switch {
+		case !slices.Contains(requested, name):
+			data.FetchErrors[name] = fmt.Errorf("%s: %w", name, errDataTypeNotRequested)
+		case !slices.Contains(file.Requested, name):
+			data.FetchErrors[name] = fmt.Errorf("%s: %w", name, errDataTypeNotInSnapshot)
+		case file.FetchErrors[name] != "":
+			data.FetchErrors[name] = errors.New(file.FetchErrors[name])
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,156 @@
+package wnc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/umatare5/cisco-ios-xe-wireless-go/service/ap"
+	"github.com/umatare5/cisco-wnc-exporter/internal/config"
+)
+
+// TestLoadSnapshot_RestoresFetchErrors pins how a restored snapshot is marked: a
+// failure it recorded stays a failure, and a data type its refresh never read must not
+// be taken for one that came back empty.
+func TestLoadSnapshot_RestoresFetchErrors(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	saved := &WNCDataCache{
+		CAPWAPData:  []ap.CAPWAPData{{WtpMAC: "00:11:22:33:44:50"}},
+		FetchErrors: map[string]error{DataAPJoinStats: errors.New("join stats unavailable")},
+		RefreshedAt: time.Now(),
+	}
+	if err := saveSnapshot(file, saved, []string{DataAPCAPWAPData, DataAPJoinStats}); err != nil {
+		t.Fatalf("saveSnapshot() error = %v", err)
+	}
+
+	data, err := loadSnapshot(file, time.Minute,
+		[]string{DataAPCAPWAPData, DataAPJoinStats, DataAPOperData})
+	if err != nil {
+		t.Fatalf("loadSnapshot() error = %v, want nil", err)
+	}
+
+	if len(data.CAPWAPData) != 1 || data.CAPWAPData[0].WtpMAC != "00:11:22:33:44:50" {
+		t.Errorf("CAPWAPData = %+v, want the saved record", data.CAPWAPData)
+	}
+	if !data.RefreshedAt.Equal(saved.RefreshedAt) {
+		t.Errorf("RefreshedAt = %v, want the saved %v", data.RefreshedAt, saved.RefreshedAt)
+	}
+	if err := data.FetchErrors[DataAPCAPWAPData]; err != nil {
+		t.Errorf("FetchErrors[%s] = %v, want nil", DataAPCAPWAPData, err)
+	}
+	if err := data.FetchErrors[DataAPJoinStats]; err == nil || err.Error() != "join stats unavailable" {
+		t.Errorf("FetchErrors[%s] = %v, want the saved failure", DataAPJoinStats, err)
+	}
+	if err := data.FetchErrors[DataAPOperData]; !errors.Is(err, errDataTypeNotInSnapshot) {
+		t.Errorf("FetchErrors[%s] = %v, want errDataTypeNotInSnapshot", DataAPOperData, err)
+	}
+	if err := data.FetchErrors[DataRRMMainData]; !errors.Is(err, errDataTypeNotRequested) {
+		t.Errorf("FetchErrors[%s] = %v, want errDataTypeNotRequested", DataRRMMainData, err)
+	}
+}
+
+func TestLoadSnapshot_RefusesStaleOrForeignFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "stale.json")
+	old := &WNCDataCache{RefreshedAt: time.Now().Add(-time.Hour)}
+	if err := saveSnapshot(stale, old, nil); err != nil {
+		t.Fatalf("saveSnapshot() error = %v", err)
+	}
+	if _, err := loadSnapshot(stale, time.Minute, nil); !errors.Is(err, errSnapshotFileStale) {
+		t.Errorf("loadSnapshot() error = %v, want errSnapshotFileStale", err)
+	}
+
+	foreign := filepath.Join(dir, "foreign.json")
+	if err := os.WriteFile(foreign, []byte(`{"version":99,"data":{}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := loadSnapshot(foreign, time.Minute, nil); !errors.Is(err, errSnapshotFileVersion) {
+		t.Errorf("loadSnapshot() error = %v, want errSnapshotFileVersion", err)
+	}
+}
+
+// TestDataSource_RestoresSnapshotAcrossRestart drives the whole warm restart: one
+// process refreshes and saves, the next serves that snapshot before its own first
+// refresh, reports it as restored, and stops doing so once it refreshes.
+func TestDataSource_RestoresSnapshotAcrossRestart(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(failing())
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+
+	first := newSnapshotDataSource(t, server.URL, file)
+	first.refresher.refreshOnce(context.Background())
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("snapshot file not written after a successful refresh: %v", err)
+	}
+
+	second := newSnapshotDataSource(t, server.URL, file)
+	suppressBackgroundRefresh(second)
+
+	data, err := second.GetCachedData(context.Background())
+	if err != nil {
+		t.Fatalf("GetCachedData() error = %v, want the restored snapshot", err)
+	}
+	if len(data.CAPWAPData) != 1 {
+		t.Errorf("CAPWAPData length = %d, want 1 from the restored snapshot", len(data.CAPWAPData))
+	}
+	for _, name := range dataTypeNames {
+		if err := data.FetchErrors[name]; err != nil {
+			t.Errorf("FetchErrors[%s] = %v, want nil as the saving refresh recorded it", name, err)
+		}
+	}
+
+	stats := second.Stats()
+	if !stats.Restored {
+		t.Error("Stats().Restored = false, want true before the first refresh")
+	}
+	if stats.Attempted {
+		t.Error("Stats().Attempted = true, want false: restoring is not a refresh")
+	}
+
+	// A restored snapshot past the allowed age is withheld like a stale one.
+	second.snapshotMaxAge = time.Nanosecond
+	if _, err := second.GetCachedData(context.Background()); !errors.Is(err, errSnapshotWithheld) {
+		t.Errorf("GetCachedData() error = %v, want errSnapshotWithheld past the allowed age", err)
+	}
+
+	second.refresher.inflight.Store(false)
+	second.refresher.refreshOnce(context.Background())
+	suppressBackgroundRefresh(second)
+
+	if _, err := second.GetCachedData(context.Background()); err != nil {
+		t.Errorf("GetCachedData() error = %v, want nil after a refresh replaced the restored snapshot", err)
+	}
+	if second.Stats().Restored {
+		t.Error("Stats().Restored = true after a refresh, want false")
+	}
+}
+
+// newSnapshotDataSource returns a data source that keeps its snapshot in file.
+func newSnapshotDataSource(t *testing.T, controllerURL, file string) *dataSource {
+	t.Helper()
+
+	ds, ok := NewDataSource(config.WNC{
+		Controller:     extractHostFromURL(controllerURL),
+		AccessToken:    "test-token",
+		Timeout:        5 * time.Second,
+		TLSSkipVerify:  true,
+		CacheTTL:       55 * time.Second,
+		SnapshotFile:   file,
+		SnapshotMaxAge: time.Minute,
+	}, allModules()).(*dataSource)
+	if !ok {
+		t.Fatal("NewDataSource did not return *dataSource")
+	}
+	return ds
+}
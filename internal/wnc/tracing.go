@@ -0,0 +1,52 @@
+// Package wnc provides WNC data access and caching.
+// This file holds the spans a refresh is traced with.
+package wnc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of every span this package starts.
+const tracerName = "github.com/umatare5/cisco-wnc-exporter/internal/wnc"
+
+// Span names, one per step a slow refresh can be spending its time in.
+const (
+	spanRefresh  = "wnc.refresh"
+	spanFetch    = "wnc.fetch"
+	spanFallback = "wnc.fallback"
+	spanRawRead  = "wnc.raw_read"
+)
+
+// Span attributes.
+const (
+	attrData     = attribute.Key("wnc.data")
+	attrItems    = attribute.Key("wnc.items")
+	attrAttempts = attribute.Key("wnc.attempts")
+	attrPath     = attribute.Key("wnc.path")
+	attrPresent  = attribute.Key("wnc.present")
+	attrSize     = attribute.Key("http.response.body.size")
+	attrStatus   = attribute.Key("http.response.status_code")
+)
+
+// startSpan starts a child of the span in ctx, from the same provider. The refresh
+// span is the only one started from the refresher's own tracer; every span under it
+// follows the context, so a test that records the refresh records the whole tree.
+func startSpan(
+	ctx context.Context, name string, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
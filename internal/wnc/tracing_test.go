@@ -0,0 +1,138 @@
+package wnc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestRefreshOnce_TracesSpanTree pins the shape a slow refresh is read from: one
+// refresh span, a fetch span under it for every data type with the attempts and the
+// outcome it ended with, and the raw reads under the fetch that made them.
+func TestRefreshOnce_TracesSpanTree(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(mockServerConfig{
+		fail: map[string]bool{DataAPJoinStats: true},
+		busy: map[string]int{DataClientCommonOperData: 1},
+	})
+	defer server.Close()
+
+	ds := newRetryingDataSource(t, server.URL, 3)
+	recorder := recordSpans(ds)
+	ds.refresher.refreshOnce(context.Background())
+
+	spans := recorder.Ended()
+	refresh := spansNamed(spans, spanRefresh)
+	if len(refresh) != 1 {
+		t.Fatalf("got %d %s spans, want 1", len(refresh), spanRefresh)
+	}
+	if refresh[0].Parent().IsValid() {
+		t.Errorf("%s span has a parent, want it to be the root", spanRefresh)
+	}
+
+	fetches := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spansNamed(spans, spanFetch) {
+		if span.Parent().SpanID() != refresh[0].SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of the refresh span", spanFetch)
+		}
+		fetches[spanAttribute(span, attrData).AsString()] = span
+	}
+	if len(fetches) != len(ds.names) {
+		t.Errorf("got %d %s spans, want one per data type (%d)", len(fetches), spanFetch, len(ds.names))
+	}
+
+	retried := fetches[DataClientCommonOperData]
+	if retried == nil {
+		t.Fatalf("no %s span for %s", spanFetch, DataClientCommonOperData)
+	}
+	if got := spanAttribute(retried, attrAttempts).AsInt64(); got != 2 {
+		t.Errorf("%s attempts = %d, want 2", DataClientCommonOperData, got)
+	}
+	if got := spanAttribute(retried, attrItems).AsInt64(); got != 1 {
+		t.Errorf("%s items = %d, want 1", DataClientCommonOperData, got)
+	}
+	if got := retried.Status().Code; got != codes.Unset {
+		t.Errorf("%s status = %v, want unset after the retry succeeded", DataClientCommonOperData, got)
+	}
+
+	failed := fetches[DataAPJoinStats]
+	if failed == nil {
+		t.Fatalf("no %s span for %s", spanFetch, DataAPJoinStats)
+	}
+	if got := failed.Status().Code; got != codes.Error {
+		t.Errorf("%s status = %v, want error", DataAPJoinStats, got)
+	}
+
+	fetchIDs := make(map[string]bool, len(fetches))
+	for _, span := range fetches {
+		fetchIDs[span.SpanContext().SpanID().String()] = true
+	}
+	raw := spansNamed(spans, spanRawRead)
+	if len(raw) == 0 {
+		t.Fatalf("got no %s spans, want one per raw read", spanRawRead)
+	}
+	for _, span := range raw {
+		if !fetchIDs[span.Parent().SpanID().String()] {
+			t.Errorf("%s span for %s is not a child of a fetch span", spanRawRead,
+				spanAttribute(span, attrPath).AsString())
+		}
+	}
+}
+
+// TestReadEffective_TracesFallback gives the re-read after a rejected request its own
+// span, so the second round trip shows up as such rather than as a slow fetch.
+func TestReadEffective_TracesFallback(t *testing.T) {
+	t.Parallel()
+
+	rec := newQueryRecorder("wlan-cfg-entries")
+	server := rec.server()
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	recorder := recordSpans(ds)
+	ds.refresher.refreshOnce(context.Background())
+
+	fallbacks := spansNamed(recorder.Ended(), spanFallback)
+	if len(fallbacks) != 1 {
+		t.Fatalf("got %d %s spans, want 1", len(fallbacks), spanFallback)
+	}
+	if got := spanAttribute(fallbacks[0], attrStatus).AsInt64(); got != 400 {
+		t.Errorf("%s status attribute = %d, want 400", spanFallback, got)
+	}
+	if got := fallbacks[0].Status().Code; got != codes.Unset {
+		t.Errorf("%s status = %v, want unset after the plain re-read succeeded", spanFallback, got)
+	}
+}
+
+// recordSpans makes the data source's refreshes trace into a recorder.
+func recordSpans(ds *dataSource) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ds.refresher.tracer = provider.Tracer(tracerName)
+	return recorder
+}
+
+func spansNamed(spans []sdktrace.ReadOnlySpan, name string) []sdktrace.ReadOnlySpan {
+	var named []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Name() == name {
+			named = append(named, span)
+		}
+	}
+	return named
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
@@ -0,0 +1,78 @@
+// Package wnc provides WNC data access and caching.
+// This file holds what lets a push mode or a one-shot command drive refreshes without
+// a scrape.
+package wnc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errRefreshInProgress is returned by Refresh when a background refresh is running.
+var errRefreshInProgress = errors.New("WNC data refresh already in progress")
+
+// RefreshWatcher is implemented by data sources that can be refreshed without a scrape
+// and announce each refresh that succeeded. A scrape is what normally starts a refresh,
+// so a process that pushes its metrics instead needs both.
+type RefreshWatcher interface {
+	// Poke starts a background refresh when one is due, exactly as a scrape would.
+	// It never blocks.
+	Poke()
+	// Refreshed receives the start time of each successful refresh, the time the
+	// snapshot it published describes. A refresh that succeeds while the previous
+	// announcement is still unread replaces it, so a slow reader sees the latest.
+	Refreshed() <-chan time.Time
+}
+
+// SyncRefresher is implemented by data sources that can run a refresh in the caller's
+// goroutine, for a one-shot command that has no scrape to start one and exits once it
+// has the data.
+type SyncRefresher interface {
+	// Refresh runs a refresh when one is due and returns once it completed, with the
+	// error that failed it. Data types that failed while others succeeded do not fail
+	// it: they are withheld from the snapshot as a scrape would withhold them.
+	Refresh(ctx context.Context) error
+}
+
+// Refresh implements SyncRefresher.
+func (s *dataSource) Refresh(ctx context.Context) error {
+	if !s.refresher.inflight.CompareAndSwap(false, true) {
+		return errRefreshInProgress
+	}
+	if err := s.refresher.refreshOnce(ctx); err != nil {
+		return err
+	}
+	// A refresh that was not due leaves the snapshot as it was, which is none when
+	// every refresh so far failed.
+	if s.refresher.cur.Load() == nil {
+		return errNoSnapshot
+	}
+	return nil
+}
+
+// Poke implements RefreshWatcher.
+func (s *dataSource) Poke() {
+	s.refresher.get()
+}
+
+// Refreshed implements RefreshWatcher.
+func (s *dataSource) Refreshed() <-chan time.Time {
+	return s.refreshed
+}
+
+// announce publishes a successful refresh to Refreshed. Only the refresh goroutine
+// sends, and refreshes never overlap, so draining an unread announcement before
+// sending cannot lose the newer one.
+func (s *dataSource) announce() {
+	data := s.refresher.cur.Load()
+	if data == nil {
+		return
+	}
+
+	select {
+	case <-s.refreshed:
+	default:
+	}
+	s.refreshed <- data.RefreshedAt
+}
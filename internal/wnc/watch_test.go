@@ -0,0 +1,99 @@
+package wnc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestDataSource_RefreshedAnnouncesSuccessOnly pins what a push loop is driven by: a
+// successful refresh announces the time its snapshot describes, a failed one announces
+// nothing, and a reader that fell behind sees only the latest.
+func TestDataSource_RefreshedAnnouncesSuccessOnly(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(failing(dataTypeNames...))
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	ds.refresher.refreshOnce(context.Background())
+
+	select {
+	case at := <-ds.Refreshed():
+		t.Errorf("Refreshed() announced %v after a failed refresh, want nothing", at)
+	default:
+	}
+
+	ok := newMockWNCServer(mockServerConfig{})
+	defer ok.Close()
+
+	ds = newTestDataSource(t, ok.URL, time.Minute)
+	for range 2 {
+		ds.refresher.nextAt.Store(0)
+		ds.refresher.refreshOnce(context.Background())
+	}
+	suppressBackgroundRefresh(ds)
+
+	data, err := ds.GetCachedData(context.Background())
+	if err != nil {
+		t.Fatalf("GetCachedData() error = %v, want nil", err)
+	}
+
+	select {
+	case at := <-ds.Refreshed():
+		if !at.Equal(data.RefreshedAt) {
+			t.Errorf("Refreshed() = %v, want the latest snapshot's %v", at, data.RefreshedAt)
+		}
+	default:
+		t.Fatal("Refreshed() announced nothing after a successful refresh")
+	}
+
+	select {
+	case at := <-ds.Refreshed():
+		t.Errorf("Refreshed() announced %v a second time, want the two refreshes coalesced", at)
+	default:
+	}
+}
+
+// TestDataSource_PokeStartsDueRefresh covers a push mode with no scrape: the poke is
+// what starts the refresh.
+func TestDataSource_PokeStartsDueRefresh(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(mockServerConfig{})
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	ds.Poke()
+
+	select {
+	case <-ds.Refreshed():
+	case <-time.After(10 * time.Second):
+		t.Fatal("Refreshed() announced nothing after Poke(), want the refresh it started")
+	}
+}
+
+// TestDataSource_RefreshRunsSynchronously covers a one-shot command: Refresh returns
+// once the snapshot is published, and reports a refresh that reached nothing.
+func TestDataSource_RefreshRunsSynchronously(t *testing.T) {
+	t.Parallel()
+
+	server := newMockWNCServer(mockServerConfig{})
+	defer server.Close()
+
+	ds := newTestDataSource(t, server.URL, time.Minute)
+	if err := ds.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v, want nil", err)
+	}
+	if _, err := ds.GetCachedData(context.Background()); err != nil {
+		t.Errorf("GetCachedData() after Refresh() error = %v, want the published snapshot", err)
+	}
+
+	down := newMockWNCServer(failing(dataTypeNames...))
+	defer down.Close()
+
+	ds = newTestDataSource(t, down.URL, time.Minute)
+	if err := ds.Refresh(context.Background()); err == nil {
+		t.Error("Refresh() error = nil with every data type failing, want the refresh error")
+	}
+}
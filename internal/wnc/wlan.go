@@ -15,6 +15,8 @@ type WLANSource interface {
 	ListPolicies(ctx context.Context) ([]wlan.WlanPolicy, error)
 	ListPolicyListEntries(ctx context.Context) ([]wlan.PolicyListEntry, error)
 	ListClientStats(ctx context.Context) ([]ap.WlanClientStats, error)
+	ListAppStats(ctx context.Context) ([]WLANAppStats, error)
+	ListATFStats(ctx context.Context) ([]RadioATFStats, error)
 }
 
 // wlanSource implements WLANSource using SharedDataSource for caching.
@@ -31,7 +33,7 @@ func NewWLANSource(sharedDataSource DataSource) WLANSource {
 
 // ListConfigEntries retrieves WLAN configuration entries via SharedDataSource (cached).
 func (s *wlanSource) ListConfigEntries(ctx context.Context) ([]wlan.WlanCfgEntry, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataWLANCfgEntries)
+	data, err := snapshot(ctx, s.sharedDataSource, DataWLANCfgEntries)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +42,7 @@ func (s *wlanSource) ListConfigEntries(ctx context.Context) ([]wlan.WlanCfgEntry
 
 // ListPolicies retrieves WLAN policies via SharedDataSource (cached).
 func (s *wlanSource) ListPolicies(ctx context.Context) ([]wlan.WlanPolicy, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataWLANPolicies)
+	data, err := snapshot(ctx, s.sharedDataSource, DataWLANPolicies)
 	if err != nil {
 		return nil, err
 	}
@@ -51,16 +53,36 @@ func (s *wlanSource) ListPolicies(ctx context.Context) ([]wlan.WlanPolicy, error
 // (cached). The controller keeps them in the AP global operational subtree, but they
 // are keyed by WLAN and read by the WLAN collector alone.
 func (s *wlanSource) ListClientStats(ctx context.Context) ([]ap.WlanClientStats, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataWLANClientStats)
+	data, err := snapshot(ctx, s.sharedDataSource, DataWLANClientStats)
 	if err != nil {
 		return nil, err
 	}
 	return data.WLANClientStats, nil
 }
 
+// ListAppStats retrieves the per-WLAN application statistics AVC classifies via
+// SharedDataSource (cached).
+func (s *wlanSource) ListAppStats(ctx context.Context) ([]WLANAppStats, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataWLANAVCStats)
+	if err != nil {
+		return nil, err
+	}
+	return data.WLANAppStats, nil
+}
+
+// ListATFStats retrieves the Air Time Fairness statistics per radio, WLAN and policy via
+// SharedDataSource (cached).
+func (s *wlanSource) ListATFStats(ctx context.Context) ([]RadioATFStats, error) {
+	data, err := snapshot(ctx, s.sharedDataSource, DataAPRadioATFStats)
+	if err != nil {
+		return nil, err
+	}
+	return data.RadioATFStats, nil
+}
+
 // ListPolicyListEntries retrieves policy list entries via SharedDataSource (cached).
 func (s *wlanSource) ListPolicyListEntries(ctx context.Context) ([]wlan.PolicyListEntry, error) {
-	data, err := snapshot(ctx, s.sharedDataSource, dataWLANPolicyListEntries)
+	data, err := snapshot(ctx, s.sharedDataSource, DataWLANPolicyListEntries)
 	if err != nil {
 		return nil, err
 	}
@@ -366,3 +366,64 @@ func TestWLANSource_ListPolicyListEntries(t *testing.T) {
 		})
 	}
 }
+
+// TestWLANSource_ListAppStats covers the AVC list, which must pass the per-application
+// records through untouched and fail with the fetch error its data type recorded.
+func TestWLANSource_ListAppStats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	mock := &mockWLANDataSource{
+		data: &WNCDataCache{
+			WLANAppStats: []WLANAppStats{
+				{WlanID: 1, AppName: "ms-teams", Direction: "ingress", Bytes: "1048576", Packets: "900"},
+				{WlanID: 1, AppName: "ms-teams", Direction: "egress", Bytes: "524288", Packets: "450"},
+			},
+		},
+	}
+
+	stats, err := NewWLANSource(mock).ListAppStats(ctx)
+	if err != nil {
+		t.Fatalf("ListAppStats() error = %v, want nil", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("ListAppStats() returned %d records, want 2", len(stats))
+	}
+	if stats[1].Direction != "egress" || stats[1].Bytes != "524288" {
+		t.Errorf("ListAppStats()[1] = %+v, want the egress record as fetched", stats[1])
+	}
+
+	mock.data.FetchErrors = map[string]error{DataWLANAVCStats: errors.New("fetch failed")}
+	if _, err := NewWLANSource(mock).ListAppStats(ctx); err == nil {
+		t.Error("ListAppStats() error = nil with the AVC list failed, want the recorded fetch error")
+	}
+}
+
+// TestWLANSource_ListATFStats covers the ATF list, which must pass the per-policy records
+// through untouched and fail with the fetch error its data type recorded.
+func TestWLANSource_ListATFStats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	mock := &mockWLANDataSource{
+		data: &WNCDataCache{
+			RadioATFStats: []RadioATFStats{{
+				WtpMAC: "aa:bb:cc:11:22:80", RadioSlotID: 1, WlanID: 1, PolicyName: "voice-first",
+				AirtimeAllocation: "40", AirtimeUsed: "1250000",
+			}},
+		},
+	}
+
+	stats, err := NewWLANSource(mock).ListATFStats(ctx)
+	if err != nil {
+		t.Fatalf("ListATFStats() error = %v, want nil", err)
+	}
+	if len(stats) != 1 || stats[0].PolicyName != "voice-first" {
+		t.Fatalf("ListATFStats() = %+v, want the one record as fetched", stats)
+	}
+
+	mock.data.FetchErrors = map[string]error{DataAPRadioATFStats: errors.New("fetch failed")}
+	if _, err := NewWLANSource(mock).ListATFStats(ctx); err == nil {
+		t.Error("ListATFStats() error = nil with the ATF list failed, want the recorded fetch error")
+	}
+}
@@ -3,13 +3,15 @@ package wnc
 
 import (
 	"fmt"
+	"net/http"
 
 	wnc "github.com/umatare5/cisco-ios-xe-wireless-go"
 	"github.com/umatare5/cisco-wnc-exporter/internal/config"
 )
 
-// createWNCClient creates a configured WNC client for REST API access.
-func createWNCClient(cfg config.WNC) *wnc.Client {
+// createWNCClient creates a configured WNC client for REST API access. Its requests
+// go through a transport that records each of them in the meter.
+func createWNCClient(cfg config.WNC, meter *requestMeter) *wnc.Client {
 	options := []wnc.Option{
 		wnc.WithTimeout(cfg.Timeout),
 		wnc.WithInsecureSkipVerify(cfg.TLSSkipVerify),
@@ -21,5 +23,18 @@ func createWNCClient(cfg config.WNC) *wnc.Client {
 		panic(fmt.Sprintf("Failed to create WNC client: %v", err))
 	}
 
+	meterTransport(wncClient.Core().HTTP, meter)
 	return wncClient
 }
+
+// meterTransport wraps the transport of the SDK's HTTP client in one that records each
+// request in the meter. The SDK's own transport is kept as the one requests go through,
+// so its TLS, timeout and connection settings apply as they would unmetered. A client
+// without a transport uses http.DefaultTransport, as net/http does.
+func meterTransport(client *http.Client, meter *requestMeter) {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &meteredTransport{base: base, meter: meter}
+}